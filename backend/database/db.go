@@ -1,6 +1,8 @@
 package database
 
 import (
+	"context"
+
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
@@ -8,4 +10,12 @@ type DB interface {
 	Connect() error
 	Disconnect() error
 	GetClient() *mongo.Client
+	Transactor
+}
+
+// Transactor ejecuta fn dentro de una transacción cuando el servidor lo
+// permite. Las operaciones de repositorio que reciban el ctx de fn quedan
+// dentro de la misma transacción.
+type Transactor interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
 }
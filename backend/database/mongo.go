@@ -4,14 +4,19 @@ import (
 	"context"
 	"log"
 	"os"
+	"sync"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type MongoDB struct {
 	Client *mongo.Client
+
+	transactionsOnce      sync.Once
+	transactionsSupported bool
 }
 
 func NewMongoDB() *MongoDB {
@@ -63,3 +68,39 @@ func (mongoDB *MongoDB) GetClient() *mongo.Client {
 func (mongoDB *MongoDB) Disconnect() error {
 	return mongoDB.Client.Disconnect(context.Background())
 }
+
+// WithTransaction corre fn en una transacción si el servidor es un replica set
+// o un mongos (Atlas siempre lo es). En un mongod standalone, como el de
+// desarrollo local, las transacciones no existen y fn se ejecuta sin ella.
+func (mongoDB *MongoDB) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if !mongoDB.supportsTransactions(ctx) {
+		return fn(ctx)
+	}
+
+	session, err := mongoDB.Client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessionCtx)
+	})
+	return err
+}
+
+func (mongoDB *MongoDB) supportsTransactions(ctx context.Context) bool {
+	mongoDB.transactionsOnce.Do(func() {
+		var hello struct {
+			SetName string `bson:"setName"`
+			Msg     string `bson:"msg"`
+		}
+		err := mongoDB.Client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello)
+		if err != nil {
+			log.Println("⚠️ Aviso: No se pudo consultar el tipo de servidor, se desactivan las transacciones:", err)
+			return
+		}
+		mongoDB.transactionsSupported = hello.SetName != "" || hello.Msg == "isdbgrid"
+	})
+	return mongoDB.transactionsSupported
+}
@@ -0,0 +1,205 @@
+// Package dietary clasifica ingredientes por alérgenos y dietas a partir de
+// su nombre. Es una heurística por palabras clave: un ingrediente que no
+// reconoce queda sin clasificar, y mientras una receta tenga alguno no se le
+// asigna ninguna dieta. Las etiquetas orientan la búsqueda pero no
+// reemplazan leer la receta.
+package dietary
+
+import (
+	"burned/backend/models"
+	"burned/backend/shopping"
+	"burned/backend/units"
+	"strings"
+)
+
+// Alérgenos que se detectan.
+const (
+	Gluten    = "gluten"
+	Dairy     = "dairy"
+	Egg       = "egg"
+	Nuts      = "nuts" // frutos secos de árbol
+	Peanuts   = "peanuts"
+	Soy       = "soy"
+	Fish      = "fish"
+	Shellfish = "shellfish"
+	Sesame    = "sesame"
+)
+
+// Dietas que se deducen de los alérgenos y del origen de los ingredientes.
+const (
+	Vegan       = "vegan"
+	Vegetarian  = "vegetarian"
+	Pescatarian = "pescatarian"
+	GlutenFree  = "gluten_free"
+	DairyFree   = "dairy_free"
+	EggFree     = "egg_free"
+	NutFree     = "nut_free"
+)
+
+// Categorías que no son alérgenos pero definen las dietas.
+const (
+	meat  = "meat"  // carne, aves, embutidos, gelatina
+	honey = "honey" // de origen animal sin ser carne
+)
+
+var Allergens = []string{Gluten, Dairy, Egg, Nuts, Peanuts, Soy, Fish, Shellfish, Sesame}
+
+var Diets = []string{Vegan, Vegetarian, Pescatarian, GlutenFree, DairyFree, EggFree, NutFree}
+
+// dietExcludes son las categorías que una receta no puede tener para entrar en cada dieta.
+var dietExcludes = map[string][]string{
+	Vegan:       {meat, Fish, Shellfish, Dairy, Egg, honey},
+	Vegetarian:  {meat, Fish, Shellfish},
+	Pescatarian: {meat},
+	GlutenFree:  {Gluten},
+	DairyFree:   {Dairy},
+	EggFree:     {Egg},
+	NutFree:     {Nuts, Peanuts},
+}
+
+// maxPhraseWords es el largo de la frase clave más larga del catálogo
+const maxPhraseWords = 4
+
+func ValidAllergen(allergen string) bool {
+	return contains(Allergens, allergen)
+}
+
+func ValidDiet(diet string) bool {
+	return contains(Diets, diet)
+}
+
+// Categories devuelve los alérgenos y demás categorías de un ingrediente.
+// Las frases más largas tienen prioridad sobre las palabras que contienen:
+// "leche de almendras" es nuts y no dairy, "nuez moscada" no es un fruto
+// seco. Después se quitan las categorías que el nombre niega ("sin gluten",
+// "vegano").
+func Categories(name string) []string {
+	categories, _ := categorize(name)
+	return categories
+}
+
+// categorize es Categories e indica además si alguna palabra del nombre
+// está en el catálogo. Un nombre sin ninguna no se puede clasificar.
+func categorize(name string) ([]string, bool) {
+	key := shopping.Key(name)
+	words := strings.Fields(key)
+	covered := make([]bool, len(words))
+	known := false
+	found := map[string]bool{}
+	for size := maxPhraseWords; size >= 1; size-- {
+		for start := 0; start+size <= len(words); start++ {
+			categories, ok := keywordCategories[strings.Join(words[start:start+size], " ")]
+			if !ok || anyCovered(covered[start:start+size]) {
+				continue
+			}
+			known = true
+			for i := start; i < start+size; i++ {
+				covered[i] = true
+			}
+			for _, category := range categories {
+				found[category] = true
+			}
+		}
+	}
+	padded := " " + key + " "
+	for phrase, categories := range freeOfCategories {
+		if strings.Contains(padded, " "+phrase+" ") {
+			for _, category := range categories {
+				delete(found, category)
+			}
+		}
+	}
+
+	result := []string{}
+	for _, category := range append(append([]string{}, Allergens...), meat, honey) {
+		if found[category] {
+			result = append(result, category)
+		}
+	}
+	return result, known
+}
+
+// Classify devuelve los alérgenos de una receta a partir de los nombres de
+// sus ingredientes, las dietas que cumple, en el orden de Allergens y Diets,
+// y los nombres que no pudo clasificar. Los opcionales cuentan: quien tiene
+// una alergia necesita saberlo igual. Lo que va "a gusto" (sal, pimienta)
+// aporta sus alérgenos si los reconoce, pero no deja la receta sin dietas.
+func Classify(ingredients []models.Ingredient) (allergens []string, diets []string, unclassified []string) {
+	found := map[string]bool{}
+	unclassified = []string{}
+	for _, ingredient := range ingredients {
+		if strings.TrimSpace(ingredient.Name) == "" {
+			continue
+		}
+		categories, known := categorize(ingredient.Name)
+		for _, category := range categories {
+			found[category] = true
+		}
+		if !known && ingredient.Unit != units.CodeToTaste {
+			unclassified = append(unclassified, ingredient.Name)
+		}
+	}
+	if len(unclassified) > 0 {
+		return allergensOf(found), []string{}, unclassified
+	}
+	return allergensOf(found), dietsOf(found), unclassified
+}
+
+// DietsOf devuelve las dietas que admiten un ingrediente; ninguna si no lo
+// reconoce.
+func DietsOf(name string) []string {
+	categories, known := categorize(name)
+	if !known {
+		return []string{}
+	}
+	found := map[string]bool{}
+	for _, category := range categories {
+		found[category] = true
+	}
+	return dietsOf(found)
+}
+
+func allergensOf(found map[string]bool) []string {
+	allergens := []string{}
+	for _, allergen := range Allergens {
+		if found[allergen] {
+			allergens = append(allergens, allergen)
+		}
+	}
+	return allergens
+}
+
+func dietsOf(found map[string]bool) []string {
+	diets := []string{}
+	for _, diet := range Diets {
+		suits := true
+		for _, category := range dietExcludes[diet] {
+			if found[category] {
+				suits = false
+				break
+			}
+		}
+		if suits {
+			diets = append(diets, diet)
+		}
+	}
+	return diets
+}
+
+func anyCovered(covered []bool) bool {
+	for _, c := range covered {
+		if c {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package dietary
+
+import (
+	"burned/backend/models"
+	"burned/backend/units"
+	"reflect"
+	"testing"
+)
+
+func TestClassifyKnownDishes(t *testing.T) {
+	tests := []struct {
+		name      string
+		allergens []string
+		notDiets  []string
+	}{
+		{"hamburguesas", []string{Gluten}, []string{Vegan, Vegetarian, GlutenFree}},
+		{"pizza", []string{Gluten, Dairy}, []string{Vegan, GlutenFree, DairyFree}},
+		{"prepizza", []string{Gluten}, []string{GlutenFree}},
+		{"gnocchi", []string{Gluten}, []string{GlutenFree}},
+		{"ñoquis", []string{Gluten}, []string{GlutenFree}},
+		{"sorrentinos", []string{Gluten, Dairy, Egg}, []string{Vegan, GlutenFree}},
+		{"canelones", []string{Gluten, Dairy, Egg}, []string{Vegan, GlutenFree}},
+		{"milanesas de ternera", []string{Gluten, Egg}, []string{Vegan, Vegetarian, GlutenFree}},
+		{"provolone", []string{Dairy}, []string{Vegan, DairyFree}},
+		{"salsa bechamel", []string{Gluten, Dairy}, []string{Vegan, DairyFree, GlutenFree}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			allergens, diets, unclassified := Classify([]models.Ingredient{{Name: test.name}})
+			if !reflect.DeepEqual(allergens, test.allergens) {
+				t.Errorf("allergens = %v, want %v", allergens, test.allergens)
+			}
+			if len(unclassified) != 0 {
+				t.Errorf("unclassified = %v, want none", unclassified)
+			}
+			for _, diet := range test.notDiets {
+				if contains(diets, diet) {
+					t.Errorf("diets = %v, must not include %s", diets, diet)
+				}
+			}
+		})
+	}
+}
+
+func TestClassifyUnknownIngredientRemovesDiets(t *testing.T) {
+	ingredients := []models.Ingredient{
+		{Name: "tomates perita", Quantity: 3, Unit: "unit"},
+		{Name: "tofu", Quantity: 200, Unit: "g"},
+		{Name: "relleno misterioso", Quantity: 1, Unit: "cup"},
+	}
+	allergens, diets, unclassified := Classify(ingredients)
+	if !reflect.DeepEqual(allergens, []string{Soy}) {
+		t.Errorf("allergens = %v, want [soy]", allergens)
+	}
+	if len(diets) != 0 {
+		t.Errorf("diets = %v, want none while an ingredient is unclassified", diets)
+	}
+	if !reflect.DeepEqual(unclassified, []string{"relleno misterioso"}) {
+		t.Errorf("unclassified = %v, want [relleno misterioso]", unclassified)
+	}
+}
+
+func TestClassifyIgnoresUnknownToTaste(t *testing.T) {
+	ingredients := []models.Ingredient{
+		{Name: "garbanzos", Quantity: 400, Unit: "g"},
+		{Name: "aceite de oliva", Quantity: 2, Unit: "tbsp"},
+		{Name: "hierbas provenzales", Unit: units.CodeToTaste},
+	}
+	_, diets, unclassified := Classify(ingredients)
+	if len(unclassified) != 0 {
+		t.Errorf("unclassified = %v, want none", unclassified)
+	}
+	if !reflect.DeepEqual(diets, Diets) {
+		t.Errorf("diets = %v, want %v", diets, Diets)
+	}
+}
+
+func TestClassifyFullyKnownRecipe(t *testing.T) {
+	ingredients := []models.Ingredient{
+		{Name: "harina 0000", Quantity: 500, Unit: "g"},
+		{Name: "agua", Quantity: 300, Unit: "ml"},
+		{Name: "levadura", Quantity: 10, Unit: "g"},
+		{Name: "sal", Quantity: 1, Unit: "tsp"},
+	}
+	allergens, diets, _ := Classify(ingredients)
+	if !reflect.DeepEqual(allergens, []string{Gluten}) {
+		t.Errorf("allergens = %v, want [gluten]", allergens)
+	}
+	want := []string{Vegan, Vegetarian, Pescatarian, DairyFree, EggFree, NutFree}
+	if !reflect.DeepEqual(diets, want) {
+		t.Errorf("diets = %v, want %v", diets, want)
+	}
+}
+
+func TestDietsOfUnknownIngredient(t *testing.T) {
+	if diets := DietsOf("relleno misterioso"); len(diets) != 0 {
+		t.Errorf("DietsOf = %v, want none", diets)
+	}
+	if diets := DietsOf("queso vegano"); !contains(diets, Vegan) {
+		t.Errorf("DietsOf(queso vegano) = %v, want vegan", diets)
+	}
+}
@@ -0,0 +1,189 @@
+package dietary
+
+import "burned/backend/shopping"
+
+// categoryKeywords son los ingredientes que aportan cada categoría, en
+// español e inglés. Una palabra puede estar en varias ("pesto").
+var categoryKeywords = map[string][]string{
+	Gluten: {
+		"harina", "flour", "trigo", "wheat", "pan", "bread", "baguette", "pan rallado", "breadcrumb", "rebozador",
+		"fideo", "pasta", "spaghetti", "tallarin", "noodle", "raviol", "noqui", "lasagna", "lasana", "cuscus",
+		"couscous", "semola", "semolina", "bulgur", "avena", "oat", "cebada", "barley", "centeno", "rye",
+		"espelta", "spelt", "seitan", "malta", "malt", "cerveza", "beer", "galletita", "galleta", "cookie",
+		"cracker", "bizcochuelo", "tapa de empanada", "masa", "dough", "pionono", "medialuna",
+		"croissant", "pretzel", "salsa de soja", "soy sauce", "pizza", "prepizza", "pizzeta", "fugazza",
+		"focaccia", "gnocchi", "ñoquis", "sorrentino", "capeletti", "canelon", "cannelloni", "empanada", "tarta",
+		"milanesa", "hamburguesa", "burger", "nugget", "bechamel", "bechamel sauce", "panqueque", "crepe",
+		"brioche", "panko", "crouton", "torta", "budin", "alfajor", "wrap", "premezcla",
+	},
+	Dairy: {
+		"leche", "milk", "manteca", "mantequilla", "butter", "ghee", "queso", "cheese", "crema", "cream", "nata",
+		"yogur", "yogurt", "ricota", "ricotta", "mozzarella", "muzzarella", "parmesano", "parmesan", "reggianito",
+		"dulce de leche", "buttermilk", "suero de leche", "whey", "helado", "ice cream", "chocolate con leche",
+		"milk chocolate", "chocolate blanco", "white chocolate", "pesto", "nutella", "crema pastelera",
+		"provolone", "provoleta", "cheddar", "gruyere", "roquefort", "fontina", "sardo", "cuartirolo",
+		"brie", "feta", "mascarpone", "pizza", "sorrentino", "canelon", "cannelloni", "bechamel", "panqueque",
+		"crepe", "brioche",
+	},
+	Egg: {
+		"huevo", "egg", "clara", "yema", "mayonesa", "mayonnaise", "merengue", "meringue", "crema pastelera",
+		"milanesa", "sorrentino", "canelon", "cannelloni", "panqueque", "crepe", "brioche", "torta", "budin",
+	},
+	Nuts: {
+		"nuez", "walnut", "almendra", "almond", "avellana", "hazelnut", "castana de caju", "caju", "cashew",
+		"pistacho", "pistachio", "pecan", "macadamia", "castana", "chestnut", "nut", "fruto seco", "praline",
+		"pesto", "nutella", "mazapan", "marzipan",
+	},
+	Peanuts: {
+		"mani", "cacahuate", "cacahuete", "peanut",
+	},
+	Soy: {
+		"soja", "soya", "soy", "tofu", "edamame", "miso", "tempeh", "tamari", "salsa de soja", "soy sauce",
+	},
+	Fish: {
+		"pescado", "fish", "salmon", "atun", "tuna", "merluza", "hake", "bacalao", "cod", "anchoa", "anchovy",
+		"sardina", "sardine", "trucha", "trout", "abadejo", "lenguado", "sole", "caballa", "mackerel", "brotola",
+		"pejerrey", "surubi", "salsa inglesa", "worcestershire", "salsa de pescado", "fish sauce",
+	},
+	Shellfish: {
+		"camaron", "langostino", "gamba", "shrimp", "prawn", "calamar", "squid", "mejillon", "mussel", "almeja",
+		"clam", "pulpo", "octopus", "cangrejo", "crab", "langosta", "lobster", "vieira", "scallop", "ostra",
+		"oyster", "salsa de ostra", "oyster sauce", "mariscos", "seafood", "surimi",
+	},
+	Sesame: {
+		"sesamo", "ajonjoli", "sesame", "tahini", "tahina",
+	},
+	meat: {
+		"carne", "meat", "beef", "pollo", "chicken", "cerdo", "pork", "jamon", "ham", "panceta", "tocino",
+		"bacon", "chorizo", "salchicha", "sausage", "morcilla", "cordero", "lamb", "pavo", "turkey", "pato",
+		"duck", "conejo", "rabbit", "ternera", "veal", "bife", "steak", "lomo", "vacio", "matambre",
+		"osobuco", "peceto", "nalga", "cuadril", "entrana", "molleja", "higado", "liver", "mondongo", "costilla",
+		"pechuga", "muslo", "salame", "salami", "prosciutto", "pepperoni", "longaniza", "grasa", "lard", "gelatina", "gelatin", "caldo de carne", "caldo de pollo",
+		"hamburguesa", "burger", "nugget",
+	},
+	honey: {
+		"miel", "honey",
+	},
+}
+
+// neutralKeywords son ingredientes comunes que no aportan ninguna categoría.
+// Están para que la receta quede clasificada: un nombre que no aparece en
+// ninguna lista deja la receta sin dietas.
+var neutralKeywords = []string{
+	"agua", "water", "hielo", "ice", "sal", "salt", "pimienta", "pepper", "azucar", "sugar", "edulcorante",
+	"stevia", "aceite", "oil", "oliva", "olive", "aceituna", "vinagre", "vinegar", "vino", "wine",
+	"ajo", "garlic", "cebolla", "onion", "verdeo", "puerro", "leek", "tomate", "tomato", "papa", "potato",
+	"batata", "sweet potato", "zanahoria", "carrot", "zapallo", "calabaza", "pumpkin", "squash", "zapallito",
+	"zucchini", "calabacin", "berenjena", "eggplant", "morron", "pimiento", "bell pepper", "aji", "chile",
+	"chili", "jalapeno", "lechuga", "lettuce", "espinaca", "spinach", "acelga", "chard", "rucula", "arugula",
+	"repollo", "cabbage", "brocoli", "broccoli", "coliflor", "cauliflower", "choclo", "maiz", "corn",
+	"arveja", "pea", "chaucha", "green bean", "lenteja", "lentil", "garbanzo", "chickpea", "poroto",
+	"frijol", "bean", "arroz", "rice", "quinoa", "hongo", "champinon", "mushroom", "apio", "celery",
+	"pepino", "cucumber", "palta", "aguacate", "avocado", "remolacha", "beet", "rabanito", "radish",
+	"mandioca", "yuca", "cassava", "limon", "lemon", "lima", "lime", "naranja", "orange", "manzana", "apple",
+	"pera", "pear", "banana", "platano", "frutilla", "fresa", "strawberry", "durazno", "peach", "uva",
+	"grape", "anana", "pineapple", "mango", "kiwi", "arandano", "blueberry", "frambuesa", "raspberry",
+	"ciruela", "plum", "pasa de uva", "raisin", "coco", "coconut", "alcaparra", "caper", "perejil",
+	"parsley", "cilantro", "albahaca", "basil", "oregano", "tomillo", "thyme", "romero", "rosemary",
+	"laurel", "bay leaf", "menta", "mint", "comino", "cumin", "pimenton", "paprika", "canela", "cinnamon",
+	"clavo de olor", "clove", "jengibre", "ginger", "curcuma", "turmeric", "curry", "vainilla", "vanilla",
+	"cacao", "cocoa", "levadura", "yeast", "polvo de hornear", "baking powder", "bicarbonato",
+	"baking soda", "maicena", "fecula", "almidon", "starch", "cornstarch", "mostaza", "mustard", "ketchup",
+	"semilla", "seed", "lino", "linaza", "flaxseed", "chia", "aquafaba", "polenta", "jarabe de arce",
+	"maple syrup",
+}
+
+// phraseCategories pisan lo que dirían sus palabras por separado. Una lista
+// vacía marca la frase como neutra.
+var phraseCategories = map[string][]string{
+	"nuez moscada":        {},
+	"nutmeg":              {},
+	"leche de coco":       {},
+	"coconut milk":        {},
+	"crema de coco":       {},
+	"coconut cream":       {},
+	"leche de arroz":      {},
+	"rice milk":           {},
+	"leche de almendra":   {Nuts},
+	"almond milk":         {Nuts},
+	"leche de soja":       {Soy},
+	"soy milk":            {Soy},
+	"leche de avena":      {Gluten},
+	"oat milk":            {Gluten},
+	"manteca de cacao":    {},
+	"cocoa butter":        {},
+	"manteca de mani":     {Peanuts},
+	"mantequilla de mani": {Peanuts},
+	"crema de mani":       {Peanuts},
+	"peanut butter":       {Peanuts},
+	"almond butter":       {Nuts},
+	"cremor tartaro":      {},
+	"cream of tartar":     {},
+	"harina de arroz":     {},
+	"rice flour":          {},
+	"harina de maiz":      {},
+	"corn flour":          {},
+	"harina de garbanzo":  {},
+	"chickpea flour":      {},
+	"harina de mandioca":  {},
+	"harina de coco":      {},
+	"coconut flour":       {},
+	"harina de almendra":  {Nuts},
+	"almond flour":        {Nuts},
+	"fideo de arroz":      {},
+	"rice noodle":         {},
+	"pan de arroz":        {},
+	"carne de soja":       {Soy},
+	"caldo de verdura":    {},
+	"salsa de tomate":     {},
+	"pasta de mani":       {Peanuts},
+	"pasta de sesamo":     {Sesame},
+	"pasta de tomate":     {},
+	"tomato paste":        {},
+	"pasta de curry":      {},
+}
+
+// freeOf son las frases que niegan categorías en el nombre del ingrediente:
+// "pan sin gluten", "queso vegano", "manteca vegetal".
+var freeOf = map[string][]string{
+	"sin gluten":      {Gluten},
+	"sin tacc":        {Gluten},
+	"gluten free":     {Gluten},
+	"libre de gluten": {Gluten},
+	"sin huevo":       {Egg},
+	"egg free":        {Egg},
+	"vegano":          {meat, Fish, Shellfish, Dairy, Egg, honey},
+	"vegana":          {meat, Fish, Shellfish, Dairy, Egg, honey},
+	"vegan":           {meat, Fish, Shellfish, Dairy, Egg, honey},
+	"vegetal":         {meat, Fish, Shellfish, Dairy, Egg, honey},
+	"plant based":     {meat, Fish, Shellfish, Dairy, Egg, honey},
+}
+
+// keywordCategories indexa las frases por su shopping.Key.
+var keywordCategories = func() map[string][]string {
+	index := map[string][]string{}
+	for category, keywords := range categoryKeywords {
+		for _, keyword := range keywords {
+			key := shopping.Key(keyword)
+			index[key] = append(index[key], category)
+		}
+	}
+	for _, keyword := range neutralKeywords {
+		if key := shopping.Key(keyword); index[key] == nil {
+			index[key] = []string{}
+		}
+	}
+	for phrase, categories := range phraseCategories {
+		index[shopping.Key(phrase)] = categories
+	}
+	return index
+}()
+
+// freeOfCategories es freeOf indexado por shopping.Key.
+var freeOfCategories = func() map[string][]string {
+	index := map[string][]string{}
+	for phrase, categories := range freeOf {
+		index[shopping.Key(phrase)] = categories
+	}
+	return index
+}()
@@ -0,0 +1,62 @@
+package dtos
+
+import (
+	"burned/backend/models"
+	"time"
+)
+
+type CollectionRequest struct {
+	Name        string `json:"name" binding:"required,min=1,max=80"`
+	Description string `json:"description" binding:"omitempty,max=500"`
+	CoverImage  string `json:"coverImage" binding:"omitempty,max=2000"` // URL (por ahora)
+	Visibility  string `json:"visibility" binding:"required,oneof=public private unlisted"`
+}
+
+type CollectionItemRequest struct {
+	RecipeID string `json:"recipeId" binding:"required"`
+}
+
+// CollectionOrderRequest trae todos los ids de la colección en el orden nuevo.
+type CollectionOrderRequest struct {
+	RecipeIDs []string `json:"recipeIds" binding:"required,max=500"`
+}
+
+type CollectionResponse struct {
+	ID          string                   `json:"id"`
+	UserID      string                   `json:"userId"`
+	UserName    string                   `json:"userName"`
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	CoverImage  string                   `json:"coverImage"`
+	Visibility  string                   `json:"visibility"`
+	IsDefault   bool                     `json:"isDefault"`
+	ShareToken  string                   `json:"shareToken,omitempty"` // solo para el dueño
+	ItemCount   int                      `json:"itemCount"`
+	Items       []CollectionItemResponse `json:"items,omitempty"` // solo en el detalle
+	CreatedAt   time.Time                `json:"createdAt"`
+	UpdatedAt   time.Time                `json:"updatedAt"`
+}
+
+type CollectionItemResponse struct {
+	AddedAt time.Time      `json:"addedAt"`
+	Recipe  RecipeResponse `json:"recipe"`
+}
+
+type ShareLinkResponse struct {
+	Token string `json:"token"`
+}
+
+func CollectionModelToResponse(model models.RecipeCollection) CollectionResponse {
+	return CollectionResponse{
+		ID:          model.ID.Hex(),
+		UserID:      model.UserID.Hex(),
+		Name:        model.Name,
+		Description: model.Description,
+		CoverImage:  model.CoverImage,
+		Visibility:  model.Visibility,
+		IsDefault:   model.IsDefault,
+		ItemCount:   len(model.Items),
+		CreatedAt:   model.CreatedAt,
+		UpdatedAt:   model.UpdatedAt,
+	}
+}
@@ -7,16 +7,40 @@ import (
 
 type CommentRequest struct {
 	RecipeID string `bson:"recipeId" json:"recipeId"`
+	ParentID string `bson:"parentId" json:"parentId"` // opcional: el comentario al que responde
 	Text     string `bson:"text" json:"text"`
 }
 
+type CommentUpdateRequest struct {
+	Text string `json:"text" binding:"required,max=2000"`
+}
+
 type CommentResponse struct {
-	ID        string    `bson:"_id,omitempty" json:"id"`
-	UserID    string    `bson:"userId" json:"userId"`
-	UserName  string    `bson:"userName" json:"userName"`
-	RecipeID  string    `bson:"recipeId" json:"recipeId"`
-	Text      string    `bson:"text" json:"text"`
-	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+	ID         string     `bson:"_id,omitempty" json:"id"`
+	UserID     string     `bson:"userId" json:"userId"`
+	UserName   string     `bson:"userName" json:"userName"`
+	RecipeID   string     `bson:"recipeId" json:"recipeId"`
+	ParentID   string     `bson:"parentId,omitempty" json:"parentId,omitempty"`
+	Depth      int        `bson:"depth" json:"depth"`
+	ReplyCount int        `bson:"replyCount" json:"replyCount"`
+	Deleted    bool       `bson:"deleted" json:"deleted"`
+	Hidden     bool       `bson:"hidden" json:"hidden"`
+	Text       string     `bson:"text" json:"text"`
+	CreatedAt  time.Time  `bson:"createdAt" json:"createdAt"`
+	EditedAt   *time.Time `bson:"editedAt,omitempty" json:"editedAt,omitempty"`
+}
+
+// CommentHistoryResponse es el comentario actual con sus versiones
+// anteriores, de la más nueva a la más vieja.
+type CommentHistoryResponse struct {
+	Comment  CommentResponse          `json:"comment"`
+	Versions []CommentVersionResponse `json:"versions"`
+}
+
+type CommentVersionResponse struct {
+	Text       string    `json:"text"`
+	WrittenAt  time.Time `json:"writtenAt"`
+	ReplacedAt time.Time `json:"replacedAt"`
 }
 
 func CommentModelToResponse(model models.Comment) CommentResponse {
@@ -27,5 +51,13 @@ func CommentModelToResponse(model models.Comment) CommentResponse {
 	dto.UserID = model.UserID.Hex()
 	dto.UserName = model.UserName
 	dto.Text = model.Text
+	if model.ParentID != nil {
+		dto.ParentID = model.ParentID.Hex()
+	}
+	dto.Depth = model.Depth
+	dto.ReplyCount = model.ReplyCount
+	dto.Deleted = model.Deleted
+	dto.Hidden = model.Hidden
+	dto.EditedAt = model.EditedAt
 	return dto
 }
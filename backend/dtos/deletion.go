@@ -0,0 +1,27 @@
+package dtos
+
+// DeletionReport resume lo que se borró (o se borraría, si DryRun es true)
+// al eliminar una receta o una cuenta. Las claves son los nombres de las
+// colecciones afectadas.
+type DeletionReport struct {
+	DryRun     bool             `json:"dryRun"`
+	Removed    map[string]int64 `json:"removed"`
+	Anonymized map[string]int64 `json:"anonymized"`
+}
+
+func NewDeletionReport(dryRun bool) DeletionReport {
+	return DeletionReport{
+		DryRun:     dryRun,
+		Removed:    map[string]int64{},
+		Anonymized: map[string]int64{},
+	}
+}
+
+// Add suma count documentos de collection a la sección que corresponde.
+func (report *DeletionReport) Add(collection string, count int64, anonymized bool) {
+	if anonymized {
+		report.Anonymized[collection] += count
+		return
+	}
+	report.Removed[collection] += count
+}
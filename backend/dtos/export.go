@@ -0,0 +1,8 @@
+package dtos
+
+// ExportFile es el resultado de una exportación, listo para enviarse como descarga.
+type ExportFile struct {
+	Name        string
+	ContentType string
+	Content     []byte
+}
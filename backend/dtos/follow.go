@@ -0,0 +1,56 @@
+package dtos
+
+import (
+	"burned/backend/models"
+	"time"
+)
+
+// UserProfileResponse es el perfil público de un usuario. Following indica
+// si quien consulta lo sigue (siempre false sin sesión).
+type UserProfileResponse struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	CreatedAt      time.Time `json:"createdAt"`
+	FollowerCount  int       `json:"followerCount"`
+	FollowingCount int       `json:"followingCount"`
+	Following      bool      `json:"following"`
+}
+
+// FollowResponse es el resultado de seguir o dejar de seguir: el estado
+// final y los contadores del usuario seguido.
+type FollowResponse struct {
+	UserID         string `json:"userId"`
+	Following      bool   `json:"following"`
+	FollowerCount  int    `json:"followerCount"`
+	FollowingCount int    `json:"followingCount"`
+}
+
+// FollowUserResponse es un usuario en la lista de seguidores o seguidos.
+type FollowUserResponse struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	FollowerCount int       `json:"followerCount"`
+	Since         time.Time `json:"since"`
+}
+
+// FeedItemResponse es una entrada del feed. Recipe es la receta publicada o,
+// en un comentario, la receta comentada.
+type FeedItemResponse struct {
+	ID        string           `json:"id"`
+	Type      string           `json:"type"` // "recipe" | "comment"
+	UserID    string           `json:"userId"`
+	UserName  string           `json:"userName"`
+	CreatedAt time.Time        `json:"createdAt"`
+	Recipe    *RecipeResponse  `json:"recipe,omitempty"`
+	Comment   *CommentResponse `json:"comment,omitempty"`
+}
+
+func UserModelToProfileResponse(model models.User) UserProfileResponse {
+	return UserProfileResponse{
+		ID:             model.ID.Hex(),
+		Name:           model.Name,
+		CreatedAt:      model.CreatedAt,
+		FollowerCount:  model.FollowerCount,
+		FollowingCount: model.FollowingCount,
+	}
+}
@@ -0,0 +1,12 @@
+package dtos
+
+type RecipeImportRequest struct {
+	Content string `json:"content" binding:"required"` // HTML o JSON-LD
+}
+
+// RecipeImportResponse es la vista previa de una receta importada. Draft es
+// un RecipeRequest válido: el cliente lo revisa y lo manda a POST /recipes.
+type RecipeImportResponse struct {
+	Draft    RecipeRequest `json:"draft"`
+	Warnings []string      `json:"warnings"`
+}
@@ -0,0 +1,16 @@
+package dtos
+
+import "burned/backend/models"
+
+type ParseIngredientsRequest struct {
+	Lines []string `json:"lines" binding:"required,min=1,max=100,dive,max=300"`
+}
+
+// ParsedIngredientResponse es el resultado de una línea. Si no se pudo
+// interpretar, Error explica por qué y Ingredient viene vacío.
+type ParsedIngredientResponse struct {
+	Input       string            `json:"input"`
+	Ingredient  models.Ingredient `json:"ingredient"`
+	QuantityMax float64           `json:"quantityMax,omitempty"`
+	Error       string            `json:"error,omitempty"`
+}
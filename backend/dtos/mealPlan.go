@@ -0,0 +1,57 @@
+package dtos
+
+import (
+	"burned/backend/models"
+	"time"
+)
+
+type MealPlanEntryRequest struct {
+	RecipeID string `json:"recipeId" binding:"required"`
+	Date     string `json:"date" binding:"required"` // YYYY-MM-DD
+	Slot     string `json:"slot" binding:"required,oneof=breakfast lunch dinner snack"`
+	Servings int    `json:"servings" binding:"omitempty,gte=1,lte=100"`
+}
+
+// CopyWeekRequest copia la semana de From a la de To (cualquier día de cada
+// semana sirve). Con Replace se vacía antes la semana destino.
+type CopyWeekRequest struct {
+	From    string `json:"from" binding:"required"`
+	To      string `json:"to" binding:"required"`
+	Replace bool   `json:"replace"`
+}
+
+// RepeatWeekRequest repite la semana de Week en las Times semanas siguientes.
+type RepeatWeekRequest struct {
+	Week    string `json:"week" binding:"required"`
+	Times   int    `json:"times" binding:"required,gte=1,lte=12"`
+	Replace bool   `json:"replace"`
+}
+
+type MealPlanEntryResponse struct {
+	ID        string          `json:"id"`
+	Date      string          `json:"date"`
+	Slot      string          `json:"slot"`
+	Servings  int             `json:"servings"`
+	RecipeID  string          `json:"recipeId"`
+	Recipe    *RecipeResponse `json:"recipe,omitempty"` // nil si la receta se borró o dejó de ser visible
+	CreatedAt time.Time       `json:"createdAt"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+type MealPlanResponse struct {
+	From    string                  `json:"from"`
+	To      string                  `json:"to"`
+	Entries []MealPlanEntryResponse `json:"entries"`
+}
+
+func MealPlanEntryModelToResponse(model models.MealPlanEntry) MealPlanEntryResponse {
+	return MealPlanEntryResponse{
+		ID:        model.ID.Hex(),
+		Date:      model.Date,
+		Slot:      model.Slot,
+		Servings:  model.Servings,
+		RecipeID:  model.RecipeID.Hex(),
+		CreatedAt: model.CreatedAt,
+		UpdatedAt: model.UpdatedAt,
+	}
+}
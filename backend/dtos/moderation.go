@@ -0,0 +1,160 @@
+package dtos
+
+import (
+	"burned/backend/models"
+	"errors"
+	"strings"
+	"time"
+)
+
+type ReportRequest struct {
+	Reason  string `json:"reason" binding:"required"` // ver models.ReportReasons
+	Details string `json:"details" binding:"omitempty,max=500"`
+}
+
+// Validate normaliza el motivo y exige detalle cuando es "other".
+func (dto *ReportRequest) Validate() error {
+	dto.Reason = strings.ToLower(strings.TrimSpace(dto.Reason))
+	dto.Details = strings.TrimSpace(dto.Details)
+	valid := false
+	for _, reason := range models.ReportReasons {
+		if dto.Reason == reason {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return errors.New("invalid reason: " + dto.Reason)
+	}
+	if dto.Reason == "other" && dto.Details == "" {
+		return errors.New("invalid report, details are required for reason other")
+	}
+	return nil
+}
+
+type ReportResponse struct {
+	ID         string    `json:"id"`
+	CaseID     string    `json:"caseId"`
+	TargetType string    `json:"targetType"`
+	TargetID   string    `json:"targetId"`
+	Reason     string    `json:"reason"`
+	Details    string    `json:"details,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+func ReportModelToResponse(model models.Report) ReportResponse {
+	return ReportResponse{
+		ID:         model.ID.Hex(),
+		CaseID:     model.CaseID.Hex(),
+		TargetType: model.TargetType,
+		TargetID:   model.TargetID.Hex(),
+		Reason:     model.Reason,
+		Details:    model.Details,
+		CreatedAt:  model.CreatedAt,
+	}
+}
+
+type ModerationDecisionRequest struct {
+	Action string `json:"action" binding:"required,oneof=hide delete dismiss warn"`
+	Note   string `json:"note" binding:"omitempty,max=500"` // para warn, es el texto que recibe el autor
+}
+
+// ModerationCaseResponse es un elemento de la cola. Target, Reports y
+// Decisions solo vienen en el detalle del caso.
+type ModerationCaseResponse struct {
+	ID           string                       `json:"id"`
+	TargetType   string                       `json:"targetType"`
+	TargetID     string                       `json:"targetId"`
+	RecipeID     string                       `json:"recipeId"`
+	AuthorID     string                       `json:"authorId"`
+	Status       string                       `json:"status"`
+	ReportCount  int                          `json:"reportCount"`
+	Reasons      []string                     `json:"reasons"`
+	AutoHidden   bool                         `json:"autoHidden"`
+	Findings     []string                     `json:"findings,omitempty"`
+	CreatedAt    time.Time                    `json:"createdAt"`
+	LastReportAt time.Time                    `json:"lastReportAt"`
+	Action       string                       `json:"action,omitempty"`
+	ResolvedBy   string                       `json:"resolvedBy,omitempty"`
+	ResolvedAt   *time.Time                   `json:"resolvedAt,omitempty"`
+	Target       *ModerationTargetResponse    `json:"target,omitempty"`
+	Reports      []ReportResponse             `json:"reports,omitempty"`
+	Decisions    []ModerationDecisionResponse `json:"decisions,omitempty"`
+}
+
+// ModerationTargetResponse muestra el contenido denunciado tal como está
+// ahora. Missing indica que ya no existe.
+type ModerationTargetResponse struct {
+	AuthorName string `json:"authorName,omitempty"`
+	Title      string `json:"title,omitempty"`
+	Text       string `json:"text,omitempty"`
+	Hidden     bool   `json:"hidden"`
+	Missing    bool   `json:"missing"`
+}
+
+func ModerationCaseModelToResponse(model models.ModerationCase) ModerationCaseResponse {
+	response := ModerationCaseResponse{
+		ID:           model.ID.Hex(),
+		TargetType:   model.TargetType,
+		TargetID:     model.TargetID.Hex(),
+		RecipeID:     model.RecipeID.Hex(),
+		AuthorID:     model.AuthorID.Hex(),
+		Status:       model.Status,
+		ReportCount:  model.ReportCount,
+		Reasons:      model.Reasons,
+		AutoHidden:   model.AutoHidden,
+		Findings:     model.Findings,
+		CreatedAt:    model.CreatedAt,
+		LastReportAt: model.LastReportAt,
+		Action:       model.Action,
+		ResolvedAt:   model.ResolvedAt,
+	}
+	if !model.ResolvedBy.IsZero() {
+		response.ResolvedBy = model.ResolvedBy.Hex()
+	}
+	return response
+}
+
+type ModerationDecisionResponse struct {
+	ID          string    `json:"id"`
+	CaseID      string    `json:"caseId"`
+	TargetType  string    `json:"targetType"`
+	TargetID    string    `json:"targetId"`
+	AuthorID    string    `json:"authorId"`
+	ModeratorID string    `json:"moderatorId"`
+	Action      string    `json:"action"`
+	Note        string    `json:"note,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+func ModerationDecisionModelToResponse(model models.ModerationDecision) ModerationDecisionResponse {
+	return ModerationDecisionResponse{
+		ID:          model.ID.Hex(),
+		CaseID:      model.CaseID.Hex(),
+		TargetType:  model.TargetType,
+		TargetID:    model.TargetID.Hex(),
+		AuthorID:    model.AuthorID.Hex(),
+		ModeratorID: model.ModeratorID.Hex(),
+		Action:      model.Action,
+		Note:        model.Note,
+		CreatedAt:   model.CreatedAt,
+	}
+}
+
+type UserWarningResponse struct {
+	ID         string    `json:"id"`
+	TargetType string    `json:"targetType"`
+	TargetID   string    `json:"targetId"`
+	Note       string    `json:"note,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+func UserWarningModelToResponse(model models.UserWarning) UserWarningResponse {
+	return UserWarningResponse{
+		ID:         model.ID.Hex(),
+		TargetType: model.TargetType,
+		TargetID:   model.TargetID.Hex(),
+		Note:       model.Note,
+		CreatedAt:  model.CreatedAt,
+	}
+}
@@ -0,0 +1,44 @@
+package dtos
+
+import (
+	"burned/backend/models"
+	"errors"
+	"strings"
+	"time"
+)
+
+// NutritionAliasRequest asocia un nombre de ingrediente a un alimento de la
+// base. Con UnmatchedID se da por revisado ese nombre sin resolver.
+type NutritionAliasRequest struct {
+	Name        string `json:"name" binding:"required,max=120"`
+	Food        string `json:"food" binding:"required,max=120"`
+	UnmatchedID string `json:"unmatchedId" binding:"omitempty"`
+}
+
+func (dto *NutritionAliasRequest) Validate() error {
+	dto.Name = strings.TrimSpace(dto.Name)
+	dto.Food = strings.TrimSpace(dto.Food)
+	if dto.Name == "" || dto.Food == "" {
+		return errors.New("invalid alias")
+	}
+	return nil
+}
+
+type NutritionAliasResponse struct {
+	Key       string    `json:"key"`
+	Name      string    `json:"name"`
+	Food      string    `json:"food"`
+	CreatedBy string    `json:"createdBy"`
+	CreatedAt time.Time `json:"createdAt"`
+	Updated   int       `json:"updatedRecipes"` // recetas recalculadas con el alias nuevo
+}
+
+func NutritionAliasModelToResponse(model models.NutritionAlias) NutritionAliasResponse {
+	return NutritionAliasResponse{
+		Key:       model.Key,
+		Name:      model.Name,
+		Food:      model.Food,
+		CreatedBy: model.CreatedBy.Hex(),
+		CreatedAt: model.CreatedAt,
+	}
+}
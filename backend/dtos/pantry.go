@@ -0,0 +1,73 @@
+package dtos
+
+import (
+	"burned/backend/mealplan"
+	"burned/backend/models"
+	"errors"
+	"strings"
+	"time"
+)
+
+type PantryItemRequest struct {
+	Name      string  `json:"name" binding:"required,max=120"`
+	Quantity  float64 `json:"quantity" binding:"gte=0"`
+	Unit      string  `json:"unit"`
+	ExpiresAt string  `json:"expiresAt"` // YYYY-MM-DD, opcional
+}
+
+// Validate limpia el nombre, normaliza la unidad y verifica la fecha.
+func (dto *PantryItemRequest) Validate() error {
+	dto.Name = strings.TrimSpace(dto.Name)
+	if dto.Name == "" {
+		return errors.New("invalid item, name is required")
+	}
+	if dto.ExpiresAt != "" {
+		if _, err := mealplan.ParseDate(dto.ExpiresAt); err != nil {
+			return err
+		}
+	}
+	return normalizeItemUnit(&dto.Quantity, &dto.Unit)
+}
+
+type PantryItemResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Quantity  float64   `json:"quantity"`
+	Unit      string    `json:"unit"`
+	ExpiresAt string    `json:"expiresAt,omitempty"`
+	Expired   bool      `json:"expired"`
+	Expiring  bool      `json:"expiring"` // vence dentro de los días de aviso
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// PantrySearchRequest son los parámetros propios de la búsqueda por
+// despensa; los filtros de receta son los de /recipes/search.
+type PantrySearchRequest struct {
+	MaxMissing   *int `form:"maxMissing" binding:"omitempty,gte=0,lte=50"`
+	ExpiringDays *int `form:"expiringDays" binding:"omitempty,gte=0,lte=30"`
+}
+
+// PantryRecipeResponse es una receta encontrada por despensa con lo que
+// falta comprar. Needed no incluye opcionales ni ingredientes "a gusto".
+type PantryRecipeResponse struct {
+	Recipe   RecipeResponse `json:"recipe"`
+	Matched  int            `json:"matched"`
+	Needed   int            `json:"needed"`
+	Missing  []string       `json:"missing"`
+	Expiring []string       `json:"expiring"` // ingredientes por vencer que la receta aprovecha
+}
+
+func PantryItemModelToResponse(model models.PantryItem, today string, expiringUntil string) PantryItemResponse {
+	return PantryItemResponse{
+		ID:        model.ID.Hex(),
+		Name:      model.Name,
+		Quantity:  model.Quantity,
+		Unit:      model.Unit,
+		ExpiresAt: model.ExpiresAt,
+		Expired:   model.ExpiresAt != "" && model.ExpiresAt < today,
+		Expiring:  model.ExpiresAt != "" && model.ExpiresAt >= today && model.ExpiresAt <= expiringUntil,
+		CreatedAt: model.CreatedAt,
+		UpdatedAt: model.UpdatedAt,
+	}
+}
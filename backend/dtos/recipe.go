@@ -1,7 +1,14 @@
 package dtos
 
 import (
+	"burned/backend/dietary"
+	"burned/backend/ingredients"
 	"burned/backend/models"
+	"burned/backend/timeline"
+	"burned/backend/units"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -9,39 +16,137 @@ type RecipeRequest struct {
 	Title          string              `json:"title" binding:"required,min=3,max=120"`
 	Description    string              `json:"description" binding:"required,min=3,max=350"`
 	Visibility     string              `json:"visibility" binding:"required,oneof=public private"`
-	TotalTime      int                 `json:"totalTime" binding:"required,gte=0,lte=100000"`
+	TotalTime      int                 `json:"totalTime" binding:"omitempty,gte=0,lte=100000"` // solo si algún paso no tiene tiempo; si no, sale de timeline.Schedule
+	Servings       int                 `json:"servings" binding:"omitempty,gte=1,lte=100"`
 	Step           []models.Step       `json:"step" binding:"required,min=1,dive"`
 	DificultyLevel string              `json:"dificultyLevel" binding:"required,oneof=easy medium hard"`
 	Tags           []string            `json:"tags" binding:"omitempty,max=20,dive,min=1,max=30"`
-	Ingredients    []models.Ingredient `json:"ingredients" binding:"required,min=1,dive"`
-	Image          string              `json:"image" binding:"omitempty,max=2000"` // URL (por ahora)
+	Ingredients    []models.Ingredient `json:"ingredients" binding:"required_without=IngredientLines,dive"`
+	// Alternativa a Ingredients: líneas de texto libre ("2 tazas de harina")
+	// que se interpretan con backend/ingredients y se agregan a Ingredients
+	IngredientLines []string `json:"ingredientLines" binding:"omitempty,max=100,dive,max=300"`
+	Image           string   `json:"image" binding:"omitempty,max=2000"` // URL (por ahora)
 }
 
 type RecipeResponse struct {
-	Title          string              `json:"title" binding:"required,min=3,max=120"`
-	Description    string              `json:"description" binding:"required,min=3,max=350"`
-	Visibility     string              `json:"visibility" binding:"required,oneof=public private"`
-	TotalTime      int                 `json:"totalTime" binding:"required,gte=0,lte=100000"`
-	Step           []models.Step       `json:"step" binding:"required,min=1,dive"`
-	DificultyLevel string              `json:"dificultyLevel" binding:"required,oneof=easy medium hard"`
-	Tags           []string            `json:"tags" binding:"omitempty,max=20,dive,min=1,max=30"`
-	Ingredients    []models.Ingredient `json:"ingredients" binding:"required,min=1,dive"`
-	Image          string              `json:"image" binding:"omitempty,max=2000"` // URL (por ahora)
-	CreatedAt      time.Time           `json:"createdAt"`
-	UpdatedAt      time.Time           `json:"updatedAt"`
-	ID             string              `json:"id"`
-	UserName       string              `json:"userName"`
-	UserID         string              `json:"userId"`
-	AverageRating  float64             `json:"averageRating"`
+	Title          string                  `json:"title" binding:"required,min=3,max=120"`
+	Description    string                  `json:"description" binding:"required,min=3,max=350"`
+	Visibility     string                  `json:"visibility" binding:"required,oneof=public private"`
+	Hidden         bool                    `json:"hidden,omitempty"` // oculta por moderación, solo la ve su autor
+	TotalTime      int                     `json:"totalTime" binding:"required,gte=0,lte=100000"`
+	Servings       int                     `json:"servings"`
+	Step           []models.Step           `json:"step" binding:"required,min=1,dive"`
+	DificultyLevel string                  `json:"dificultyLevel" binding:"required,oneof=easy medium hard"`
+	Tags           []string                `json:"tags" binding:"omitempty,max=20,dive,min=1,max=30"`
+	Ingredients    []models.Ingredient     `json:"ingredients" binding:"required,min=1,dive"`
+	Image          string                  `json:"image" binding:"omitempty,max=2000"` // URL (por ahora)
+	CreatedAt      time.Time               `json:"createdAt"`
+	UpdatedAt      time.Time               `json:"updatedAt"`
+	ID             string                  `json:"id"`
+	UserName       string                  `json:"userName"`
+	UserID         string                  `json:"userId"`
+	AverageRating  float64                 `json:"averageRating"`
+	SavedCount     int64                   `json:"savedCount"`
+	Score          float64                 `json:"score,omitempty"` // relevancia, solo en resultados de búsqueda
+	ForkedFrom     *models.ForkOrigin      `json:"forkedFrom,omitempty"`
+	Lineage        []models.ForkOrigin     `json:"lineage,omitempty"`
+	ForkCount      int64                   `json:"forkCount"`               // solo en el detalle de la receta
+	PersonalNotes  *models.RecipeNotes     `json:"personalNotes,omitempty"` // notas privadas de quien consulta
+	Allergens      []string                `json:"allergens"`
+	Diets          []string                `json:"diets"`
+	Unclassified   []string                `json:"unclassified,omitempty"` // ingredientes sin clasificar, por eso no hay dietas
+	Nutrition      *models.RecipeNutrition `json:"nutrition,omitempty"`
 }
 
 type RecipeSearchRequest struct {
-	Title          string   `json:"title" binding:"omitempty,max=120"`
-	Description    string   `json:"description" binding:"omitempty,max=350"`
-	Visibility     string   `json:"visibility" binding:"omitempty,oneof=public private"`
-	TotalTime      int      `json:"totalTime" binding:"omitempty"`
-	DificultyLevel string   `json:"dificultyLevel" binding:"omitempty,oneof=easy medium hard"`
-	Tags           []string `json:"tags" binding:"omitempty"`
+	Query            string   `json:"query" binding:"omitempty,max=200"` // texto libre: admite "frases" y -exclusiones
+	Title            string   `json:"title" binding:"omitempty,max=120"`
+	Description      string   `json:"description" binding:"omitempty,max=350"`
+	Visibility       string   `json:"visibility" binding:"omitempty,oneof=public private"`
+	TotalTime        int      `json:"totalTime" binding:"omitempty"`
+	DificultyLevel   string   `json:"dificultyLevel" binding:"omitempty,oneof=easy medium hard"`
+	Tags             []string `json:"tags" binding:"omitempty"`
+	ExcludeAllergens []string `json:"excludeAllergens" binding:"omitempty"` // ver dietary.Allergens
+	Diets            []string `json:"diets" binding:"omitempty"`            // ver dietary.Diets; se exigen todas
+}
+
+// Validate deja alérgenos y dietas en minúscula y rechaza los desconocidos:
+// un alérgeno mal escrito no puede pasar como filtro vacío.
+func (dto *RecipeSearchRequest) Validate() error {
+	return normalizeDietary(dto.ExcludeAllergens, dto.Diets)
+}
+
+// normalizeDietary valida alérgenos y dietas contra backend/dietary y los
+// deja en minúscula.
+func normalizeDietary(allergens []string, diets []string) error {
+	for i, allergen := range allergens {
+		allergens[i] = strings.ToLower(strings.TrimSpace(allergen))
+		if !dietary.ValidAllergen(allergens[i]) {
+			return errors.New("invalid allergen: " + allergen)
+		}
+	}
+	for i, diet := range diets {
+		diets[i] = strings.ToLower(strings.TrimSpace(diet))
+		if !dietary.ValidDiet(diets[i]) {
+			return errors.New("invalid diet: " + diet)
+		}
+	}
+	return nil
+}
+
+// Validate revisa los ingredientes y deja cada unidad en su código canónico
+// del catálogo ("tazas" -> "cup"). Un ingrediente sin unidad se toma como
+// conteo si tiene cantidad y como "a gusto" si no la tiene.
+func (dto *RecipeRequest) Validate() error {
+	if len(dto.IngredientLines) > 0 {
+		parsed, err := ingredients.ParseLines(dto.IngredientLines)
+		if err != nil {
+			return err
+		}
+		for _, line := range parsed {
+			dto.Ingredients = append(dto.Ingredients, line.Ingredient())
+		}
+		dto.IngredientLines = nil
+	}
+	for i := range dto.Ingredients {
+		ingredient := &dto.Ingredients[i]
+		ingredient.Name = strings.TrimSpace(ingredient.Name)
+		ingredient.Note = strings.TrimSpace(ingredient.Note)
+
+		if ingredient.Name == "" || len(ingredient.Name) > 120 {
+			return fmt.Errorf("ingredient %d: name is required (max 120 characters)", i+1)
+		}
+		if len(ingredient.Note) > 200 {
+			return fmt.Errorf("ingredient %d: note is too long (max 200 characters)", i+1)
+		}
+		if ingredient.Quantity < 0 {
+			return fmt.Errorf("ingredient %d: quantity cannot be negative", i+1)
+		}
+
+		if ingredient.Unit == "" {
+			if ingredient.Quantity == 0 {
+				ingredient.Unit = units.CodeToTaste
+			} else {
+				ingredient.Unit = units.CodeUnit
+			}
+			continue
+		}
+		unit, ok := units.Find(ingredient.Unit)
+		if !ok {
+			return fmt.Errorf("ingredient %d: unknown unit %q", i+1, ingredient.Unit)
+		}
+		ingredient.Unit = unit.Code
+		if unit.Dimension == units.ToTaste {
+			ingredient.Quantity = 0
+		} else if ingredient.Quantity == 0 {
+			return fmt.Errorf("ingredient %d: quantity is required for unit %q", i+1, unit.Code)
+		}
+	}
+	if len(dto.Ingredients) == 0 {
+		return errors.New("at least one ingredient is required")
+	}
+	//los pasos refieren ingredientes por posición, así que se revisan después de interpretar las líneas
+	return timeline.Validate(dto.Step, len(dto.Ingredients))
 }
 
 func RecipeRequestToModel(dto RecipeRequest) models.Recipe {
@@ -53,11 +158,27 @@ func RecipeRequestToModel(dto RecipeRequest) models.Recipe {
 	model.Ingredients = dto.Ingredients
 	model.Step = dto.Step
 	model.TotalTime = dto.TotalTime
+	model.Servings = dto.Servings
 	model.Title = dto.Title
 	model.Description = dto.Description
 	return model
 }
 
+func RecipeModelToRequest(model models.Recipe) RecipeRequest {
+	var request RecipeRequest
+	request.Title = model.Title
+	request.Description = model.Description
+	request.Visibility = model.Visibility
+	request.TotalTime = model.TotalTime
+	request.Servings = model.Servings
+	request.Step = model.Step
+	request.DificultyLevel = model.DificultyLevel
+	request.Tags = model.Tags
+	request.Ingredients = model.Ingredients
+	request.Image = model.Image
+	return request
+}
+
 func RecipeModelToResponse(model models.Recipe) RecipeResponse {
 	var response RecipeResponse
 	response.CreatedAt = model.CreatedAt
@@ -67,12 +188,22 @@ func RecipeModelToResponse(model models.Recipe) RecipeResponse {
 	response.Image = model.Image
 	response.Tags = model.Tags
 	response.Visibility = model.Visibility
+	response.Hidden = model.Hidden
 	response.Ingredients = model.Ingredients
 	response.Step = model.Step
 	response.TotalTime = model.TotalTime
+	response.Servings = model.Servings
 	response.Title = model.Title
 	response.Description = model.Description
 	response.UserID = model.UserID.Hex()
 	response.AverageRating = model.AverageRating
+	response.SavedCount = model.SavedCount
+	response.Score = model.Score
+	response.ForkedFrom = model.ForkedFrom
+	response.Lineage = model.Lineage
+	response.Allergens = model.Allergens
+	response.Diets = model.Diets
+	response.Unclassified = model.Unclassified
+	response.Nutrition = model.Nutrition
 	return response
 }
@@ -0,0 +1,45 @@
+package dtos
+
+import (
+	"burned/backend/models"
+	"burned/backend/revisions"
+	"time"
+)
+
+// RevisionSummaryResponse es un elemento del historial, sin la foto completa.
+type RevisionSummaryResponse struct {
+	Number       int       `json:"number"`
+	Title        string    `json:"title"`
+	EditorID     string    `json:"editorId"`
+	EditorName   string    `json:"editorName"`
+	CreatedAt    time.Time `json:"createdAt"`
+	RestoredFrom int       `json:"restoredFrom,omitempty"`
+}
+
+type RevisionResponse struct {
+	RevisionSummaryResponse
+	Recipe RecipeResponse `json:"recipe"`
+}
+
+type RevisionDiffResponse struct {
+	From    int                `json:"from"`
+	To      int                `json:"to"`
+	Changes []revisions.Change `json:"changes"`
+}
+
+func RevisionModelToSummary(revision models.RecipeRevision) RevisionSummaryResponse {
+	return RevisionSummaryResponse{
+		Number:       revision.Number,
+		Title:        revision.Snapshot.Title,
+		EditorID:     revision.EditorID.Hex(),
+		CreatedAt:    revision.CreatedAt,
+		RestoredFrom: revision.RestoredFrom,
+	}
+}
+
+func RevisionModelToResponse(revision models.RecipeRevision) RevisionResponse {
+	return RevisionResponse{
+		RevisionSummaryResponse: RevisionModelToSummary(revision),
+		Recipe:                  RecipeModelToResponse(revision.Snapshot),
+	}
+}
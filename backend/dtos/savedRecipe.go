@@ -1,6 +1,12 @@
 package dtos
 
-import "time"
+import (
+	"burned/backend/models"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
 
 type SavedRecipeRequest struct {
 	RecipeID string `json:"recipeId"`
@@ -10,3 +16,46 @@ type SavedRecipeResponse struct {
 	RecipeID string    `json:"savedRecipeId"`
 	SavedAt  time.Time `json:"createdAt"`
 }
+
+type RecipeNotesRequest struct {
+	Note          string                          `json:"note" binding:"omitempty,max=1000"`
+	Steps         []models.StepNote               `json:"steps" binding:"omitempty,max=50"`
+	Substitutions []models.IngredientSubstitution `json:"substitutions" binding:"omitempty,max=50"`
+}
+
+// Validate limpia las notas y verifica que los pasos anotados existan en la
+// receta (stepCount pasos) y que no se repitan.
+func (dto *RecipeNotesRequest) Validate(stepCount int) error {
+	dto.Note = strings.TrimSpace(dto.Note)
+	seen := map[int]bool{}
+	for i := range dto.Steps {
+		step := &dto.Steps[i]
+		step.Text = strings.TrimSpace(step.Text)
+		if step.Step < 1 || step.Step > stepCount {
+			return fmt.Errorf("step note %d: step must be between 1 and %d", i+1, stepCount)
+		}
+		if seen[step.Step] {
+			return fmt.Errorf("step note %d: step %d is already annotated", i+1, step.Step)
+		}
+		if step.Text == "" || len(step.Text) > 500 {
+			return fmt.Errorf("step note %d: text is required (max 500 characters)", i+1)
+		}
+		seen[step.Step] = true
+	}
+	for i := range dto.Substitutions {
+		substitution := &dto.Substitutions[i]
+		substitution.Ingredient = strings.TrimSpace(substitution.Ingredient)
+		substitution.Replacement = strings.TrimSpace(substitution.Replacement)
+		substitution.Note = strings.TrimSpace(substitution.Note)
+		if substitution.Ingredient == "" || substitution.Replacement == "" {
+			return fmt.Errorf("substitution %d: ingredient and replacement are required", i+1)
+		}
+		if len(substitution.Ingredient) > 120 || len(substitution.Replacement) > 120 || len(substitution.Note) > 300 {
+			return fmt.Errorf("substitution %d: text too long", i+1)
+		}
+	}
+	if dto.Note == "" && len(dto.Steps) == 0 && len(dto.Substitutions) == 0 {
+		return errors.New("notes are empty")
+	}
+	return nil
+}
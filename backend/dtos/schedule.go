@@ -0,0 +1,52 @@
+package dtos
+
+import "time"
+
+type CookSessionRequest struct {
+	RecipeID string    `json:"recipeId" binding:"required"`
+	ServeAt  time.Time `json:"serveAt" binding:"required"` // RFC 3339, con zona horaria
+	Servings int       `json:"servings" binding:"omitempty,gte=1,lte=100"`
+}
+
+type CookSessionResponse struct {
+	ID          string    `json:"id"`
+	RecipeID    string    `json:"recipeId"`
+	RecipeTitle string    `json:"recipeTitle"`
+	ServeAt     time.Time `json:"serveAt"`
+	StartAt     time.Time `json:"startAt"` // cuándo empezar el primer paso
+	Servings    int       `json:"servings"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// CalendarFeedResponse se devuelve una sola vez al crear el feed: el token no
+// se puede volver a consultar, solo regenerar.
+type CalendarFeedResponse struct {
+	Token string `json:"token"`
+	Path  string `json:"path"`
+}
+
+// RecipeTimelineResponse es el cronograma de una receta. Los minutos se
+// cuentan desde que se empieza a cocinar; con serveAt se agregan también
+// las horas reales.
+type RecipeTimelineResponse struct {
+	RecipeID  string                 `json:"recipeId"`
+	Title     string                 `json:"title"`
+	TotalTime int                    `json:"totalTime"`
+	ServeAt   *time.Time             `json:"serveAt,omitempty"`
+	StartAt   *time.Time             `json:"startAt,omitempty"`
+	Critical  []int                  `json:"critical"` // posiciones de los pasos que definen el tiempo total
+	Steps     []TimelineStepResponse `json:"steps"`
+}
+
+type TimelineStepResponse struct {
+	Index       int        `json:"index"`
+	Title       string     `json:"title"`
+	Start       int        `json:"start"`
+	End         int        `json:"end"`
+	StartAt     *time.Time `json:"startAt,omitempty"`
+	EndAt       *time.Time `json:"endAt,omitempty"`
+	Passive     bool       `json:"passive"`
+	Critical    bool       `json:"critical"`
+	DependsOn   []int      `json:"dependsOn"`
+	Ingredients []string   `json:"ingredients"`
+}
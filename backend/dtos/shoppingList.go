@@ -0,0 +1,146 @@
+package dtos
+
+import (
+	"burned/backend/models"
+	"burned/backend/shopping"
+	"burned/backend/units"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ShoppingListGenerateRequest agrega a la lista los ingredientes de Recipes y,
+// si viene From, los del plan de comidas entre From y To (sin To, la semana
+// de From). Con Replace se descartan antes los ítems generados; los manuales
+// se conservan siempre.
+type ShoppingListGenerateRequest struct {
+	Recipes []ShoppingRecipeRequest `json:"recipes" binding:"omitempty,max=50,dive"`
+	From    string                  `json:"from"`
+	To      string                  `json:"to"`
+	Replace bool                    `json:"replace"`
+	System  string                  `json:"system" binding:"omitempty,oneof=metric imperial"`
+}
+
+type ShoppingRecipeRequest struct {
+	RecipeID string `json:"recipeId" binding:"required"`
+	Servings int    `json:"servings" binding:"omitempty,gte=1,lte=100"` // 0 usa las porciones de la receta
+}
+
+type ShoppingItemRequest struct {
+	Name     string  `json:"name" binding:"required,max=120"`
+	Quantity float64 `json:"quantity" binding:"gte=0"`
+	Unit     string  `json:"unit"`
+	Aisle    string  `json:"aisle" binding:"omitempty,oneof=produce meat seafood dairy bakery pantry spices frozen beverages other"`
+}
+
+// Validate limpia el nombre y deja la unidad en su código del catálogo. Un
+// ítem manual puede no tener cantidad ("servilletas").
+func (dto *ShoppingItemRequest) Validate() error {
+	dto.Name = strings.TrimSpace(dto.Name)
+	if dto.Name == "" {
+		return errors.New("invalid item, name is required")
+	}
+	return normalizeItemUnit(&dto.Quantity, &dto.Unit)
+}
+
+// normalizeItemUnit deja la unidad de un ítem suelto (lista de compras,
+// despensa) en su código del catálogo. Sin unidad, una cantidad es un conteo.
+func normalizeItemUnit(quantity *float64, unit *string) error {
+	if *unit == "" {
+		if *quantity > 0 {
+			*unit = units.CodeUnit
+		}
+		return nil
+	}
+	found, ok := units.Find(*unit)
+	if !ok {
+		return fmt.Errorf("invalid item, unknown unit %q", *unit)
+	}
+	*unit = found.Code
+	if found.Dimension == units.ToTaste {
+		*quantity = 0
+	} else if *quantity == 0 {
+		return fmt.Errorf("invalid item, quantity is required for unit %q", found.Code)
+	}
+	return nil
+}
+
+// ShoppingItemUpdateRequest tacha o destacha un ítem o lo cambia de pasillo.
+type ShoppingItemUpdateRequest struct {
+	Checked *bool   `json:"checked"`
+	Aisle   *string `json:"aisle" binding:"omitempty,oneof=produce meat seafood dairy bakery pantry spices frozen beverages other"`
+}
+
+type ShoppingItemResponse struct {
+	ID        string                  `json:"id"`
+	Name      string                  `json:"name"`
+	Aisle     string                  `json:"aisle"`
+	Amounts   []models.ShoppingAmount `json:"amounts"`
+	Display   string                  `json:"display"` // "1 kg + 2 can"
+	Manual    bool                    `json:"manual"`
+	Checked   bool                    `json:"checked"`
+	RecipeIDs []string                `json:"recipeIds,omitempty"`
+	AddedAt   time.Time               `json:"addedAt"`
+}
+
+type ShoppingAisleResponse struct {
+	Aisle string                 `json:"aisle"`
+	Items []ShoppingItemResponse `json:"items"`
+}
+
+type ShoppingListResponse struct {
+	Aisles    []ShoppingAisleResponse `json:"aisles"`
+	Recipes   []models.ShoppingSource `json:"recipes"`
+	Total     int                     `json:"total"`
+	Checked   int                     `json:"checked"`
+	UpdatedAt time.Time               `json:"updatedAt"`
+}
+
+func ShoppingItemModelToResponse(model models.ShoppingItem) ShoppingItemResponse {
+	response := ShoppingItemResponse{
+		ID:      model.ID.Hex(),
+		Name:    model.Name,
+		Aisle:   model.Aisle,
+		Amounts: model.Amounts,
+		Display: shopping.Display(model.Amounts),
+		Manual:  model.Manual,
+		Checked: model.Checked,
+		AddedAt: model.AddedAt,
+	}
+	if response.Amounts == nil {
+		response.Amounts = []models.ShoppingAmount{}
+	}
+	for _, recipeId := range model.RecipeIDs {
+		response.RecipeIDs = append(response.RecipeIDs, recipeId.Hex())
+	}
+	return response
+}
+
+// ShoppingListModelToResponse agrupa los ítems por pasillo en el orden de recorrido.
+func ShoppingListModelToResponse(model models.ShoppingList) ShoppingListResponse {
+	items := append([]models.ShoppingItem{}, model.Items...)
+	shopping.Sort(items)
+
+	response := ShoppingListResponse{
+		Aisles:    []ShoppingAisleResponse{},
+		Recipes:   model.Recipes,
+		Total:     len(items),
+		UpdatedAt: model.UpdatedAt,
+	}
+	if response.Recipes == nil {
+		response.Recipes = []models.ShoppingSource{}
+	}
+	for _, item := range items {
+		if item.Checked {
+			response.Checked++
+		}
+		last := len(response.Aisles) - 1
+		if last < 0 || response.Aisles[last].Aisle != item.Aisle {
+			response.Aisles = append(response.Aisles, ShoppingAisleResponse{Aisle: item.Aisle})
+			last++
+		}
+		response.Aisles[last].Items = append(response.Aisles[last].Items, ShoppingItemModelToResponse(item))
+	}
+	return response
+}
@@ -0,0 +1,101 @@
+package dtos
+
+import (
+	"burned/backend/models"
+	"burned/backend/units"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxSubstituteParts acota los ingredientes de un reemplazo
+const maxSubstituteParts = 6
+
+type SubstitutionRuleRequest struct {
+	Ingredient string                  `json:"ingredient" binding:"required,max=120"`
+	Quantity   float64                 `json:"quantity" binding:"gt=0"`
+	Unit       string                  `json:"unit" binding:"required"`
+	Substitute []SubstitutePartRequest `json:"substitute" binding:"required,min=1,dive"`
+	Note       string                  `json:"note" binding:"omitempty,max=300"`
+}
+
+type SubstitutePartRequest struct {
+	Name     string  `json:"name" binding:"required,max=120"`
+	Quantity float64 `json:"quantity" binding:"gte=0"`
+	Unit     string  `json:"unit"`
+}
+
+// Validate limpia los nombres y deja las unidades en su código del
+// catálogo. La cantidad de referencia tiene que ser medible: "a gusto" no
+// sirve para calcular proporciones.
+func (dto *SubstitutionRuleRequest) Validate() error {
+	dto.Ingredient = strings.TrimSpace(dto.Ingredient)
+	dto.Note = strings.TrimSpace(dto.Note)
+	if dto.Ingredient == "" {
+		return errors.New("invalid rule, ingredient is required")
+	}
+	unit, ok := units.Find(dto.Unit)
+	if !ok || unit.Dimension == units.ToTaste {
+		return fmt.Errorf("invalid rule, unit %q cannot be used as reference", dto.Unit)
+	}
+	dto.Unit = unit.Code
+	if len(dto.Substitute) == 0 || len(dto.Substitute) > maxSubstituteParts {
+		return fmt.Errorf("invalid rule, substitute needs between 1 and %d ingredients", maxSubstituteParts)
+	}
+	for i := range dto.Substitute {
+		part := &dto.Substitute[i]
+		part.Name = strings.TrimSpace(part.Name)
+		if part.Name == "" {
+			return errors.New("invalid rule, substitute name is required")
+		}
+		if err := normalizeItemUnit(&part.Quantity, &part.Unit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func SubstitutionRuleRequestToModel(dto SubstitutionRuleRequest) models.SubstitutionRule {
+	rule := models.SubstitutionRule{
+		Ingredient: dto.Ingredient,
+		Quantity:   dto.Quantity,
+		Unit:       dto.Unit,
+		Note:       dto.Note,
+	}
+	for _, part := range dto.Substitute {
+		rule.Substitute = append(rule.Substitute, models.SubstitutePart{Name: part.Name, Quantity: part.Quantity, Unit: part.Unit})
+	}
+	return rule
+}
+
+// SubstitutionFilter son las restricciones de quien pide los reemplazos:
+// ?excludeAllergens=dairy,nuts&diets=vegan
+type SubstitutionFilter struct {
+	ExcludeAllergens []string
+	Diets            []string
+}
+
+func (dto *SubstitutionFilter) Validate() error {
+	return normalizeDietary(dto.ExcludeAllergens, dto.Diets)
+}
+
+type SubstitutionResponse struct {
+	RuleID     string              `json:"ruleId"`
+	Ratio      string              `json:"ratio"`      // "1 cup buttermilk = 1 cup leche + 1 tbsp jugo de limón"
+	Substitute []models.Ingredient `json:"substitute"` // cantidades para esta receta; vacío si no se pueden calcular
+	Note       string              `json:"note,omitempty"`
+	Allergens  []string            `json:"allergens"`
+	Diets      []string            `json:"diets"`
+}
+
+type IngredientSubstitutionsResponse struct {
+	Ingredient    models.Ingredient      `json:"ingredient"`
+	Conflicts     bool                   `json:"conflicts"` // el ingrediente original no cumple las restricciones pedidas
+	Substitutions []SubstitutionResponse `json:"substitutions"`
+}
+
+type RecipeSubstitutionsResponse struct {
+	RecipeID    string                            `json:"recipeId"`
+	Title       string                            `json:"title"`
+	Ingredients []IngredientSubstitutionsResponse `json:"ingredients"` // solo los que tienen reemplazos o no cumplen las restricciones
+}
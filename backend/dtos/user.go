@@ -24,6 +24,9 @@ type UserResponse struct {
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 	Password  string    `json:"password"`
+
+	FollowerCount  int `json:"followerCount"`
+	FollowingCount int `json:"followingCount"`
 }
 
 type LoginRequest struct {
@@ -60,5 +63,7 @@ func UserModelToResponse(model models.User) UserResponse {
 	response.UpdatedAt = model.UpdatedAt
 	response.CreatedAt = model.CreatedAt
 	response.Password = model.HashedPassword
+	response.FollowerCount = model.FollowerCount
+	response.FollowingCount = model.FollowingCount
 	return response
 }
@@ -0,0 +1,114 @@
+package export
+
+import (
+	"archive/zip"
+	"burned/backend/dtos"
+	"burned/backend/models"
+	"burned/backend/scaling"
+	"burned/backend/units"
+	"bytes"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Exporter convierte una receta a un formato de archivo. Para agregar un
+// formato nuevo alcanza con implementarlo y registrarlo en init.
+type Exporter interface {
+	Format() string
+	ContentType() string
+	Extension() string
+	Export(recipe dtos.RecipeResponse) ([]byte, error)
+}
+
+var registry = map[string]Exporter{}
+
+// Register agrega un exportador; uno nuevo con el mismo formato reemplaza al anterior.
+func Register(exporter Exporter) {
+	registry[exporter.Format()] = exporter
+}
+
+// Get devuelve el exportador del formato pedido.
+func Get(format string) (Exporter, bool) {
+	exporter, ok := registry[strings.ToLower(format)]
+	return exporter, ok
+}
+
+// Formats lista los formatos registrados.
+func Formats() []string {
+	formats := make([]string, 0, len(registry))
+	for format := range registry {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+func init() {
+	Register(jsonLDExporter{})
+	Register(markdownExporter{})
+	Register(htmlExporter{})
+	Register(textExporter{})
+}
+
+// Zip exporta varias recetas con el mismo formato en un único archivo zip.
+func Zip(recipes []dtos.RecipeResponse, exporter Exporter) ([]byte, error) {
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+	for _, recipe := range recipes {
+		content, err := exporter.Export(recipe)
+		if err != nil {
+			return nil, err
+		}
+		file, err := archive.Create(FileName(recipe, exporter))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := file.Write(content); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// FileName arma un nombre de archivo estable: título en minúsculas sin
+// símbolos y el id para que dos recetas con el mismo título no choquen.
+func FileName(recipe dtos.RecipeResponse, exporter Exporter) string {
+	return Slug(recipe.Title) + "-" + recipe.ID + "." + exporter.Extension()
+}
+
+// Slug deja el título apto para un nombre de archivo.
+func Slug(title string) string {
+	slug := strings.Trim(nonSlug.ReplaceAllString(units.Normalize(title), "-"), "-")
+	if slug == "" {
+		slug = "receta"
+	}
+	if len(slug) > 60 {
+		slug = strings.TrimRight(slug[:60], "-")
+	}
+	return slug
+}
+
+// IngredientLine escribe un ingrediente en una línea: "1 1/2 cup harina, tamizada (optional)".
+func IngredientLine(ingredient models.Ingredient) string {
+	line := ingredient.Name
+	if amount := scaling.Format(scaling.Amount{Quantity: ingredient.Quantity, Unit: ingredient.Unit}); amount != "" {
+		if ingredient.Unit == "to_taste" {
+			line = ingredient.Name + ", " + amount
+		} else {
+			line = amount + " " + ingredient.Name
+		}
+	}
+	if ingredient.Note != "" {
+		line += ", " + ingredient.Note
+	}
+	if ingredient.Optional {
+		line += " (optional)"
+	}
+	return line
+}
@@ -0,0 +1,61 @@
+package export
+
+import (
+	"burned/backend/dtos"
+	"bytes"
+	"html/template"
+)
+
+// htmlExporter genera una página autocontenida pensada para imprimir.
+type htmlExporter struct{}
+
+func (htmlExporter) Format() string      { return "html" }
+func (htmlExporter) ContentType() string { return "text/html; charset=utf-8" }
+func (htmlExporter) Extension() string   { return "html" }
+
+var printableTemplate = template.Must(template.New("recipe").Funcs(template.FuncMap{
+	"ingredient": IngredientLine,
+	"summary":    summary,
+	"inc":        func(i int) int { return i + 1 },
+}).Parse(`<!DOCTYPE html>
+<html lang="es">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+  body { font-family: Georgia, serif; max-width: 720px; margin: 2rem auto; padding: 0 1rem; color: #111; }
+  h1 { margin-bottom: .25rem; }
+  .author, .summary { color: #555; margin: .25rem 0; }
+  .tags span { display: inline-block; border: 1px solid #ccc; border-radius: 4px; padding: 0 .4rem; margin-right: .25rem; font-size: .85rem; }
+  img { max-width: 100%; max-height: 320px; object-fit: cover; }
+  ol li { margin-bottom: .75rem; }
+  .time { color: #777; font-size: .9rem; }
+  @media print { body { margin: 0; } img { max-height: 200px; } a { color: inherit; text-decoration: none; } }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="author">Por {{.UserName}}</p>
+{{if .Image}}<img src="{{.Image}}" alt="{{.Title}}">{{end}}
+<p>{{.Description}}</p>
+<p class="summary">{{summary .}}</p>
+{{if .Tags}}<p class="tags">{{range .Tags}}<span>{{.}}</span>{{end}}</p>{{end}}
+<h2>Ingredientes</h2>
+<ul>
+{{range .Ingredients}}  <li>{{ingredient .}}</li>
+{{end}}</ul>
+<h2>Pasos</h2>
+<ol>
+{{range .Step}}  <li><strong>{{.Title}}</strong>{{if .Time}} <span class="time">({{.Time}} min)</span>{{end}}<br>{{.Descripcion}}</li>
+{{end}}</ol>
+</body>
+</html>
+`))
+
+func (htmlExporter) Export(recipe dtos.RecipeResponse) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := printableTemplate.Execute(&buffer, recipe); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,94 @@
+package export
+
+import (
+	"burned/backend/dtos"
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// jsonLDExporter genera un schema.org Recipe, el mismo formato que entiende
+// backend/recipeimport, así una receta exportada se puede volver a importar.
+type jsonLDExporter struct{}
+
+func (jsonLDExporter) Format() string      { return "jsonld" }
+func (jsonLDExporter) ContentType() string { return "application/ld+json; charset=utf-8" }
+func (jsonLDExporter) Extension() string   { return "jsonld" }
+
+func (jsonLDExporter) Export(recipe dtos.RecipeResponse) ([]byte, error) {
+	ingredients := make([]string, 0, len(recipe.Ingredients))
+	for _, ingredient := range recipe.Ingredients {
+		ingredients = append(ingredients, IngredientLine(ingredient))
+	}
+
+	steps := make([]map[string]interface{}, 0, len(recipe.Step))
+	for i, step := range recipe.Step {
+		howToStep := map[string]interface{}{
+			"@type":    "HowToStep",
+			"position": i + 1,
+			"name":     step.Title,
+			"text":     step.Descripcion,
+		}
+		if step.Time > 0 {
+			howToStep["performTime"] = Duration(step.Time)
+		}
+		steps = append(steps, howToStep)
+	}
+
+	document := map[string]interface{}{
+		"@context":           "https://schema.org",
+		"@type":              "Recipe",
+		"name":               recipe.Title,
+		"description":        recipe.Description,
+		"author":             map[string]interface{}{"@type": "Person", "name": recipe.UserName},
+		"datePublished":      recipe.CreatedAt.Format(time.RFC3339),
+		"recipeIngredient":   ingredients,
+		"recipeInstructions": steps,
+		"keywords":           recipe.Tags,
+		"identifier":         recipe.ID,
+	}
+	if recipe.TotalTime > 0 {
+		document["totalTime"] = Duration(recipe.TotalTime)
+	}
+	if recipe.Servings > 0 {
+		document["recipeYield"] = strconv.Itoa(recipe.Servings)
+	}
+	if recipe.Image != "" {
+		document["image"] = recipe.Image
+	}
+	if recipe.ForkedFrom != nil {
+		document["isBasedOn"] = map[string]interface{}{
+			"@type":  "Recipe",
+			"name":   recipe.ForkedFrom.Title,
+			"author": map[string]interface{}{"@type": "Person", "name": recipe.ForkedFrom.UserName},
+		}
+	}
+	if recipe.AverageRating > 0 {
+		document["aggregateRating"] = map[string]interface{}{
+			"@type":       "AggregateRating",
+			"ratingValue": recipe.AverageRating,
+		}
+	}
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// Duration escribe minutos como duración ISO-8601 ("PT1H30M").
+func Duration(minutes int) string {
+	hours, rest := minutes/60, minutes%60
+	switch {
+	case hours == 0:
+		return "PT" + strconv.Itoa(rest) + "M"
+	case rest == 0:
+		return "PT" + strconv.Itoa(hours) + "H"
+	default:
+		return "PT" + strconv.Itoa(hours) + "H" + strconv.Itoa(rest) + "M"
+	}
+}
@@ -0,0 +1,85 @@
+package export
+
+import (
+	"burned/backend/dtos"
+	"fmt"
+	"strings"
+)
+
+type markdownExporter struct{}
+
+func (markdownExporter) Format() string      { return "markdown" }
+func (markdownExporter) ContentType() string { return "text/markdown; charset=utf-8" }
+func (markdownExporter) Extension() string   { return "md" }
+
+func (markdownExporter) Export(recipe dtos.RecipeResponse) ([]byte, error) {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "# %s\n\n", recipe.Title)
+	fmt.Fprintf(&builder, "_Por %s_\n\n", recipe.UserName)
+	if recipe.Image != "" {
+		fmt.Fprintf(&builder, "![%s](%s)\n\n", recipe.Title, recipe.Image)
+	}
+	fmt.Fprintf(&builder, "%s\n\n", recipe.Description)
+	fmt.Fprintf(&builder, "%s\n\n", summary(recipe))
+	if len(recipe.Tags) > 0 {
+		tags := make([]string, 0, len(recipe.Tags))
+		for _, tag := range recipe.Tags {
+			tags = append(tags, "`"+tag+"`")
+		}
+		fmt.Fprintf(&builder, "%s\n\n", strings.Join(tags, " "))
+	}
+
+	builder.WriteString("## Ingredientes\n\n")
+	for _, ingredient := range recipe.Ingredients {
+		fmt.Fprintf(&builder, "- %s\n", IngredientLine(ingredient))
+	}
+
+	builder.WriteString("\n## Pasos\n\n")
+	for i, step := range recipe.Step {
+		fmt.Fprintf(&builder, "%d. **%s**%s  \n   %s\n", i+1, step.Title, stepTime(step.Time), step.Descripcion)
+	}
+	return []byte(builder.String()), nil
+}
+
+type textExporter struct{}
+
+func (textExporter) Format() string      { return "txt" }
+func (textExporter) ContentType() string { return "text/plain; charset=utf-8" }
+func (textExporter) Extension() string   { return "txt" }
+
+func (textExporter) Export(recipe dtos.RecipeResponse) ([]byte, error) {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "%s\n%s\n", strings.ToUpper(recipe.Title), strings.Repeat("=", len([]rune(recipe.Title))))
+	fmt.Fprintf(&builder, "Por %s\n\n%s\n\n%s\n", recipe.UserName, recipe.Description, summary(recipe))
+	if len(recipe.Tags) > 0 {
+		fmt.Fprintf(&builder, "Tags: %s\n", strings.Join(recipe.Tags, ", "))
+	}
+
+	builder.WriteString("\nINGREDIENTES\n")
+	for _, ingredient := range recipe.Ingredients {
+		fmt.Fprintf(&builder, "  - %s\n", IngredientLine(ingredient))
+	}
+
+	builder.WriteString("\nPASOS\n")
+	for i, step := range recipe.Step {
+		fmt.Fprintf(&builder, "  %d. %s%s\n     %s\n", i+1, step.Title, stepTime(step.Time), step.Descripcion)
+	}
+	return []byte(builder.String()), nil
+}
+
+// summary es la línea de datos generales: tiempo, porciones y dificultad.
+func summary(recipe dtos.RecipeResponse) string {
+	parts := []string{fmt.Sprintf("Tiempo total: %d min", recipe.TotalTime)}
+	if recipe.Servings > 0 {
+		parts = append(parts, fmt.Sprintf("Porciones: %d", recipe.Servings))
+	}
+	parts = append(parts, "Dificultad: "+recipe.DificultyLevel)
+	return strings.Join(parts, " · ")
+}
+
+func stepTime(minutes int) string {
+	if minutes <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%d min)", minutes)
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"burned/backend/dtos"
+	"burned/backend/pagination"
+	"burned/backend/services"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CollectionHandler struct {
+	service services.CollectionServiceInterface
+}
+
+func NewCollectionHandler(s services.CollectionServiceInterface) *CollectionHandler {
+	return &CollectionHandler{service: s}
+}
+
+func (handler *CollectionHandler) CreateCollection(c *gin.Context) {
+	var req dtos.CollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, err.Error())
+		return
+	}
+	userID, _ := c.Get("user_id")
+	result, err := handler.service.CreateCollection(req, userID.(string))
+	if err != nil {
+		collectionError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
+func (handler *CollectionHandler) UpdateCollection(c *gin.Context) {
+	var req dtos.CollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, err.Error())
+		return
+	}
+	userID, _ := c.Get("user_id")
+	result, err := handler.service.UpdateCollection(c.Param("id"), req, userID.(string))
+	if err != nil {
+		collectionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (handler *CollectionHandler) DeleteCollection(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	if err := handler.service.DeleteCollection(c.Param("id"), userID.(string)); err != nil {
+		collectionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"Result": "Collection deleted"})
+}
+
+// GetCollection es pública con auth opcional: el dueño ve también las privadas.
+func (handler *CollectionHandler) GetCollection(c *gin.Context) {
+	requesterId, _ := c.Get("user_id")
+	requesterIdStr, _ := requesterId.(string)
+	result, err := handler.service.GetCollection(c.Param("id"), requesterIdStr)
+	if err != nil {
+		collectionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (handler *CollectionHandler) GetSharedCollection(c *gin.Context) {
+	requesterId, _ := c.Get("user_id")
+	requesterIdStr, _ := requesterId.(string)
+	result, err := handler.service.GetSharedCollection(c.Param("token"), requesterIdStr)
+	if err != nil {
+		collectionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (handler *CollectionHandler) GetMyCollections(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	handler.listCollections(c, userID.(string), userID.(string))
+}
+
+func (handler *CollectionHandler) GetCollectionsByUser(c *gin.Context) {
+	requesterId, _ := c.Get("user_id")
+	requesterIdStr, _ := requesterId.(string)
+	handler.listCollections(c, c.Param("id"), requesterIdStr)
+}
+
+func (handler *CollectionHandler) listCollections(c *gin.Context, userId string, requesterId string) {
+	var page pagination.Request
+	if err := c.ShouldBindQuery(&page); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"Error": err.Error()})
+		return
+	}
+	result, err := handler.service.GetCollectionsByUser(userId, requesterId, page)
+	if err != nil {
+		if pagination.IsRequestError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"Error": err.Error()})
+			return
+		}
+		collectionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (handler *CollectionHandler) AddRecipe(c *gin.Context) {
+	var req dtos.CollectionItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, err.Error())
+		return
+	}
+	userID, _ := c.Get("user_id")
+	result, err := handler.service.AddRecipe(c.Param("id"), req.RecipeID, userID.(string))
+	if err != nil {
+		collectionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (handler *CollectionHandler) RemoveRecipe(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	if err := handler.service.RemoveRecipe(c.Param("id"), c.Param("recipeId"), userID.(string)); err != nil {
+		collectionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"Result": "Recipe removed from collection"})
+}
+
+func (handler *CollectionHandler) ReorderRecipes(c *gin.Context) {
+	var req dtos.CollectionOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, err.Error())
+		return
+	}
+	userID, _ := c.Get("user_id")
+	result, err := handler.service.ReorderRecipes(c.Param("id"), req.RecipeIDs, userID.(string))
+	if err != nil {
+		collectionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (handler *CollectionHandler) RotateShareToken(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	result, err := handler.service.RotateShareToken(c.Param("id"), userID.(string))
+	if err != nil {
+		collectionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func collectionError(c *gin.Context, err error) {
+	message := err.Error()
+	switch {
+	case strings.HasPrefix(message, "invalid"), strings.HasPrefix(message, "order must"):
+		c.JSON(http.StatusBadRequest, gin.H{"Error": message})
+	case strings.HasSuffix(message, "not found"), message == "recipe not in collection":
+		c.JSON(http.StatusNotFound, gin.H{"Error": message})
+	case message == "recipe already in collection", message == "collection is full", message == "the default collection cannot be deleted":
+		c.JSON(http.StatusConflict, gin.H{"Error": message})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"Error": message})
+	}
+}
@@ -2,8 +2,10 @@ package handlers
 
 import (
 	"burned/backend/dtos"
+	"burned/backend/pagination"
 	"burned/backend/services"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -37,7 +39,14 @@ func (handler *CommentHandler) CreateComment(c *gin.Context) {
 
 	result, err := handler.service.CreateComment(request, userIdStr)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"Error": err.Error()})
+		switch {
+		case strings.HasPrefix(err.Error(), "invalid"):
+			c.JSON(http.StatusBadRequest, gin.H{"Error": err.Error()})
+		case err.Error() == "parent comment not found", err.Error() == "recipe not found":
+			c.JSON(http.StatusNotFound, gin.H{"Error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"Error": err.Error()})
+		}
 		return
 	}
 	c.JSON(http.StatusCreated, result)
@@ -65,8 +74,18 @@ func (handler *CommentHandler) DeleteComment(c *gin.Context) {
 func (handler *CommentHandler) GetCommentsByRecipe(c *gin.Context) {
 	id := c.Param("recipeId")
 
-	result, err := handler.service.GetCommentsByRecipe(id)
+	var page pagination.Request
+	if err := c.ShouldBindQuery(&page); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"Error": err.Error()})
+		return
+	}
+
+	result, err := handler.service.GetCommentsByRecipe(id, page)
 	if err != nil {
+		if pagination.IsRequestError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"Error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -83,3 +102,68 @@ func (handler *CommentHandler) GetCommentById(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, result)
 }
+
+// GetReplies pagina las respuestas directas de un comentario (?sort=oldest
+// por defecto, o newest).
+func (handler *CommentHandler) GetReplies(c *gin.Context) {
+	var page pagination.Request
+	if err := c.ShouldBindQuery(&page); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"Error": err.Error()})
+		return
+	}
+
+	result, err := handler.service.GetReplies(c.Param("id"), page)
+	if err != nil {
+		switch {
+		case pagination.IsRequestError(err), err.Error() == "invalid id":
+			c.JSON(http.StatusBadRequest, gin.H{"Error": err.Error()})
+		case err.Error() == "comment not found":
+			c.JSON(http.StatusNotFound, gin.H{"Error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (handler *CommentHandler) UpdateComment(c *gin.Context) {
+	var request dtos.CommentUpdateRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"Error": err.Error()})
+		return
+	}
+	userID, _ := c.Get("user_id")
+
+	result, err := handler.service.UpdateComment(c.Param("id"), request, userID.(string))
+	if err != nil {
+		commentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetCommentHistory es solo para admins: muestra los textos anteriores de un
+// comentario editado.
+func (handler *CommentHandler) GetCommentHistory(c *gin.Context) {
+	result, err := handler.service.GetCommentHistory(c.Param("id"))
+	if err != nil {
+		commentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func commentError(c *gin.Context, err error) {
+	message := err.Error()
+	switch {
+	case strings.HasSuffix(message, "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"Error": message})
+	case strings.HasPrefix(message, "invalid"):
+		c.JSON(http.StatusBadRequest, gin.H{"Error": message})
+	case strings.HasPrefix(message, "unauthorized"):
+		c.JSON(http.StatusForbidden, gin.H{"Error": message})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"Error": message})
+	}
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"burned/backend/dtos"
+	"burned/backend/export"
+	"burned/backend/services"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExportHandler struct {
+	service services.ExportServiceInterface
+}
+
+func NewExportHandler(s services.ExportServiceInterface) *ExportHandler {
+	return &ExportHandler{service: s}
+}
+
+// ExportRecipe es pública (con auth opcional): el token solo hace falta para
+// exportar recetas privadas propias.
+func (handler *ExportHandler) ExportRecipe(c *gin.Context) {
+	requesterId, _ := c.Get("user_id")
+	requesterIdStr, _ := requesterId.(string)
+
+	file, err := handler.service.ExportRecipe(c.Param("id"), exportFormat(c), requesterIdStr)
+	if err != nil {
+		handler.exportError(c, err)
+		return
+	}
+	sendExportFile(c, file)
+}
+
+func (handler *ExportHandler) ExportRecipesByUser(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"Error": "User unauthorized"})
+		return
+	}
+	file, err := handler.service.ExportRecipesByUser(userID.(string), exportFormat(c))
+	if err != nil {
+		handler.exportError(c, err)
+		return
+	}
+	sendExportFile(c, file)
+}
+
+func (handler *ExportHandler) exportError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "unsupported export format":
+		c.JSON(http.StatusBadRequest, gin.H{"Error": err.Error(), "formats": export.Formats()})
+	case "invalid id":
+		c.JSON(http.StatusBadRequest, gin.H{"Error": err.Error()})
+	case "recipe not found":
+		c.JSON(http.StatusNotFound, gin.H{"Error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"Error": err.Error()})
+	}
+}
+
+func exportFormat(c *gin.Context) string {
+	return c.DefaultQuery("format", "jsonld")
+}
+
+// sendExportFile envía el archivo como descarga; el HTML se abre en el
+// navegador para poder imprimirlo directamente.
+func sendExportFile(c *gin.Context, file dtos.ExportFile) {
+	disposition := "attachment"
+	if strings.HasPrefix(file.ContentType, "text/html") {
+		disposition = "inline"
+	}
+	c.Header("Content-Disposition", disposition+`; filename="`+file.Name+`"`)
+	c.Data(http.StatusOK, file.ContentType, file.Content)
+}
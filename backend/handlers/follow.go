@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"burned/backend/pagination"
+	"burned/backend/services"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FollowHandler struct {
+	service     services.FollowServiceInterface
+	feedService services.FeedServiceInterface
+}
+
+func NewFollowHandler(s services.FollowServiceInterface, feedService services.FeedServiceInterface) *FollowHandler {
+	return &FollowHandler{service: s, feedService: feedService}
+}
+
+func (handler *FollowHandler) Follow(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	result, err := handler.service.Follow(c.Param("id"), userID.(string))
+	if err != nil {
+		followError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (handler *FollowHandler) Unfollow(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	result, err := handler.service.Unfollow(c.Param("id"), userID.(string))
+	if err != nil {
+		followError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetProfile es pública con auth opcional: con sesión indica si ya lo sigue.
+func (handler *FollowHandler) GetProfile(c *gin.Context) {
+	requesterId, _ := c.Get("user_id")
+	requesterIdStr, _ := requesterId.(string)
+	result, err := handler.service.GetProfile(c.Param("id"), requesterIdStr)
+	if err != nil {
+		followError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (handler *FollowHandler) GetFollowers(c *gin.Context) {
+	var page pagination.Request
+	if err := c.ShouldBindQuery(&page); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"Error": err.Error()})
+		return
+	}
+	result, err := handler.service.GetFollowers(c.Param("id"), page)
+	if err != nil {
+		followError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (handler *FollowHandler) GetFollowing(c *gin.Context) {
+	var page pagination.Request
+	if err := c.ShouldBindQuery(&page); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"Error": err.Error()})
+		return
+	}
+	result, err := handler.service.GetFollowing(c.Param("id"), page)
+	if err != nil {
+		followError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetFeed devuelve la actividad de los usuarios seguidos: ?limit=&after=
+func (handler *FollowHandler) GetFeed(c *gin.Context) {
+	var page pagination.Request
+	if err := c.ShouldBindQuery(&page); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"Error": err.Error()})
+		return
+	}
+	userID, _ := c.Get("user_id")
+	result, err := handler.feedService.GetFeed(userID.(string), page)
+	if err != nil {
+		followError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func followError(c *gin.Context, err error) {
+	message := err.Error()
+	switch {
+	case pagination.IsRequestError(err), strings.HasPrefix(message, "invalid"):
+		c.JSON(http.StatusBadRequest, gin.H{"Error": message})
+	case strings.HasSuffix(message, "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"Error": message})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"Error": message})
+	}
+}
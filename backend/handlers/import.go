@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"burned/backend/dtos"
+	"burned/backend/services"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportSize limita el tamaño del documento a importar (páginas con
+// mucho HTML alrededor del JSON-LD rondan los cientos de KB)
+const maxImportSize = 2 << 20
+
+type RecipeImportHandler struct {
+	service services.RecipeImportServiceInterface
+}
+
+func NewRecipeImportHandler(s services.RecipeImportServiceInterface) *RecipeImportHandler {
+	return &RecipeImportHandler{service: s}
+}
+
+// PreviewImport acepta el documento como archivo (multipart, campo "file"),
+// como JSON {"content": "..."} o directamente como cuerpo HTML / JSON-LD.
+func (handler *RecipeImportHandler) PreviewImport(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+	content, err := readImportContent(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"Error": err.Error()})
+		return
+	}
+
+	result, err := handler.service.PreviewImport(content)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"Error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func readImportContent(c *gin.Context) ([]byte, error) {
+	contentType := c.ContentType()
+	switch {
+	case strings.HasPrefix(contentType, "multipart/form-data"):
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			return nil, err
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return io.ReadAll(file)
+	case contentType == "application/json":
+		var request dtos.RecipeImportRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			return nil, err
+		}
+		return []byte(request.Content), nil
+	default:
+		return io.ReadAll(c.Request.Body)
+	}
+}
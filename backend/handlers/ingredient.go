@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"burned/backend/dtos"
+	"burned/backend/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type IngredientHandler struct {
+	service services.IngredientServiceInterface
+}
+
+func NewIngredientHandler(s services.IngredientServiceInterface) *IngredientHandler {
+	return &IngredientHandler{service: s}
+}
+
+func (handler *IngredientHandler) ParseIngredients(c *gin.Context) {
+	var request dtos.ParseIngredientsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"Error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, handler.service.ParseIngredients(request))
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"burned/backend/dtos"
+	"burned/backend/services"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MealPlanHandler struct {
+	service services.MealPlanServiceInterface
+}
+
+func NewMealPlanHandler(s services.MealPlanServiceInterface) *MealPlanHandler {
+	return &MealPlanHandler{service: s}
+}
+
+func (handler *MealPlanHandler) CreateEntry(c *gin.Context) {
+	var req dtos.MealPlanEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, err.Error())
+		return
+	}
+	userID, _ := c.Get("user_id")
+	result, err := handler.service.CreateEntry(req, userID.(string))
+	if err != nil {
+		mealPlanError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
+func (handler *MealPlanHandler) UpdateEntry(c *gin.Context) {
+	var req dtos.MealPlanEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, err.Error())
+		return
+	}
+	userID, _ := c.Get("user_id")
+	result, err := handler.service.UpdateEntry(c.Param("id"), req, userID.(string))
+	if err != nil {
+		mealPlanError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (handler *MealPlanHandler) DeleteEntry(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	if err := handler.service.DeleteEntry(c.Param("id"), userID.(string)); err != nil {
+		mealPlanError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"Result": "Meal removed from plan"})
+}
+
+// GetPlan acepta ?from=YYYY-MM-DD&to=YYYY-MM-DD; sin parámetros devuelve la
+// semana actual y solo con from, la semana de esa fecha.
+func (handler *MealPlanHandler) GetPlan(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	result, err := handler.service.GetPlan(userID.(string), c.Query("from"), c.Query("to"))
+	if err != nil {
+		mealPlanError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (handler *MealPlanHandler) CopyWeek(c *gin.Context) {
+	var req dtos.CopyWeekRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, err.Error())
+		return
+	}
+	userID, _ := c.Get("user_id")
+	result, err := handler.service.CopyWeek(req, userID.(string))
+	if err != nil {
+		mealPlanError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
+func (handler *MealPlanHandler) RepeatWeek(c *gin.Context) {
+	var req dtos.RepeatWeekRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, err.Error())
+		return
+	}
+	userID, _ := c.Get("user_id")
+	result, err := handler.service.RepeatWeek(req, userID.(string))
+	if err != nil {
+		mealPlanError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
+func mealPlanError(c *gin.Context, err error) {
+	message := err.Error()
+	switch {
+	case strings.HasSuffix(message, "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"Error": message})
+	case strings.HasPrefix(message, "invalid"), message == "source and target are the same week", message == "source week has no meals planned":
+		c.JSON(http.StatusBadRequest, gin.H{"Error": message})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"Error": message})
+	}
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"burned/backend/dtos"
+	"burned/backend/pagination"
+	"burned/backend/services"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ModerationHandler struct {
+	service services.ModerationServiceInterface
+}
+
+func NewModerationHandler(s services.ModerationServiceInterface) *ModerationHandler {
+	return &ModerationHandler{service: s}
+}
+
+func (handler *ModerationHandler) ReportComment(c *gin.Context) {
+	var req dtos.ReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"Error": err.Error()})
+		return
+	}
+	userID, _ := c.Get("user_id")
+	result, err := handler.service.ReportComment(c.Param("id"), req, userID.(string))
+	if err != nil {
+		moderationError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
+func (handler *ModerationHandler) ReportRecipe(c *gin.Context) {
+	var req dtos.ReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"Error": err.Error()})
+		return
+	}
+	userID, _ := c.Get("user_id")
+	result, err := handler.service.ReportRecipe(c.Param("id"), req, userID.(string))
+	if err != nil {
+		moderationError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
+// GetCases es la cola de moderación: ?status=open|resolved y
+// ?sort=reports|newest|oldest.
+func (handler *ModerationHandler) GetCases(c *gin.Context) {
+	var page pagination.Request
+	if err := c.ShouldBindQuery(&page); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"Error": err.Error()})
+		return
+	}
+	result, err := handler.service.GetCases(c.Query("status"), page)
+	if err != nil {
+		moderationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (handler *ModerationHandler) GetCase(c *gin.Context) {
+	result, err := handler.service.GetCase(c.Param("id"))
+	if err != nil {
+		moderationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (handler *ModerationHandler) Decide(c *gin.Context) {
+	var req dtos.ModerationDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"Error": err.Error()})
+		return
+	}
+	userID, _ := c.Get("user_id")
+	result, err := handler.service.Decide(c.Param("id"), req, userID.(string))
+	if err != nil {
+		moderationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (handler *ModerationHandler) GetDecisions(c *gin.Context) {
+	var page pagination.Request
+	if err := c.ShouldBindQuery(&page); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"Error": err.Error()})
+		return
+	}
+	result, err := handler.service.GetDecisions(page)
+	if err != nil {
+		moderationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetWarnings devuelve los avisos de moderación del usuario logueado.
+func (handler *ModerationHandler) GetWarnings(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	result, err := handler.service.GetWarnings(userID.(string))
+	if err != nil {
+		moderationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func moderationError(c *gin.Context, err error) {
+	message := err.Error()
+	switch {
+	case strings.HasSuffix(message, "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"Error": message})
+	case message == "content already reported", message == "case already resolved":
+		c.JSON(http.StatusConflict, gin.H{"Error": message})
+	case strings.HasPrefix(message, "invalid"), pagination.IsRequestError(err):
+		c.JSON(http.StatusBadRequest, gin.H{"Error": message})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"Error": message})
+	}
+}
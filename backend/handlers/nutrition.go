@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"burned/backend/dtos"
+	"burned/backend/pagination"
+	"burned/backend/services"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NutritionHandler expone la revisión de la base nutricional; todas sus
+// rutas son solo para admins.
+type NutritionHandler struct {
+	service services.NutritionServiceInterface
+}
+
+func NewNutritionHandler(s services.NutritionServiceInterface) *NutritionHandler {
+	return &NutritionHandler{service: s}
+}
+
+// GetUnmatched lista los nombres de ingredientes sin datos nutricionales,
+// los más usados primero.
+func (handler *NutritionHandler) GetUnmatched(c *gin.Context) {
+	var page pagination.Request
+	if err := c.ShouldBindQuery(&page); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"Error": err.Error()})
+		return
+	}
+	result, err := handler.service.GetUnmatched(page)
+	if err != nil {
+		nutritionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (handler *NutritionHandler) DismissUnmatched(c *gin.Context) {
+	if err := handler.service.DismissUnmatched(c.Param("id")); err != nil {
+		nutritionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"Result": "Unmatched ingredient dismissed"})
+}
+
+func (handler *NutritionHandler) GetFoods(c *gin.Context) {
+	c.JSON(http.StatusOK, handler.service.GetFoods())
+}
+
+func (handler *NutritionHandler) AddAlias(c *gin.Context) {
+	var req dtos.NutritionAliasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, err.Error())
+		return
+	}
+	userID, _ := c.Get("user_id")
+	result, err := handler.service.AddAlias(req, userID.(string))
+	if err != nil {
+		nutritionError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
+func nutritionError(c *gin.Context, err error) {
+	message := err.Error()
+	switch {
+	case strings.HasSuffix(message, "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"Error": message})
+	case strings.HasPrefix(message, "invalid"), pagination.IsRequestError(err):
+		c.JSON(http.StatusBadRequest, gin.H{"Error": message})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"Error": message})
+	}
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"burned/backend/dtos"
+	"burned/backend/mealplan"
+	"burned/backend/pagination"
+	"burned/backend/services"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PantryHandler struct {
+	service services.PantryServiceInterface
+}
+
+func NewPantryHandler(s services.PantryServiceInterface) *PantryHandler {
+	return &PantryHandler{service: s}
+}
+
+func (handler *PantryHandler) GetItems(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	result, err := handler.service.GetItems(userID.(string))
+	if err != nil {
+		pantryError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (handler *PantryHandler) CreateItem(c *gin.Context) {
+	var req dtos.PantryItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, err.Error())
+		return
+	}
+	userID, _ := c.Get("user_id")
+	result, err := handler.service.CreateItem(req, userID.(string))
+	if err != nil {
+		pantryError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
+func (handler *PantryHandler) UpdateItem(c *gin.Context) {
+	var req dtos.PantryItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, err.Error())
+		return
+	}
+	userID, _ := c.Get("user_id")
+	result, err := handler.service.UpdateItem(c.Param("id"), req, userID.(string))
+	if err != nil {
+		pantryError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (handler *PantryHandler) DeleteItem(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	if err := handler.service.DeleteItem(c.Param("id"), userID.(string)); err != nil {
+		pantryError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"Result": "Item removed from pantry"})
+}
+
+// SearchRecipes es "cocinar con lo que tengo": acepta los filtros de
+// /recipes/search (?q=&difficulty=&time=&tags=) más ?maxMissing= y
+// ?expiringDays=, y la paginación de siempre (?sort=pantry por defecto).
+func (handler *PantryHandler) SearchRecipes(c *gin.Context) {
+	var options dtos.PantrySearchRequest
+	if err := c.ShouldBindQuery(&options); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var page pagination.Request
+	if err := c.ShouldBindQuery(&page); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID, _ := c.Get("user_id")
+	result, err := handler.service.SearchRecipes(userID.(string), searchFiltersFromQuery(c), options, page)
+	if err != nil {
+		if pagination.IsRequestError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		pantryError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func pantryError(c *gin.Context, err error) {
+	message := err.Error()
+	switch {
+	case strings.HasSuffix(message, "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"Error": message})
+	case message == "pantry item already exists":
+		c.JSON(http.StatusConflict, gin.H{"Error": message})
+	case strings.HasPrefix(message, "invalid"), errors.Is(err, mealplan.ErrInvalidDate):
+		c.JSON(http.StatusBadRequest, gin.H{"Error": message})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"Error": message})
+	}
+}
@@ -2,7 +2,10 @@ package handlers
 
 import (
 	"burned/backend/dtos"
+	"burned/backend/pagination"
+	"burned/backend/scaling"
 	"burned/backend/services"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
@@ -36,6 +39,11 @@ func (handler *RecipeHandler) CreateRecipe(c *gin.Context) {
 	}
 	result, err := handler.service.CreateRecipe(req, userIdStr)
 	if err != nil {
+		//texto rechazado por la moderación automática
+		if strings.HasPrefix(err.Error(), "invalid content") {
+			c.JSON(http.StatusBadRequest, gin.H{"Error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -58,6 +66,11 @@ func (handler *RecipeHandler) UpdateRecipe(c *gin.Context) {
 	requesterRole, _ := c.Get("user_role")
 	result, err := handler.service.UpdateRecipe(req, id, requesterId.(string), requesterRole.(string))
 	if err != nil {
+		//texto rechazado por la moderación automática
+		if strings.HasPrefix(err.Error(), "invalid content") {
+			c.JSON(http.StatusBadRequest, gin.H{"Error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -68,12 +81,17 @@ func (handler *RecipeHandler) DeleteRecipe(c *gin.Context) {
 	id := c.Param("id")
 	requesterId, _ := c.Get("user_id")
 	requesterRole, _ := c.Get("user_role")
-	err := handler.service.DeleteRecipe(id, requesterId.(string), requesterRole.(string))
+	dryRun := c.Query("dryRun") == "true"
+	report, err := handler.service.DeleteRecipe(id, requesterId.(string), requesterRole.(string), dryRun)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, err.Error())
 		return
 	}
-	c.JSON(http.StatusCreated, gin.H{"Result": "It was successfully deleted"})
+	if dryRun {
+		c.JSON(http.StatusOK, report)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"Result": "It was successfully deleted", "Report": report})
 
 }
 
@@ -87,14 +105,20 @@ func (handler *RecipeHandler) GetRecipes(c *gin.Context) {
 		return
 	}
 
-	recipes, err := handler.service.GetRecipes(recipe)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error interno al obtener recetas"})
+	var page pagination.Request
+	if err := c.ShouldBindQuery(&page); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if recipes == nil {
-		recipes = []dtos.RecipeResponse{}
+	recipes, err := handler.service.GetRecipes(recipe, page)
+	if err != nil {
+		if pagination.IsRequestError(err) || strings.HasPrefix(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error interno al obtener recetas"})
+		return
 	}
 
 	c.JSON(http.StatusOK, recipes)
@@ -112,8 +136,18 @@ func (handler *RecipeHandler) GetRecipesByUser(c *gin.Context) {
 		return
 	}
 
-	result, err := handler.service.GetRecipesByUser(userIdStr)
+	var page pagination.Request
+	if err := c.ShouldBindQuery(&page); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := handler.service.GetRecipesByUser(userIdStr, page)
 	if err != nil {
+		if pagination.IsRequestError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -122,8 +156,11 @@ func (handler *RecipeHandler) GetRecipesByUser(c *gin.Context) {
 
 func (handler *RecipeHandler) GetRecipeById(c *gin.Context) {
 	id := c.Param("id")
+	//con auth opcional: el token solo agrega las notas privadas de quien consulta
+	requesterId, _ := c.Get("user_id")
+	requesterIdStr, _ := requesterId.(string)
 
-	result, err := handler.service.GetRecipeById(id)
+	result, err := handler.service.GetRecipeById(id, requesterIdStr)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, err.Error())
 		return
@@ -132,18 +169,23 @@ func (handler *RecipeHandler) GetRecipeById(c *gin.Context) {
 }
 
 func (handler *RecipeHandler) GetAll(c *gin.Context) {
+	var page pagination.Request
+	if err := c.ShouldBindQuery(&page); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Llama al servicio
-	recipes, err := handler.service.GetAll()
+	recipes, err := handler.service.GetAll(page)
 	if err != nil {
+		if pagination.IsRequestError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener las recetas"})
 		return
 	}
 
-	// Si está vacío, devolvemos array vacío en vez de null
-	if recipes == nil {
-		recipes = []dtos.RecipeResponse{}
-	}
-
 	c.JSON(http.StatusOK, recipes)
 }
 
@@ -161,11 +203,34 @@ func (handler *RecipeHandler) GetTopRecipes(c *gin.Context) {
 	c.JSON(http.StatusOK, recipes)
 }
 func (handler *RecipeHandler) QuickSearch(c *gin.Context) {
-	title := c.Query("q")
+	filters := searchFiltersFromQuery(c)
+
+	var page pagination.Request
+	if err := c.ShouldBindQuery(&page); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	recipes, err := handler.service.GetRecipes(filters, page)
+	if err != nil {
+		if pagination.IsRequestError(err) || strings.HasPrefix(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, recipes)
+}
+
+// searchFiltersFromQuery arma los filtros de búsqueda desde la query string:
+// ?q=&desc=&difficulty=&time=&tags=a,b&excludeAllergens=gluten,nuts&diets=vegan
+func searchFiltersFromQuery(c *gin.Context) dtos.RecipeSearchRequest {
+	query := c.Query("q")
 	description := c.Query("desc")
 	difficulty := c.Query("difficulty")
 	timeStr := c.Query("time")
-	tagsStr := c.Query("tags") // <--- Leemos el parámetro 'tags'
 
 	// Convertir Tiempo
 	var totalTime int
@@ -176,36 +241,98 @@ func (handler *RecipeHandler) QuickSearch(c *gin.Context) {
 		}
 	}
 
-	// Convertir Tags (String "vegano,facil" -> Array ["vegano", "facil"])
-	var tags []string
-	if tagsStr != "" {
-		splitTags := strings.Split(tagsStr, ",")
-		for _, t := range splitTags {
-			trimmed := strings.TrimSpace(t)
-			if trimmed != "" {
-				tags = append(tags, trimmed)
-			}
+	return dtos.RecipeSearchRequest{
+		Query:            query,
+		Description:      description,
+		DificultyLevel:   difficulty,
+		TotalTime:        totalTime,
+		Tags:             queryList(c, "tags"),
+		ExcludeAllergens: queryList(c, "excludeAllergens"),
+		Diets:            queryList(c, "diets"),
+	}
+}
+
+// queryList lee un parámetro separado por comas ("vegano,facil" -> ["vegano", "facil"]).
+func queryList(c *gin.Context, name string) []string {
+	var values []string
+	for _, value := range strings.Split(c.Query(name), ",") {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			values = append(values, trimmed)
 		}
 	}
+	return values
+}
 
-	filters := dtos.RecipeSearchRequest{
-		Title:          title,
-		Description:    description,
-		DificultyLevel: difficulty,
-		TotalTime:      totalTime,
-		Tags:           tags, // <--- Asignamos al DTO
+func (handler *RecipeHandler) GetScaledRecipe(c *gin.Context) {
+	id := c.Param("id")
+
+	var servings int
+	if servingsStr := c.Query("servings"); servingsStr != "" {
+		parsed, err := strconv.Atoi(servingsStr)
+		if err != nil || parsed < 1 || parsed > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "servings must be a number between 1 and 100"})
+			return
+		}
+		servings = parsed
 	}
 
-	recipes, err := handler.service.GetRecipes(filters)
+	//con auth opcional: el autor puede escalar sus recetas privadas u ocultas
+	requesterId, _ := c.Get("user_id")
+	requesterIdStr, _ := requesterId.(string)
+
+	result, err := handler.service.GetScaledRecipe(id, servings, c.Query("system"), requesterIdStr)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if errors.Is(err, scaling.ErrInvalidSystem) || err.Error() == "recipe has no servings defined" || err.Error() == "invalid id" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err.Error() == "recipe not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, err.Error())
 		return
 	}
+	c.JSON(http.StatusOK, result)
+}
 
-	// Evitar null en la respuesta JSON
-	if recipes == nil {
-		recipes = []dtos.RecipeResponse{}
+func (handler *RecipeHandler) ForkRecipe(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"Error": "User unauthorized"})
+		return
 	}
+	result, err := handler.service.ForkRecipe(c.Param("id"), userID.(string))
+	if err != nil {
+		switch err.Error() {
+		case "invalid id":
+			c.JSON(http.StatusBadRequest, gin.H{"Error": err.Error()})
+		case "recipe not found", "user not found":
+			c.JSON(http.StatusNotFound, gin.H{"Error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
 
-	c.JSON(http.StatusOK, recipes)
+// GetForks lista las versiones derivadas; con ?all=true incluye también las
+// versiones de versiones.
+func (handler *RecipeHandler) GetForks(c *gin.Context) {
+	var page pagination.Request
+	if err := c.ShouldBindQuery(&page); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	result, err := handler.service.GetForks(c.Param("id"), c.Query("all") == "true", page)
+	if err != nil {
+		if pagination.IsRequestError(err) || err.Error() == "invalid id" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
 }
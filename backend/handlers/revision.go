@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"burned/backend/pagination"
+	"burned/backend/services"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RevisionHandler struct {
+	service services.RevisionServiceInterface
+}
+
+func NewRevisionHandler(s services.RevisionServiceInterface) *RevisionHandler {
+	return &RevisionHandler{service: s}
+}
+
+func (handler *RevisionHandler) GetRevisions(c *gin.Context) {
+	requesterId, _ := c.Get("user_id")
+	requesterRole, _ := c.Get("user_role")
+
+	var page pagination.Request
+	if err := c.ShouldBindQuery(&page); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"Error": err.Error()})
+		return
+	}
+	result, err := handler.service.GetRevisions(c.Param("id"), requesterId.(string), requesterRole.(string), page)
+	if err != nil {
+		if pagination.IsRequestError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"Error": err.Error()})
+			return
+		}
+		revisionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (handler *RevisionHandler) GetRevision(c *gin.Context) {
+	number, ok := revisionNumber(c, c.Param("number"))
+	if !ok {
+		return
+	}
+	requesterId, _ := c.Get("user_id")
+	requesterRole, _ := c.Get("user_role")
+	result, err := handler.service.GetRevision(c.Param("id"), number, requesterId.(string), requesterRole.(string))
+	if err != nil {
+		revisionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// DiffRevisions compara ?from=N&to=M
+func (handler *RevisionHandler) DiffRevisions(c *gin.Context) {
+	from, ok := revisionNumber(c, c.Query("from"))
+	if !ok {
+		return
+	}
+	to, ok := revisionNumber(c, c.Query("to"))
+	if !ok {
+		return
+	}
+	requesterId, _ := c.Get("user_id")
+	requesterRole, _ := c.Get("user_role")
+	result, err := handler.service.DiffRevisions(c.Param("id"), from, to, requesterId.(string), requesterRole.(string))
+	if err != nil {
+		revisionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (handler *RevisionHandler) RestoreRevision(c *gin.Context) {
+	number, ok := revisionNumber(c, c.Param("number"))
+	if !ok {
+		return
+	}
+	requesterId, _ := c.Get("user_id")
+	requesterRole, _ := c.Get("user_role")
+	result, err := handler.service.RestoreRevision(c.Param("id"), number, requesterId.(string), requesterRole.(string))
+	if err != nil {
+		revisionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func revisionNumber(c *gin.Context, raw string) (int, bool) {
+	number, err := strconv.Atoi(raw)
+	if err != nil || number < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"Error": "invalid revision number"})
+		return 0, false
+	}
+	return number, true
+}
+
+func revisionError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == "invalid id":
+		c.JSON(http.StatusBadRequest, gin.H{"Error": err.Error()})
+	case strings.HasPrefix(err.Error(), "unauthorized"):
+		c.JSON(http.StatusForbidden, gin.H{"Error": err.Error()})
+	case strings.HasSuffix(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"Error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"Error": err.Error()})
+	}
+}
@@ -2,8 +2,10 @@ package handlers
 
 import (
 	"burned/backend/dtos"
+	"burned/backend/pagination"
 	"burned/backend/services"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -70,8 +72,18 @@ func (handler *SavedRecipeHandler) GetRecipesSavedByUser(c *gin.Context) {
 		return
 	}
 
-	result, err := handler.service.GetRecipesSavedByUser(userIdStr)
+	var page pagination.Request
+	if err := c.ShouldBindQuery(&page); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"Error": err.Error()})
+		return
+	}
+
+	result, err := handler.service.GetRecipesSavedByUser(userIdStr, page)
 	if err != nil {
+		if pagination.IsRequestError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"Error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -99,3 +111,48 @@ func (handler *SavedRecipeHandler) GetTop10MostSaved(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, gin.H{"Result": result})
 }
+
+func (handler *SavedRecipeHandler) UpdateNotes(c *gin.Context) {
+	var notes dtos.RecipeNotesRequest
+	if err := c.ShouldBindJSON(&notes); err != nil {
+		c.JSON(http.StatusBadRequest, err.Error())
+		return
+	}
+	userID, _ := c.Get("user_id")
+	result, err := handler.service.UpdateNotes(userID.(string), c.Param("id"), notes)
+	if err != nil {
+		notesError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (handler *SavedRecipeHandler) GetNotes(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	result, err := handler.service.GetNotes(userID.(string), c.Param("id"))
+	if err != nil {
+		notesError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (handler *SavedRecipeHandler) DeleteNotes(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	if err := handler.service.DeleteNotes(userID.(string), c.Param("id")); err != nil {
+		notesError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"Result": "Notes deleted"})
+}
+
+func notesError(c *gin.Context, err error) {
+	switch {
+	case strings.HasSuffix(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"Error": err.Error()})
+	case strings.HasPrefix(err.Error(), "invalid"), strings.HasPrefix(err.Error(), "step note"), strings.HasPrefix(err.Error(), "substitution"), err.Error() == "notes are empty":
+		c.JSON(http.StatusBadRequest, gin.H{"Error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"Error": err.Error()})
+	}
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"burned/backend/dtos"
+	"burned/backend/services"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ScheduleHandler struct {
+	service services.ScheduleServiceInterface
+}
+
+func NewScheduleHandler(s services.ScheduleServiceInterface) *ScheduleHandler {
+	return &ScheduleHandler{service: s}
+}
+
+// RecipeSchedule devuelve el .ics de una receta para ?serveAt=<RFC 3339>
+// (opcional &servings=N). Con auth opcional para recetas privadas propias.
+func (handler *ScheduleHandler) RecipeSchedule(c *gin.Context) {
+	servings, ok := servingsQuery(c)
+	if !ok {
+		return
+	}
+	requesterId, _ := c.Get("user_id")
+	requesterIdStr, _ := requesterId.(string)
+
+	file, err := handler.service.RecipeSchedule(c.Param("id"), c.Query("serveAt"), servings, requesterIdStr)
+	if err != nil {
+		scheduleError(c, err)
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="`+file.Name+`"`)
+	c.Data(http.StatusOK, file.ContentType, file.Content)
+}
+
+// RecipeTimeline devuelve en JSON cuándo empieza cada paso y cuáles definen
+// el tiempo total. ?serveAt=<RFC 3339> y &servings=N son opcionales.
+func (handler *ScheduleHandler) RecipeTimeline(c *gin.Context) {
+	servings, ok := servingsQuery(c)
+	if !ok {
+		return
+	}
+	requesterId, _ := c.Get("user_id")
+	requesterIdStr, _ := requesterId.(string)
+
+	result, err := handler.service.RecipeTimeline(c.Param("id"), c.Query("serveAt"), servings, requesterIdStr)
+	if err != nil {
+		scheduleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// servingsQuery lee ?servings=; si es inválido ya respondió 400.
+func servingsQuery(c *gin.Context) (int, bool) {
+	servingsStr := c.Query("servings")
+	if servingsStr == "" {
+		return 0, true
+	}
+	parsed, err := strconv.Atoi(servingsStr)
+	if err != nil || parsed < 1 || parsed > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"Error": "servings must be a number between 1 and 100"})
+		return 0, false
+	}
+	return parsed, true
+}
+
+func (handler *ScheduleHandler) CreateSession(c *gin.Context) {
+	var req dtos.CookSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, err.Error())
+		return
+	}
+	userID, _ := c.Get("user_id")
+	result, err := handler.service.CreateSession(req, userID.(string))
+	if err != nil {
+		scheduleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
+func (handler *ScheduleHandler) GetSessions(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	result, err := handler.service.GetSessions(userID.(string))
+	if err != nil {
+		scheduleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (handler *ScheduleHandler) DeleteSession(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	if err := handler.service.DeleteSession(c.Param("id"), userID.(string)); err != nil {
+		scheduleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"Result": "Cook session deleted"})
+}
+
+func (handler *ScheduleHandler) CreateFeed(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	result, err := handler.service.CreateFeed(userID.(string))
+	if err != nil {
+		scheduleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
+func (handler *ScheduleHandler) RevokeFeed(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	if err := handler.service.RevokeFeed(userID.(string)); err != nil {
+		scheduleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"Result": "Calendar feed revoked"})
+}
+
+// Feed es pública: la protege el token de la URL (/calendar/<token>.ics),
+// porque las apps de calendario no mandan el header Authorization.
+func (handler *ScheduleHandler) Feed(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("file"), ".ics")
+	file, err := handler.service.Feed(token)
+	if err != nil {
+		scheduleError(c, err)
+		return
+	}
+	c.Data(http.StatusOK, file.ContentType, file.Content)
+}
+
+func scheduleError(c *gin.Context, err error) {
+	message := err.Error()
+	switch {
+	case strings.HasSuffix(message, "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"Error": message})
+	case strings.HasPrefix(message, "invalid"):
+		c.JSON(http.StatusBadRequest, gin.H{"Error": message})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"Error": message})
+	}
+}
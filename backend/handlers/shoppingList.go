@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"burned/backend/dtos"
+	"burned/backend/scaling"
+	"burned/backend/services"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ShoppingListHandler struct {
+	service services.ShoppingListServiceInterface
+}
+
+func NewShoppingListHandler(s services.ShoppingListServiceInterface) *ShoppingListHandler {
+	return &ShoppingListHandler{service: s}
+}
+
+func (handler *ShoppingListHandler) GetList(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	result, err := handler.service.GetList(userID.(string))
+	if err != nil {
+		shoppingListError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (handler *ShoppingListHandler) Generate(c *gin.Context) {
+	var req dtos.ShoppingListGenerateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, err.Error())
+		return
+	}
+	userID, _ := c.Get("user_id")
+	result, err := handler.service.Generate(req, userID.(string))
+	if err != nil {
+		shoppingListError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (handler *ShoppingListHandler) AddItem(c *gin.Context) {
+	var req dtos.ShoppingItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, err.Error())
+		return
+	}
+	userID, _ := c.Get("user_id")
+	result, err := handler.service.AddItem(req, userID.(string))
+	if err != nil {
+		shoppingListError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
+func (handler *ShoppingListHandler) UpdateItem(c *gin.Context) {
+	var req dtos.ShoppingItemUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, err.Error())
+		return
+	}
+	userID, _ := c.Get("user_id")
+	result, err := handler.service.UpdateItem(c.Param("id"), req, userID.(string))
+	if err != nil {
+		shoppingListError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (handler *ShoppingListHandler) DeleteItem(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	if err := handler.service.DeleteItem(c.Param("id"), userID.(string)); err != nil {
+		shoppingListError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"Result": "Item removed from shopping list"})
+}
+
+func (handler *ShoppingListHandler) ClearChecked(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	result, err := handler.service.ClearChecked(userID.(string))
+	if err != nil {
+		shoppingListError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (handler *ShoppingListHandler) ClearList(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	if err := handler.service.ClearList(userID.(string)); err != nil {
+		shoppingListError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"Result": "Shopping list cleared"})
+}
+
+// Export acepta ?format=txt|csv (txt por defecto).
+func (handler *ShoppingListHandler) Export(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	file, err := handler.service.Export(userID.(string), c.DefaultQuery("format", "txt"))
+	if err != nil {
+		if err.Error() == "unsupported export format" {
+			c.JSON(http.StatusBadRequest, gin.H{"Error": err.Error(), "formats": []string{"txt", "csv"}})
+			return
+		}
+		shoppingListError(c, err)
+		return
+	}
+	sendExportFile(c, file)
+}
+
+func shoppingListError(c *gin.Context, err error) {
+	message := err.Error()
+	switch {
+	case strings.HasSuffix(message, "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"Error": message})
+	case strings.HasPrefix(message, "invalid"), errors.Is(err, scaling.ErrInvalidSystem):
+		c.JSON(http.StatusBadRequest, gin.H{"Error": message})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"Error": message})
+	}
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"burned/backend/dtos"
+	"burned/backend/pagination"
+	"burned/backend/scaling"
+	"burned/backend/services"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SubstitutionHandler struct {
+	service services.SubstitutionServiceInterface
+}
+
+func NewSubstitutionHandler(s services.SubstitutionServiceInterface) *SubstitutionHandler {
+	return &SubstitutionHandler{service: s}
+}
+
+// RecipeSubstitutions acepta ?excludeAllergens=dairy,nuts&diets=vegan y
+// ?system=metric|imperial para las cantidades.
+func (handler *SubstitutionHandler) RecipeSubstitutions(c *gin.Context) {
+	requesterId, _ := c.Get("user_id")
+	requesterIdStr, _ := requesterId.(string)
+	filter := dtos.SubstitutionFilter{
+		ExcludeAllergens: queryList(c, "excludeAllergens"),
+		Diets:            queryList(c, "diets"),
+	}
+	result, err := handler.service.GetRecipeSubstitutions(c.Param("id"), requesterIdStr, filter, c.Query("system"))
+	if err != nil {
+		substitutionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (handler *SubstitutionHandler) GetRules(c *gin.Context) {
+	var page pagination.Request
+	if err := c.ShouldBindQuery(&page); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"Error": err.Error()})
+		return
+	}
+	result, err := handler.service.GetRules(page)
+	if err != nil {
+		substitutionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (handler *SubstitutionHandler) CreateRule(c *gin.Context) {
+	var req dtos.SubstitutionRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, err.Error())
+		return
+	}
+	userID, _ := c.Get("user_id")
+	result, err := handler.service.CreateRule(req, userID.(string))
+	if err != nil {
+		substitutionError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
+func (handler *SubstitutionHandler) UpdateRule(c *gin.Context) {
+	var req dtos.SubstitutionRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, err.Error())
+		return
+	}
+	result, err := handler.service.UpdateRule(c.Param("id"), req)
+	if err != nil {
+		substitutionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (handler *SubstitutionHandler) DeleteRule(c *gin.Context) {
+	if err := handler.service.DeleteRule(c.Param("id")); err != nil {
+		substitutionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"Result": "Substitution rule deleted"})
+}
+
+func substitutionError(c *gin.Context, err error) {
+	message := err.Error()
+	switch {
+	case strings.HasSuffix(message, "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"Error": message})
+	case strings.HasPrefix(message, "invalid"), errors.Is(err, scaling.ErrInvalidSystem), pagination.IsRequestError(err):
+		c.JSON(http.StatusBadRequest, gin.H{"Error": message})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"Error": message})
+	}
+}
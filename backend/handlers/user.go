@@ -76,12 +76,17 @@ func (handler *UserHandler) DeleteUser(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"Error": "Invalid user id type"})
 		return
 	}
-	err := handler.service.DeleteUser(userIdStr)
+	dryRun := c.Query("dryRun") == "true"
+	report, err := handler.service.DeleteUser(userIdStr, dryRun)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"Error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"Result": "It was successfully deleted"})
+	if dryRun {
+		c.JSON(http.StatusOK, report)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"Result": "It was successfully deleted", "Report": report})
 }
 
 func (handler *UserHandler) GetUserById(c *gin.Context) {
@@ -0,0 +1,96 @@
+// Package ical escribe calendarios iCalendar (RFC 5545) con eventos simples.
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"time"
+)
+
+const ContentType = "text/calendar; charset=utf-8"
+
+const (
+	dateTimeLayout = "20060102T150405Z"
+	// maxLineOctets es el largo máximo de línea antes de plegarla (sección 3.1)
+	maxLineOctets = 75
+)
+
+type Calendar struct {
+	ProdID string
+	Name   string
+	Events []Event
+}
+
+// Event es un VEVENT. Si End es igual a Start el evento no ocupa tiempo y se
+// escribe sin DTEND.
+type Event struct {
+	UID         string
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	URL         string
+}
+
+// Bytes serializa el calendario con finales de línea CRLF.
+func (calendar Calendar) Bytes() []byte {
+	var buffer bytes.Buffer
+	stamp := time.Now().UTC().Format(dateTimeLayout)
+
+	writeLine(&buffer, "BEGIN:VCALENDAR")
+	writeLine(&buffer, "VERSION:2.0")
+	writeLine(&buffer, "PRODID:"+calendar.ProdID)
+	writeLine(&buffer, "CALSCALE:GREGORIAN")
+	writeLine(&buffer, "METHOD:PUBLISH")
+	if calendar.Name != "" {
+		writeLine(&buffer, "X-WR-CALNAME:"+Escape(calendar.Name))
+	}
+	for _, event := range calendar.Events {
+		writeLine(&buffer, "BEGIN:VEVENT")
+		writeLine(&buffer, "UID:"+event.UID)
+		writeLine(&buffer, "DTSTAMP:"+stamp)
+		writeLine(&buffer, "DTSTART:"+event.Start.UTC().Format(dateTimeLayout))
+		if event.End.After(event.Start) {
+			writeLine(&buffer, "DTEND:"+event.End.UTC().Format(dateTimeLayout))
+		}
+		writeLine(&buffer, "SUMMARY:"+Escape(event.Summary))
+		if event.Description != "" {
+			writeLine(&buffer, "DESCRIPTION:"+Escape(event.Description))
+		}
+		if event.URL != "" {
+			writeLine(&buffer, "URL:"+event.URL)
+		}
+		writeLine(&buffer, "END:VEVENT")
+	}
+	writeLine(&buffer, "END:VCALENDAR")
+	return buffer.Bytes()
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// Escape escapa un valor TEXT (sección 3.3.11).
+func Escape(value string) string {
+	return escaper.Replace(value)
+}
+
+// writeLine pliega la línea cada 75 octetos sin cortar caracteres UTF-8;
+// las continuaciones empiezan con un espacio.
+func writeLine(buffer *bytes.Buffer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !startsRune(line[cut]) {
+			cut--
+		}
+		buffer.WriteString(line[:cut])
+		buffer.WriteString("\r\n ")
+		line = line[cut:]
+		limit = maxLineOctets - 1
+	}
+	buffer.WriteString(line)
+	buffer.WriteString("\r\n")
+}
+
+func startsRune(b byte) bool {
+	return b&0xC0 != 0x80
+}
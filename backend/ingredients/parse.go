@@ -0,0 +1,289 @@
+package ingredients
+
+import (
+	"burned/backend/models"
+	"burned/backend/units"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var ErrEmptyLine = errors.New("empty ingredient line")
+
+// Parsed es una línea de ingrediente ya separada en sus partes. Si la línea
+// traía un rango ("2-3 tazas") Quantity es el mínimo y QuantityMax el máximo;
+// si no, QuantityMax vale 0.
+type Parsed struct {
+	Input       string  `json:"input"`
+	Quantity    float64 `json:"quantity"`
+	QuantityMax float64 `json:"quantityMax,omitempty"`
+	Unit        string  `json:"unit"`
+	Name        string  `json:"name"`
+	Note        string  `json:"note,omitempty"`
+	Optional    bool    `json:"optional"`
+}
+
+// Ingredient convierte el resultado al modelo de receta. Un rango se guarda
+// con el mínimo como cantidad y el rango completo al principio de la nota.
+func (parsed Parsed) Ingredient() models.Ingredient {
+	note := parsed.Note
+	if parsed.QuantityMax > 0 {
+		rangeText := formatNumber(parsed.Quantity) + "-" + formatNumber(parsed.QuantityMax)
+		note = strings.TrimPrefix(strings.TrimSpace(rangeText+"; "+note), "; ")
+		note = strings.TrimSuffix(note, ";")
+	}
+	return models.Ingredient{
+		Name:     parsed.Name,
+		Quantity: parsed.Quantity,
+		Unit:     parsed.Unit,
+		Note:     note,
+		Optional: parsed.Optional,
+	}
+}
+
+var vulgarFractions = map[rune]string{
+	'½': "1/2", '⅓': "1/3", '⅔': "2/3", '¼': "1/4", '¾': "3/4",
+	'⅕': "1/5", '⅖': "2/5", '⅗': "3/5", '⅘': "4/5", '⅙': "1/6",
+	'⅚': "5/6", '⅛': "1/8", '⅜': "3/8", '⅝': "5/8", '⅞': "7/8",
+}
+
+var numberWords = map[string]float64{
+	"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6,
+	"seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11, "twelve": 12, "dozen": 12, "half": 0.5,
+	"un": 1, "una": 1, "uno": 1, "dos": 2, "tres": 3, "cuatro": 4, "cinco": 5, "seis": 6,
+	"siete": 7, "ocho": 8, "nueve": 9, "diez": 10, "once": 11, "doce": 12, "docena": 12, "medio": 0.5, "media": 0.5,
+}
+
+// rangeWords separan los extremos de un rango: "2 a 3", "2 to 3", "2 o 3".
+var rangeWords = map[string]bool{"-": true, "a": true, "to": true, "o": true, "or": true, "u": true}
+
+// connectors se saltean entre la unidad y el nombre: "2 tazas de harina".
+var connectors = map[string]bool{"de": true, "del": true, "of": true}
+
+var (
+	optionalPattern   = regexp.MustCompile(`(?i)[(\[]?\b(opcional|optional)\b[)\]]?`)
+	toTastePattern    = regexp.MustCompile(`(?i)(\bto taste\b|\ba gusto\b|\bal gusto\b|\bc/n\b|\bcantidad necesaria\b)`)
+	parentheses       = regexp.MustCompile(`\(([^)]*)\)`)
+	attachedUnit      = regexp.MustCompile(`^(\d+(?:[.,]\d+)?)([a-zA-Z]+\.?)$`)
+	noteSeparator     = regexp.MustCompile(`,(\s|$)|;`)
+	numberTokenFormat = regexp.MustCompile(`^\d+(?:[.,]\d+)?$|^\d+/\d+$`)
+)
+
+// ParseLine separa una línea de texto libre ("1 1/2 cups all-purpose flour,
+// sifted", "2 dientes de ajo picados", "sal a gusto") en cantidad, unidad,
+// nombre y nota. Acepta fracciones, rangos, fracciones unicode y números y
+// unidades escritos en español o inglés.
+func ParseLine(line string) (Parsed, error) {
+	parsed := Parsed{Input: line}
+	text := strings.TrimSpace(expandVulgarFractions(line))
+	text = strings.NewReplacer("–", "-", "—", "-").Replace(text)
+	text = strings.TrimLeft(text, "-*•· \t")
+	if text == "" {
+		return parsed, ErrEmptyLine
+	}
+
+	if optionalPattern.MatchString(text) {
+		parsed.Optional = true
+		text = optionalPattern.ReplaceAllString(text, "")
+	}
+	toTaste := false
+	if toTastePattern.MatchString(text) {
+		toTaste = true
+		text = toTastePattern.ReplaceAllString(text, "")
+	}
+
+	var notes []string
+	for _, match := range parentheses.FindAllStringSubmatch(text, -1) {
+		if note := strings.TrimSpace(match[1]); note != "" {
+			notes = append(notes, note)
+		}
+	}
+	text = parentheses.ReplaceAllString(text, " ")
+	if location := noteSeparator.FindStringIndex(text); location != nil {
+		if note := strings.Trim(text[location[1]:], " ,;"); note != "" {
+			notes = append([]string{note}, notes...)
+		}
+		text = text[:location[0]]
+	}
+
+	tokens := tokenize(text)
+	quantity, quantityMax, rest := parseQuantity(tokens)
+	unit, rest := parseUnit(rest, quantity > 0)
+	for len(rest) > 0 && connectors[strings.ToLower(rest[0])] {
+		rest = rest[1:]
+	}
+
+	parsed.Name = strings.Trim(strings.Join(rest, " "), " ,.;:-")
+	parsed.Note = strings.Join(notes, "; ")
+	parsed.Quantity = quantity
+	parsed.QuantityMax = quantityMax
+
+	switch {
+	case toTaste || unit == units.CodeToTaste:
+		parsed.Unit = units.CodeToTaste
+		parsed.Quantity, parsed.QuantityMax = 0, 0
+	case unit != "":
+		parsed.Unit = unit
+	case quantity > 0:
+		parsed.Unit = units.CodeUnit
+	default:
+		parsed.Unit = units.CodeToTaste
+	}
+	if parsed.Name == "" {
+		return parsed, errors.New("ingredient name not found in line")
+	}
+	return parsed, nil
+}
+
+// ParseLines procesa varias líneas ignorando las vacías. Devuelve el primer
+// error junto con el número de línea para que el usuario pueda corregirla.
+func ParseLines(lines []string) ([]Parsed, error) {
+	var result []Parsed
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		parsed, err := ParseLine(line)
+		if err != nil {
+			return nil, errors.New("line " + strconv.Itoa(i+1) + ": " + err.Error())
+		}
+		result = append(result, parsed)
+	}
+	return result, nil
+}
+
+func expandVulgarFractions(text string) string {
+	var builder strings.Builder
+	runes := []rune(text)
+	for i, r := range runes {
+		fraction, ok := vulgarFractions[r]
+		if !ok {
+			builder.WriteRune(r)
+			continue
+		}
+		// "1½" -> "1 1/2"
+		if i > 0 && unicode.IsDigit(runes[i-1]) {
+			builder.WriteRune(' ')
+		}
+		builder.WriteString(fraction)
+		if i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+			builder.WriteRune(' ')
+		}
+	}
+	return builder.String()
+}
+
+// tokenize separa por espacios, aísla los guiones de rango ("2-3") y separa
+// la unidad pegada al número ("200g").
+func tokenize(text string) []string {
+	var tokens []string
+	for _, field := range strings.Fields(text) {
+		if match := attachedUnit.FindStringSubmatch(field); match != nil {
+			if _, ok := units.Find(match[2]); ok {
+				tokens = append(tokens, match[1], match[2])
+				continue
+			}
+		}
+		if parts := strings.Split(field, "-"); len(parts) == 2 && isNumberToken(parts[0]) && isNumberToken(parts[1]) {
+			tokens = append(tokens, parts[0], "-", parts[1])
+			continue
+		}
+		tokens = append(tokens, field)
+	}
+	return tokens
+}
+
+func isNumberToken(token string) bool {
+	return numberTokenFormat.MatchString(token)
+}
+
+// parseNumber lee un número al principio de tokens: entero, decimal con punto
+// o coma, fracción, mixto ("1 1/2") o palabra ("dos", "half").
+func parseNumber(tokens []string) (float64, int) {
+	if len(tokens) == 0 {
+		return 0, 0
+	}
+	first := strings.ToLower(tokens[0])
+	if value, ok := numberWords[first]; ok {
+		// "a" y "un" solo cuentan como número si no son el nombre entero
+		if len(tokens) == 1 {
+			return 0, 0
+		}
+		return value, 1
+	}
+	value, ok := parseNumeric(first)
+	if !ok {
+		return 0, 0
+	}
+	if !strings.Contains(first, "/") && len(tokens) > 1 && strings.Contains(tokens[1], "/") {
+		if fraction, ok := parseNumeric(tokens[1]); ok && fraction < 1 {
+			return value + fraction, 2
+		}
+	}
+	return value, 1
+}
+
+func parseNumeric(token string) (float64, bool) {
+	if !isNumberToken(token) {
+		return 0, false
+	}
+	if numerator, denominator, found := strings.Cut(token, "/"); found {
+		n, err := strconv.ParseFloat(numerator, 64)
+		if err != nil {
+			return 0, false
+		}
+		d, err := strconv.ParseFloat(denominator, 64)
+		if err != nil || d == 0 {
+			return 0, false
+		}
+		return n / d, true
+	}
+	value, err := strconv.ParseFloat(strings.Replace(token, ",", ".", 1), 64)
+	return value, err == nil
+}
+
+func parseQuantity(tokens []string) (float64, float64, []string) {
+	quantity, used := parseNumber(tokens)
+	if used == 0 {
+		return 0, 0, tokens
+	}
+	rest := tokens[used:]
+	if len(rest) > 1 && rangeWords[strings.ToLower(rest[0])] {
+		if upper, usedUpper := parseNumber(rest[1:]); usedUpper > 0 && upper > quantity {
+			return quantity, upper, rest[1+usedUpper:]
+		}
+	}
+	return quantity, 0, rest
+}
+
+// parseUnit busca la unidad más larga (hasta tres palabras) al principio de
+// tokens. Las abreviaturas de una letra ("g", "l", "u") solo se aceptan
+// después de una cantidad, para no confundirlas con el nombre.
+func parseUnit(tokens []string, afterQuantity bool) (string, []string) {
+	for size := 3; size >= 1; size-- {
+		if len(tokens) < size {
+			continue
+		}
+		candidate := strings.Join(tokens[:size], " ")
+		if !afterQuantity && len(strings.TrimSuffix(candidate, ".")) <= 1 {
+			continue
+		}
+		unit, ok := units.Find(candidate)
+		if !ok {
+			continue
+		}
+		// "2 l" sí, pero en "2 limones" no hay unidad; Find ya exige la palabra completa
+		if len(tokens) == size && unit.Dimension != units.ToTaste {
+			// sin nombre después: "3 unidades" a secas no tiene sentido
+			return "", tokens
+		}
+		return unit.Code, tokens[size:]
+	}
+	return "", tokens
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
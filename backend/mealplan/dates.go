@@ -0,0 +1,60 @@
+// Package mealplan tiene las reglas de fechas y comidas del planificador.
+package mealplan
+
+import (
+	"errors"
+	"time"
+)
+
+const DateLayout = "2006-01-02"
+
+// MaxRange es el rango máximo de días que se puede pedir de una vez.
+const MaxRange = 62
+
+var ErrInvalidDate = errors.New("invalid date, expected YYYY-MM-DD")
+
+// Slots son las comidas del día, en el orden en que se muestran.
+var Slots = []string{"breakfast", "lunch", "dinner", "snack"}
+
+func ValidSlot(slot string) bool {
+	for _, candidate := range Slots {
+		if candidate == slot {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseDate interpreta una fecha de calendario, sin hora ni zona.
+func ParseDate(value string) (time.Time, error) {
+	date, err := time.Parse(DateLayout, value)
+	if err != nil {
+		return time.Time{}, ErrInvalidDate
+	}
+	return date, nil
+}
+
+func FormatDate(date time.Time) string {
+	return date.Format(DateLayout)
+}
+
+// WeekStart devuelve el lunes de la semana de la fecha.
+func WeekStart(date time.Time) time.Time {
+	offset := (int(date.Weekday()) + 6) % 7
+	return date.AddDate(0, 0, -offset)
+}
+
+// Week devuelve el lunes y el domingo de la semana de la fecha.
+func Week(date time.Time) (string, string) {
+	start := WeekStart(date)
+	return FormatDate(start), FormatDate(start.AddDate(0, 0, 6))
+}
+
+// ShiftDate mueve una fecha "YYYY-MM-DD" la cantidad de días indicada.
+func ShiftDate(value string, days int) (string, error) {
+	date, err := ParseDate(value)
+	if err != nil {
+		return "", err
+	}
+	return FormatDate(date.AddDate(0, 0, days)), nil
+}
@@ -0,0 +1,25 @@
+package middlewares
+
+import (
+	"burned/backend/auth"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OptionalAuthMiddleware carga la identidad si viene un token válido pero no
+// corta la request si falta: sirve para rutas públicas que muestran más
+// datos al dueño (por ejemplo, exportar una receta privada).
+func OptionalAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenParts := strings.Split(c.GetHeader("Authorization"), " ")
+		if len(tokenParts) == 2 && tokenParts[0] == "Bearer" {
+			if claims, err := auth.ValidateToken(tokenParts[1]); err == nil {
+				c.Set("user_id", claims.UserID)
+				c.Set("user_email", claims.Email)
+				c.Set("user_role", claims.Role)
+			}
+		}
+		c.Next()
+	}
+}
@@ -17,3 +17,16 @@ func CheckUser() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// CheckAdmin deja pasar solo a los admins. Va después de AuthMiddleware.
+func CheckAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, ok := c.Get("user_role")
+		if !ok || role != "admin" {
+			c.JSON(http.StatusForbidden, gin.H{"Error": "admin only"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
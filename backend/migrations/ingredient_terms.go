@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"burned/backend/models"
+	"burned/backend/shopping"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// backfillIngredientTerms calcula terms en los ingredientes de las recetas
+// guardadas antes de la búsqueda por despensa.
+func backfillIngredientTerms(ctx context.Context, db *mongo.Database) error {
+	collection := db.Collection("Recipe")
+
+	filter := bson.M{"ingredients": bson.M{"$elemMatch": bson.M{"terms": bson.M{"$exists": false}}}}
+	opts := options.Find().SetProjection(bson.M{"ingredients": 1})
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var recipe struct {
+			ID          primitive.ObjectID  `bson:"_id"`
+			Ingredients []models.Ingredient `bson:"ingredients"`
+		}
+		if err := cursor.Decode(&recipe); err != nil {
+			return err
+		}
+		for i := range recipe.Ingredients {
+			recipe.Ingredients[i].Terms = shopping.Terms(recipe.Ingredients[i].Name)
+		}
+		update := bson.M{"$set": bson.M{"ingredients": recipe.Ingredients}}
+		if _, err := collection.UpdateOne(ctx, bson.M{"_id": recipe.ID}, update); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
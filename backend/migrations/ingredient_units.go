@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"burned/backend/units"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// backfillIngredientUnits completa los ingredientes guardados antes de que
+// existiera Unit. Con las mismas reglas que RecipeRequest.Validate, una
+// cantidad sin unidad es un conteo y una cantidad 0 es "a gusto".
+func backfillIngredientUnits(ctx context.Context, db *mongo.Database) error {
+	collection := db.Collection("Recipe")
+
+	filter := bson.M{"ingredients": bson.M{"$elemMatch": bson.M{"unit": bson.M{"$exists": false}}}}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"ingredients": bson.M{"$map": bson.M{
+				"input": "$ingredients",
+				"in": bson.M{"$mergeObjects": bson.A{
+					bson.M{
+						"unit": bson.M{"$cond": bson.A{
+							bson.M{"$gt": bson.A{"$$this.quantity", 0}},
+							units.CodeUnit,
+							units.CodeToTaste,
+						}},
+						"note":     "",
+						"optional": false,
+					},
+					"$$this",
+				}},
+			}},
+		}}},
+	}
+
+	_, err := collection.UpdateMany(ctx, filter, update)
+	return err
+}
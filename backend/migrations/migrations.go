@@ -0,0 +1,106 @@
+package migrations
+
+import (
+	"burned/backend/database"
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Migration es un cambio de datos que se aplica una sola vez. Las ya
+// aplicadas quedan registradas en la colección Migration por su ID, así que
+// un ID nunca se reutiliza ni se renombra.
+type Migration struct {
+	ID          string
+	Description string
+	Up          func(ctx context.Context, db *mongo.Database) error
+}
+
+// all se aplica en orden; las nuevas migraciones van al final.
+var all = []Migration{
+	{
+		ID:          "0001_ingredient_units",
+		Description: "completa unit, note y optional en los ingredientes existentes",
+		Up:          backfillIngredientUnits,
+	},
+	{
+		ID:          "0002_saved_collections",
+		Description: "pasa las recetas guardadas a la colección \"Saved\" de cada usuario",
+		Up:          migrateSavedToCollections,
+	},
+	{
+		ID:          "0003_ingredient_terms",
+		Description: "calcula terms en los ingredientes para la búsqueda por despensa",
+		Up:          backfillIngredientTerms,
+	},
+	{
+		ID:          "0004_recipe_nutrition",
+		Description: "calcula la información nutricional de las recetas existentes",
+		Up:          backfillRecipeNutrition,
+	},
+	{
+		ID:          "0005_recipe_dietary",
+		Description: "clasifica alérgenos y dietas de las recetas existentes",
+		Up:          backfillRecipeDietary,
+	},
+	{
+		ID:          "0006_substitution_rules",
+		Description: "carga las reglas de reemplazo de ingredientes de fábrica",
+		Up:          seedSubstitutionRules,
+	},
+	{
+		ID:          "0007_recipe_total_time",
+		Description: "recalcula el tiempo total de las recetas a partir de sus pasos",
+		Up:          recomputeRecipeTotalTime,
+	},
+	{
+		ID:          "0008_recipe_dietary_unclassified",
+		Description: "vuelve a clasificar las recetas marcando los ingredientes que no se reconocen",
+		Up:          reclassifyRecipeDietary,
+	},
+	{
+		ID:          "0009_substitution_rules_unclassified",
+		Description: "vuelve a clasificar los reemplazos marcando las partes que no se reconocen",
+		Up:          rederiveSubstitutionRules,
+	},
+	{
+		ID:          "0010_recipe_total_time_dependencies",
+		Description: "recalcula el tiempo total con los pasos sin dependencias detrás del anterior",
+		Up:          recomputeRecipeTotalTime,
+	},
+}
+
+type appliedMigration struct {
+	ID        string    `bson:"_id"`
+	AppliedAt time.Time `bson:"appliedAt"`
+}
+
+// Run aplica las migraciones pendientes. Se detiene en la primera que falla
+// para no aplicar las siguientes sobre datos a medio migrar.
+func Run(db database.DB) error {
+	ctx := context.Background()
+	burned := db.GetClient().Database("Burned")
+	registry := burned.Collection("Migration")
+
+	for _, migration := range all {
+		count, err := registry.CountDocuments(ctx, bson.M{"_id": migration.ID})
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+
+		log.Printf("Aplicando migración %s: %s", migration.ID, migration.Description)
+		if err := migration.Up(ctx, burned); err != nil {
+			return err
+		}
+		if _, err := registry.InsertOne(ctx, appliedMigration{ID: migration.ID, AppliedAt: time.Now()}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package migrations
+
+import (
+	"burned/backend/dietary"
+	"burned/backend/models"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// backfillRecipeDietary calcula alérgenos y dietas de las recetas guardadas
+// antes de la clasificación.
+func backfillRecipeDietary(ctx context.Context, db *mongo.Database) error {
+	return classifyRecipes(ctx, db, bson.M{"allergens": bson.M{"$exists": false}})
+}
+
+// reclassifyRecipeDietary vuelve a clasificar todas las recetas desde que
+// los ingredientes que no se reconocen dejan la receta sin dietas: las
+// etiquetas anteriores los daban por libres de todo.
+func reclassifyRecipeDietary(ctx context.Context, db *mongo.Database) error {
+	return classifyRecipes(ctx, db, bson.M{})
+}
+
+func classifyRecipes(ctx context.Context, db *mongo.Database, filter bson.M) error {
+	collection := db.Collection("Recipe")
+
+	opts := options.Find().SetProjection(bson.M{"ingredients.name": 1, "ingredients.unit": 1})
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var recipe struct {
+			ID          primitive.ObjectID  `bson:"_id"`
+			Ingredients []models.Ingredient `bson:"ingredients"`
+		}
+		if err := cursor.Decode(&recipe); err != nil {
+			return err
+		}
+		allergens, diets, unclassified := dietary.Classify(recipe.Ingredients)
+		update := bson.M{"$set": bson.M{"allergens": allergens, "diets": diets, "unclassified": unclassified}}
+		if _, err := collection.UpdateOne(ctx, bson.M{"_id": recipe.ID}, update); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
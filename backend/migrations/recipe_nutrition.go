@@ -0,0 +1,60 @@
+package migrations
+
+import (
+	"burned/backend/models"
+	"burned/backend/nutrition"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// backfillRecipeNutrition calcula la información nutricional de las recetas
+// guardadas antes del cálculo, con la base incluida en el backend, y registra
+// los ingredientes sin datos para que los revisen los admins.
+func backfillRecipeNutrition(ctx context.Context, db *mongo.Database) error {
+	collection := db.Collection("Recipe")
+	unmatched := db.Collection("UnmatchedIngredient")
+	foods := nutrition.Default()
+
+	filter := bson.M{"nutrition": bson.M{"$exists": false}}
+	opts := options.Find().SetProjection(bson.M{"ingredients": 1, "servings": 1})
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var recipe struct {
+			ID          primitive.ObjectID  `bson:"_id"`
+			Ingredients []models.Ingredient `bson:"ingredients"`
+			Servings    int                 `bson:"servings"`
+		}
+		if err := cursor.Decode(&recipe); err != nil {
+			return err
+		}
+		result, misses := nutrition.Calculate(foods, recipe.Ingredients, recipe.Servings)
+		if _, err := collection.UpdateOne(ctx, bson.M{"_id": recipe.ID}, bson.M{"$set": bson.M{"nutrition": result}}); err != nil {
+			return err
+		}
+		for _, miss := range misses {
+			if miss.Key == "" {
+				continue
+			}
+			now := time.Now()
+			update := bson.M{
+				"$set":         bson.M{"name": miss.Name, "reason": miss.Reason, "unit": miss.Unit, "lastRecipeId": recipe.ID, "lastSeen": now},
+				"$inc":         bson.M{"occurrences": 1},
+				"$setOnInsert": bson.M{"firstSeen": now},
+			}
+			if _, err := unmatched.UpdateOne(ctx, bson.M{"key": miss.Key}, update, options.Update().SetUpsert(true)); err != nil {
+				return err
+			}
+		}
+	}
+	return cursor.Err()
+}
@@ -0,0 +1,48 @@
+package migrations
+
+import (
+	"burned/backend/models"
+	"burned/backend/timeline"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// recomputeRecipeTotalTime reemplaza el tiempo cargado a mano por el que sale
+// de los pasos. Las recetas con algún paso sin tiempo conservan el suyo.
+func recomputeRecipeTotalTime(ctx context.Context, db *mongo.Database) error {
+	collection := db.Collection("Recipe")
+
+	opts := options.Find().SetProjection(bson.M{"step": 1, "totalTime": 1})
+	cursor, err := collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var recipe struct {
+			ID        primitive.ObjectID `bson:"_id"`
+			Step      []models.Step      `bson:"step"`
+			TotalTime int                `bson:"totalTime"`
+		}
+		if err := cursor.Decode(&recipe); err != nil {
+			return err
+		}
+		if !timeline.Timed(recipe.Step) {
+			continue
+		}
+		total := timeline.Schedule(recipe.Step).Total
+		if total == recipe.TotalTime {
+			continue
+		}
+		update := bson.M{"$set": bson.M{"totalTime": total}}
+		if _, err := collection.UpdateOne(ctx, bson.M{"_id": recipe.ID}, update); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
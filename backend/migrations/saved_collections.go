@@ -0,0 +1,74 @@
+package migrations
+
+import (
+	"burned/backend/models"
+	"burned/backend/repositories"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// migrateSavedToCollections crea la colección "Saved" de cada usuario con lo
+// que ya tenía guardado, en el orden en que lo guardó. SavedRecipes se
+// conserva: sigue siendo la fuente de savedCount y de /saved-recipes.
+func migrateSavedToCollections(ctx context.Context, db *mongo.Database) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$sort", Value: bson.D{{Key: "createdAt", Value: 1}}}},
+		//una receta guardada dos veces entra una sola vez, con la fecha más vieja
+		{{Key: "$group", Value: bson.M{
+			"_id":     bson.M{"userId": "$userId", "recipeId": "$recipeId"},
+			"addedAt": bson.M{"$first": "$createdAt"},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "addedAt", Value: 1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$_id.userId",
+			"items": bson.M{"$push": bson.M{"recipeId": "$_id.recipeId", "addedAt": "$addedAt"}},
+		}}},
+	}
+	cursor, err := db.Collection("SavedRecipes").Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var saved []struct {
+		UserID primitive.ObjectID      `bson:"_id"`
+		Items  []models.CollectionItem `bson:"items"`
+	}
+	if err := cursor.All(ctx, &saved); err != nil {
+		return err
+	}
+
+	collections := db.Collection("RecipeCollection")
+	now := time.Now()
+	for _, user := range saved {
+		count, err := collections.CountDocuments(ctx, bson.M{"userId": user.UserID, "isDefault": true})
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		token, err := repositories.NewShareToken()
+		if err != nil {
+			return err
+		}
+		_, err = collections.InsertOne(ctx, models.RecipeCollection{
+			UserID:     user.UserID,
+			Name:       models.DefaultCollectionName,
+			Visibility: "private",
+			IsDefault:  true,
+			ShareToken: token,
+			Items:      user.Items,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
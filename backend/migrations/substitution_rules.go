@@ -0,0 +1,52 @@
+package migrations
+
+import (
+	"burned/backend/models"
+	"burned/backend/substitutions"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// seedSubstitutionRules carga las reglas de reemplazo de fábrica. Corre una
+// sola vez: lo que los admins borren o editen después no se vuelve a pisar.
+func seedSubstitutionRules(ctx context.Context, db *mongo.Database) error {
+	collection := db.Collection("SubstitutionRule")
+	now := time.Now()
+	documents := make([]interface{}, 0, len(substitutions.Defaults))
+	for _, rule := range substitutions.Defaults {
+		substitutions.Derive(&rule)
+		rule.CreatedAt = now
+		rule.UpdatedAt = now
+		documents = append(documents, rule)
+	}
+	_, err := collection.InsertMany(ctx, documents)
+	return err
+}
+
+// rederiveSubstitutionRules vuelve a calcular alérgenos y dietas de las
+// reglas guardadas desde que las partes que no se reconocen dejan el
+// reemplazo sin dietas.
+func rederiveSubstitutionRules(ctx context.Context, db *mongo.Database) error {
+	collection := db.Collection("SubstitutionRule")
+	cursor, err := collection.Find(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var rule models.SubstitutionRule
+		if err := cursor.Decode(&rule); err != nil {
+			return err
+		}
+		substitutions.Derive(&rule)
+		update := bson.M{"$set": bson.M{"allergens": rule.Allergens, "diets": rule.Diets, "unclassified": rule.Unclassified}}
+		if _, err := collection.UpdateOne(ctx, bson.M{"_id": rule.ID}, update); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
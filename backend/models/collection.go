@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const DefaultCollectionName = "Saved"
+
+// RecipeCollection es un recetario armado por el usuario. Los ítems se
+// guardan en el orden que el usuario eligió. Visibility es "public",
+// "private" o "unlisted" (solo accesible con el link de ShareToken).
+type RecipeCollection struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID      primitive.ObjectID `bson:"userId" json:"userId"`
+	Name        string             `bson:"name" json:"name"`
+	Description string             `bson:"description" json:"description"`
+	CoverImage  string             `bson:"coverImage" json:"coverImage"`
+	Visibility  string             `bson:"visibility" json:"visibility"`
+	IsDefault   bool               `bson:"isDefault" json:"isDefault"` // la colección "Saved", espejo de SavedRecipes
+	ShareToken  string             `bson:"shareToken" json:"-"`
+	Items       []CollectionItem   `bson:"items" json:"items"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+type CollectionItem struct {
+	RecipeID primitive.ObjectID `bson:"recipeId" json:"recipeId"`
+	AddedAt  time.Time          `bson:"addedAt" json:"addedAt"`
+}
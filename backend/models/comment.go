@@ -6,11 +6,40 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// MaxCommentDepth es el nivel más profundo de respuesta: 0 son los
+// comentarios de la receta, 1 sus respuestas, y así.
+const MaxCommentDepth = 4
+
+// DeletedCommentText reemplaza el texto de un comentario borrado que tenía
+// respuestas, para que el hilo siga teniendo de dónde colgar.
+const DeletedCommentText = "[deleted]"
+
+// HiddenCommentText es lo que ven los demás de un comentario ocultado por
+// moderación.
+const HiddenCommentText = "[hidden]"
+
 type Comment struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	UserID    primitive.ObjectID `bson:"userId" json:"userId"`
-	UserName  string             `bson:"userName" json:"userName"`
-	RecipeID  primitive.ObjectID `bson:"recipeId" json:"recipeId"`
-	Text      string             `bson:"text" json:"text"`
-	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	ID         primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	UserID     primitive.ObjectID  `bson:"userId" json:"userId"`
+	UserName   string              `bson:"userName" json:"userName"`
+	RecipeID   primitive.ObjectID  `bson:"recipeId" json:"recipeId"`
+	ParentID   *primitive.ObjectID `bson:"parentId,omitempty" json:"parentId,omitempty"`
+	Depth      int                 `bson:"depth" json:"depth"`
+	ReplyCount int                 `bson:"replyCount" json:"replyCount"` // respuestas directas
+	Deleted    bool                `bson:"deleted,omitempty" json:"deleted"`
+	Hidden     bool                `bson:"hidden,omitempty" json:"hidden"` // oculto por moderación
+	Text       string              `bson:"text" json:"text"`
+	CreatedAt  time.Time           `bson:"createdAt" json:"createdAt"`
+	EditedAt   *time.Time          `bson:"editedAt,omitempty" json:"editedAt,omitempty"`
+}
+
+// CommentEdit guarda el texto que tenía un comentario antes de una edición.
+// WrittenAt es cuándo se escribió ese texto y ReplacedAt cuándo se cambió.
+type CommentEdit struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CommentID  primitive.ObjectID `bson:"commentId" json:"commentId"`
+	RecipeID   primitive.ObjectID `bson:"recipeId" json:"recipeId"`
+	Text       string             `bson:"text" json:"text"`
+	WrittenAt  time.Time          `bson:"writtenAt" json:"writtenAt"`
+	ReplacedAt time.Time          `bson:"replacedAt" json:"replacedAt"`
 }
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CookSession es una receta que el usuario planea tener lista a ServeAt.
+// Aparece en su feed de calendario con un evento por paso.
+type CookSession struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    primitive.ObjectID `bson:"userId" json:"userId"`
+	RecipeID  primitive.ObjectID `bson:"recipeId" json:"recipeId"`
+	ServeAt   time.Time          `bson:"serveAt" json:"serveAt"`
+	Servings  int                `bson:"servings" json:"servings"` // 0 usa las porciones de la receta
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// CalendarFeed es el acceso al feed .ics de un usuario. Solo se guarda el
+// hash del token: revocarlo o regenerarlo no toca la contraseña.
+type CalendarFeed struct {
+	UserID    primitive.ObjectID `bson:"_id"`
+	TokenHash string             `bson:"tokenHash"`
+	CreatedAt time.Time          `bson:"createdAt"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Follow es la relación "FollowerID sigue a FolloweeID".
+type Follow struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	FollowerID primitive.ObjectID `bson:"followerId" json:"followerId"`
+	FolloweeID primitive.ObjectID `bson:"followeeId" json:"followeeId"`
+	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// Tipos de actividad del feed
+const (
+	FeedRecipe  = "recipe"
+	FeedComment = "comment"
+)
+
+// FeedItem es una entrada del feed de OwnerID: la actividad de ActorID, a
+// quien sigue. Se escribe una por seguidor al publicar (fan-out en la
+// escritura), así leer un feed es una sola consulta por OwnerID. Solo guarda
+// referencias; la receta o el comentario se leen al armar la página.
+type FeedItem struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	OwnerID   primitive.ObjectID `bson:"ownerId" json:"ownerId"`
+	ActorID   primitive.ObjectID `bson:"actorId" json:"actorId"`
+	Type      string             `bson:"type" json:"type"`
+	TargetID  primitive.ObjectID `bson:"targetId" json:"targetId"` // la receta o el comentario
+	RecipeID  primitive.ObjectID `bson:"recipeId" json:"recipeId"` // la receta, también en los comentarios
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MealPlanEntry asigna una receta a un día y una comida. Date es una fecha de
+// calendario "YYYY-MM-DD", así el plan no depende de la zona horaria y los
+// rangos se consultan comparando strings.
+type MealPlanEntry struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    primitive.ObjectID `bson:"userId" json:"userId"`
+	RecipeID  primitive.ObjectID `bson:"recipeId" json:"recipeId"`
+	Date      string             `bson:"date" json:"date"`
+	Slot      string             `bson:"slot" json:"slot"`         // breakfast | lunch | dinner | snack
+	Servings  int                `bson:"servings" json:"servings"` // 0 usa las porciones de la receta
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tipos de contenido que se pueden denunciar
+const (
+	TargetComment = "comment"
+	TargetRecipe  = "recipe"
+)
+
+// ReportReasons son los motivos que acepta una denuncia; "other" pide detalle.
+var ReportReasons = []string{"spam", "offensive", "harassment", "inappropriate", "misinformation", "other"}
+
+// ReasonAutomatic marca los casos que abrió la moderación automática
+// (backend/screening) en lugar de una denuncia.
+const ReasonAutomatic = "automatic"
+
+// Estados de un caso de moderación
+const (
+	CaseOpen     = "open"
+	CaseResolved = "resolved"
+)
+
+// Acciones que puede tomar un moderador sobre un caso
+const (
+	ActionHide    = "hide"
+	ActionDelete  = "delete"
+	ActionDismiss = "dismiss"
+	ActionWarn    = "warn"
+)
+
+// Report es la denuncia de un usuario. Cada usuario denuncia un mismo
+// contenido una sola vez.
+type Report struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CaseID     primitive.ObjectID `bson:"caseId,omitempty" json:"caseId"`
+	TargetType string             `bson:"targetType" json:"targetType"`
+	TargetID   primitive.ObjectID `bson:"targetId" json:"targetId"`
+	RecipeID   primitive.ObjectID `bson:"recipeId" json:"recipeId"` // la receta denunciada o la del comentario
+	ReporterID primitive.ObjectID `bson:"reporterId" json:"reporterId"`
+	Reason     string             `bson:"reason" json:"reason"`
+	Details    string             `bson:"details,omitempty" json:"details,omitempty"`
+	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// ModerationCase agrupa las denuncias abiertas de un mismo contenido: es un
+// elemento de la cola. Hay a lo sumo un caso abierto por contenido.
+type ModerationCase struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TargetType   string             `bson:"targetType" json:"targetType"`
+	TargetID     primitive.ObjectID `bson:"targetId" json:"targetId"`
+	RecipeID     primitive.ObjectID `bson:"recipeId" json:"recipeId"`
+	AuthorID     primitive.ObjectID `bson:"authorId" json:"authorId"`
+	Status       string             `bson:"status" json:"status"`
+	ReportCount  int                `bson:"reportCount" json:"reportCount"`
+	Reasons      []string           `bson:"reasons" json:"reasons"`
+	AutoHidden   bool               `bson:"autoHidden,omitempty" json:"autoHidden"`       // se ocultó solo, por denuncias o por la moderación automática
+	Findings     []string           `bson:"findings,omitempty" json:"findings,omitempty"` // lo que encontró la moderación automática
+	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
+	LastReportAt time.Time          `bson:"lastReportAt" json:"lastReportAt"`
+	Action       string             `bson:"action,omitempty" json:"action,omitempty"`
+	ResolvedBy   primitive.ObjectID `bson:"resolvedBy,omitempty" json:"resolvedBy,omitempty"`
+	ResolvedAt   *time.Time         `bson:"resolvedAt,omitempty" json:"resolvedAt,omitempty"`
+}
+
+// ModerationDecision registra cada acción de un moderador; no se borra
+// aunque el contenido o el caso desaparezcan.
+type ModerationDecision struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CaseID      primitive.ObjectID `bson:"caseId" json:"caseId"`
+	TargetType  string             `bson:"targetType" json:"targetType"`
+	TargetID    primitive.ObjectID `bson:"targetId" json:"targetId"`
+	AuthorID    primitive.ObjectID `bson:"authorId" json:"authorId"`
+	ModeratorID primitive.ObjectID `bson:"moderatorId" json:"moderatorId"`
+	Action      string             `bson:"action" json:"action"`
+	Note        string             `bson:"note,omitempty" json:"note,omitempty"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// UserWarning es un aviso de moderación al autor de un contenido.
+type UserWarning struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID     primitive.ObjectID `bson:"userId" json:"userId"`
+	CaseID     primitive.ObjectID `bson:"caseId" json:"caseId"`
+	TargetType string             `bson:"targetType" json:"targetType"`
+	TargetID   primitive.ObjectID `bson:"targetId" json:"targetId"`
+	Note       string             `bson:"note,omitempty" json:"note,omitempty"`
+	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`
+}
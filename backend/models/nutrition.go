@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Nutrients son los valores nutricionales de una cantidad de comida. Energía
+// en kcal, macronutrientes en gramos y minerales y vitaminas en miligramos.
+type Nutrients struct {
+	Calories      float64 `bson:"calories" json:"calories"`
+	Protein       float64 `bson:"protein" json:"protein"`
+	Fat           float64 `bson:"fat" json:"fat"`
+	SaturatedFat  float64 `bson:"saturatedFat" json:"saturatedFat"`
+	Carbohydrates float64 `bson:"carbohydrates" json:"carbohydrates"`
+	Sugar         float64 `bson:"sugar" json:"sugar"`
+	Fiber         float64 `bson:"fiber" json:"fiber"`
+	Sodium        float64 `bson:"sodium" json:"sodium"`
+	Calcium       float64 `bson:"calcium" json:"calcium"`
+	Iron          float64 `bson:"iron" json:"iron"`
+	Potassium     float64 `bson:"potassium" json:"potassium"`
+	VitaminC      float64 `bson:"vitaminC" json:"vitaminC"`
+}
+
+// RecipeNutrition es la información nutricional calculada de una receta.
+// Counted son los ingredientes que aportan (no cuentan los "a gusto");
+// Unmatched, los que no se pudieron calcular y por eso faltan en el total.
+type RecipeNutrition struct {
+	Total        Nutrients  `bson:"total" json:"total"`
+	PerServing   *Nutrients `bson:"perServing,omitempty" json:"perServing,omitempty"` // nil si la receta no indica porciones
+	Matched      int        `bson:"matched" json:"matched"`
+	Counted      int        `bson:"counted" json:"counted"`
+	Unmatched    []string   `bson:"unmatched,omitempty" json:"unmatched,omitempty"`
+	CalculatedAt time.Time  `bson:"calculatedAt" json:"calculatedAt"`
+}
+
+// UnmatchedIngredient es un nombre de ingrediente que el cálculo nutricional
+// no pudo resolver, para que un admin lo revise. Reason es "unknown" si no
+// hay alimento que coincida y "unit" si coincide pero la unidad no se puede
+// pasar a gramos.
+type UnmatchedIngredient struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Key          string             `bson:"key" json:"key"`
+	Name         string             `bson:"name" json:"name"` // como lo escribió el último autor
+	Reason       string             `bson:"reason" json:"reason"`
+	Unit         string             `bson:"unit,omitempty" json:"unit,omitempty"`
+	Occurrences  int64              `bson:"occurrences" json:"occurrences"`
+	LastRecipeID primitive.ObjectID `bson:"lastRecipeId" json:"lastRecipeId"`
+	FirstSeen    time.Time          `bson:"firstSeen" json:"firstSeen"`
+	LastSeen     time.Time          `bson:"lastSeen" json:"lastSeen"`
+}
+
+// NutritionAlias asocia un nombre de ingrediente a un alimento de la base
+// nutricional. Los cargan los admins al revisar los nombres sin resolver y
+// se suman a los alias del CSV.
+type NutritionAlias struct {
+	Key       string             `bson:"_id" json:"key"`
+	Name      string             `bson:"name" json:"name"`
+	Food      string             `bson:"food" json:"food"`
+	CreatedBy primitive.ObjectID `bson:"createdBy" json:"createdBy"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PantryItem es un ingrediente que el usuario tiene en casa. La cantidad es
+// orientativa: para buscar recetas alcanza con tenerlo.
+type PantryItem struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    primitive.ObjectID `bson:"userId" json:"userId"`
+	Name      string             `bson:"name" json:"name"`
+	Key       string             `bson:"key" json:"-"` // nombre normalizado, ver shopping.Key
+	Quantity  float64            `bson:"quantity" json:"quantity"`
+	Unit      string             `bson:"unit" json:"unit"`                               // código del catálogo de backend/units, vacío si no se indicó
+	ExpiresAt string             `bson:"expiresAt,omitempty" json:"expiresAt,omitempty"` // YYYY-MM-DD, vacío si no vence
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
@@ -7,14 +7,22 @@ import (
 )
 
 type Ingredient struct {
-	Name     string  `bson:"name" json:"name"`
-	Quantity float64 `bson:"quantity" json:"quantity"`
+	Name     string   `bson:"name" json:"name"`
+	Quantity float64  `bson:"quantity" json:"quantity"`
+	Unit     string   `bson:"unit" json:"unit"`           // código del catálogo de backend/units
+	Note     string   `bson:"note" json:"note"`           // preparación: "picada", "a temperatura ambiente"
+	Optional bool     `bson:"optional" json:"optional"`   // se puede omitir sin arruinar la receta
+	Display  string   `bson:"-" json:"display,omitempty"` // cantidad legible, solo en recetas escaladas
+	Terms    []string `bson:"terms,omitempty" json:"-"`   // frases del nombre para buscar por despensa, ver shopping.Terms
 }
 
 type Step struct {
 	Title       string `bson:"title" json:"title"`
 	Descripcion string `bson:"descripcion" json:"descripcion"`
-	Time        int    `bson:"time" json:"time"` // minutos para este paso
+	Time        int    `bson:"time" json:"time"`                                   // minutos para este paso
+	Ingredients []int  `bson:"ingredients,omitempty" json:"ingredients,omitempty"` // posiciones en Recipe.Ingredients que usa el paso
+	DependsOn   []int  `bson:"dependsOn,omitempty" json:"dependsOn,omitempty"`     // pasos anteriores que tienen que estar terminados, ver timeline.Dependencies
+	Passive     bool   `bson:"passive,omitempty" json:"passive"`                   // no necesita atención: reposar, leudar, hornear
 }
 
 type Recipe struct {
@@ -22,14 +30,36 @@ type Recipe struct {
 	UserID         primitive.ObjectID `bson:"userId" json:"userId"`
 	Title          string             `bson:"title" json:"title"`
 	Description    string             `bson:"description" json:"description"`
-	Visibility     string             `bson:"visibility" json:"visibility"` // "public" | "private"
+	Visibility     string             `bson:"visibility" json:"visibility"`   // "public" | "private"
+	Hidden         bool               `bson:"hidden,omitempty" json:"hidden"` // oculta por moderación: solo la ve su autor
 	CreatedAt      time.Time          `bson:"createdAt" json:"createdAt"`
 	UpdatedAt      time.Time          `bson:"updatedAt" json:"updatedAt"`
 	TotalTime      int                `bson:"totalTime" json:"totalTime"`
+	Servings       int                `bson:"servings" json:"servings"` // porciones que rinde; 0 si el autor no lo indicó
 	Step           []Step             `bson:"step" json:"step"`
 	DificultyLevel string             `bson:"dificultyLevel" json:"dificultyLevel"`
 	Tags           []string           `bson:"tags" json:"tags"`
 	Ingredients    []Ingredient       `bson:"ingredients" json:"ingredients"`
 	Image          string             `bson:"image" json:"image"`
 	AverageRating  float64            `bson:"averageRating" json:"averageRating"`
+	SavedCount     int64              `bson:"savedCount" json:"savedCount"` // lo mantiene SavedRecipeRepository
+	Score          float64            `bson:"score,omitempty" json:"-"`     // relevancia de búsqueda, no se persiste
+	ForkedFrom     *ForkOrigin        `bson:"forkedFrom,omitempty" json:"forkedFrom,omitempty"`
+	Lineage        []ForkOrigin       `bson:"lineage,omitempty" json:"lineage,omitempty"` // de la receta raíz a ForkedFrom
+	Allergens      []string           `bson:"allergens" json:"allergens"`                 // derivados de los ingredientes, ver backend/dietary
+	Diets          []string           `bson:"diets" json:"diets"`
+	Unclassified   []string           `bson:"unclassified" json:"unclassified"`               // ingredientes que dietary no reconoce; con alguno no hay dietas
+	Nutrition      *RecipeNutrition   `bson:"nutrition,omitempty" json:"nutrition,omitempty"` // calculada de los ingredientes, ver backend/nutrition
+}
+
+// ForkOrigin identifica la receta de la que se copió una versión. Autor y
+// título se guardan copiados para que la atribución siga siendo legible
+// aunque la receta original se borre.
+type ForkOrigin struct {
+	RecipeID primitive.ObjectID `bson:"recipeId" json:"recipeId"`
+	UserID   primitive.ObjectID `bson:"userId" json:"userId"`
+	UserName string             `bson:"userName" json:"userName"`
+	Title    string             `bson:"title" json:"title"`
+	ForkedAt time.Time          `bson:"forkedAt" json:"forkedAt"`
+	Deleted  bool               `bson:"-" json:"deleted,omitempty"` // la receta original ya no existe
 }
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RecipeRevision es una foto inmutable de la receta tal como quedó después
+// de una edición. Number es correlativo por receta y empieza en 1.
+type RecipeRevision struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	RecipeID     primitive.ObjectID `bson:"recipeId" json:"recipeId"`
+	Number       int                `bson:"number" json:"number"`
+	EditorID     primitive.ObjectID `bson:"editorId" json:"editorId"`
+	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
+	RestoredFrom int                `bson:"restoredFrom,omitempty" json:"restoredFrom,omitempty"` // revisión restaurada, si la hubo
+	Snapshot     Recipe             `bson:"snapshot" json:"snapshot"`
+}
@@ -11,6 +11,32 @@ type SavedRecipe struct {
 	UserID    primitive.ObjectID `bson:"userId" json:"userId"`
 	RecipeID  primitive.ObjectID `bson:"recipeId" json:"recipeId"`
 	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	Notes     *RecipeNotes       `bson:"notes,omitempty" json:"notes,omitempty"`
+}
+
+// RecipeNotes son las anotaciones privadas de quien guardó la receta. Viven
+// en el SavedRecipe, así que se pierden si la receta deja de estar guardada.
+type RecipeNotes struct {
+	Note          string                   `bson:"note" json:"note"`
+	Steps         []StepNote               `bson:"steps" json:"steps"`
+	Substitutions []IngredientSubstitution `bson:"substitutions" json:"substitutions"`
+	UpdatedAt     time.Time                `bson:"updatedAt" json:"updatedAt"`
+}
+
+// StepNote anota un paso por su posición (empezando en 1).
+type StepNote struct {
+	Step int    `bson:"step" json:"step"`
+	Text string `bson:"text" json:"text"`
+}
+
+// IngredientSubstitution reemplaza un ingrediente por nombre. IngredientIndex
+// se resuelve al leer contra la versión actual de la receta (-1 si el autor
+// lo quitó).
+type IngredientSubstitution struct {
+	Ingredient      string `bson:"ingredient" json:"ingredient"`
+	Replacement     string `bson:"replacement" json:"replacement"`
+	Note            string `bson:"note" json:"note"`
+	IngredientIndex int    `bson:"-" json:"ingredientIndex"`
 }
 
 type TopSavedRecipe struct {
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ShoppingList es la lista de compras de un usuario. Hay una sola por usuario
+// y se guarda completa para que se vea igual desde cualquier dispositivo.
+type ShoppingList struct {
+	UserID    primitive.ObjectID `bson:"_id" json:"userId"`
+	Items     []ShoppingItem     `bson:"items" json:"items"`
+	Recipes   []ShoppingSource   `bson:"recipes" json:"recipes"` // recetas con las que se generó
+	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// ShoppingItem es un renglón de la lista. Los generados juntan todas las
+// apariciones del ingrediente (misma Key) en las recetas; los manuales los
+// agrega el usuario y no se tocan al regenerar.
+type ShoppingItem struct {
+	ID        primitive.ObjectID   `bson:"_id" json:"id"`
+	Name      string               `bson:"name" json:"name"`
+	Key       string               `bson:"key" json:"-"` // nombre normalizado, ver shopping.Key
+	Aisle     string               `bson:"aisle" json:"aisle"`
+	Amounts   []ShoppingAmount     `bson:"amounts" json:"amounts"`
+	Manual    bool                 `bson:"manual" json:"manual"`
+	Checked   bool                 `bson:"checked" json:"checked"`
+	RecipeIDs []primitive.ObjectID `bson:"recipeIds,omitempty" json:"recipeIds,omitempty"`
+	AddedAt   time.Time            `bson:"addedAt" json:"addedAt"`
+}
+
+type ShoppingAmount struct {
+	Quantity float64 `bson:"quantity" json:"quantity"`
+	Unit     string  `bson:"unit" json:"unit"` // código del catálogo de backend/units
+}
+
+// ShoppingSource guarda título y porciones copiados para que la lista siga
+// siendo legible aunque la receta se borre.
+type ShoppingSource struct {
+	RecipeID primitive.ObjectID `bson:"recipeId" json:"recipeId"`
+	Title    string             `bson:"title" json:"title"`
+	Servings int                `bson:"servings" json:"servings"`
+	AddedAt  time.Time          `bson:"addedAt" json:"addedAt"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SubstitutionRule dice con qué se puede reemplazar una cantidad de
+// referencia de un ingrediente: "1 cup buttermilk = 1 cup leche + 1 tbsp
+// jugo de limón". Las cantidades de la receta se calculan en proporción.
+type SubstitutionRule struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Ingredient   string             `bson:"ingredient" json:"ingredient"`
+	Key          string             `bson:"key" json:"-"` // shopping.Key de Ingredient
+	Quantity     float64            `bson:"quantity" json:"quantity"`
+	Unit         string             `bson:"unit" json:"unit"`
+	Substitute   []SubstitutePart   `bson:"substitute" json:"substitute"`
+	Note         string             `bson:"note,omitempty" json:"note,omitempty"`           // "sirve para horneados, no para freír"
+	Allergens    []string           `bson:"allergens" json:"allergens"`                     // del reemplazo, ver backend/dietary
+	Diets        []string           `bson:"diets" json:"diets"`                             // dietas que admiten el reemplazo
+	Unclassified []string           `bson:"unclassified" json:"unclassified"`               // partes que dietary no reconoce
+	CreatedBy    primitive.ObjectID `bson:"createdBy,omitempty" json:"createdBy,omitempty"` // vacío en las reglas de fábrica
+	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt    time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// SubstitutePart es uno de los ingredientes del reemplazo, en la cantidad
+// que corresponde a la de referencia de la regla.
+type SubstitutePart struct {
+	Name     string  `bson:"name" json:"name"`
+	Quantity float64 `bson:"quantity" json:"quantity"`
+	Unit     string  `bson:"unit" json:"unit"`
+}
@@ -6,6 +6,10 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// DeletedUserName reemplaza el nombre del autor en el contenido que sobrevive
+// a la baja de su cuenta.
+const DeletedUserName = "[deleted]"
+
 type User struct {
 	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	Name           string             `bson:"name" json:"name"`
@@ -15,4 +19,6 @@ type User struct {
 	CreatedAt      time.Time          `bson:"createdAt" json:"createdAt"`
 	UpdatedAt      time.Time          `bson:"updatedAt" json:"updatedAt"`
 	GoogleID       string             `bson:"google_id,omitempty" json:"google_id,omitempty"`
+	FollowerCount  int                `bson:"followerCount" json:"followerCount"`   // quienes lo siguen
+	FollowingCount int                `bson:"followingCount" json:"followingCount"` // a quienes sigue
 }
@@ -0,0 +1,154 @@
+package nutrition
+
+import (
+	"burned/backend/models"
+	"burned/backend/scaling"
+	"burned/backend/shopping"
+	"burned/backend/units"
+	"math"
+	"time"
+)
+
+// Motivos por los que un ingrediente no entra en el cálculo.
+const (
+	ReasonUnknown = "unknown"
+	ReasonUnit    = "unit"
+)
+
+// Miss es un ingrediente que no se pudo calcular.
+type Miss struct {
+	Name   string
+	Key    string
+	Unit   string
+	Reason string
+}
+
+// fixedGrams son los pesos de las unidades de conteo que no dependen del
+// alimento. El resto (unidad, diente, rodaja, atado) usa Food.UnitGrams.
+var fixedGrams = map[string]float64{
+	"pinch": 0.3,
+	"can":   400,
+}
+
+// Grams pasa la cantidad de un ingrediente a gramos del alimento. Los
+// volúmenes sin densidad conocida se toman como agua (1 g/ml).
+func Grams(food Food, ingredient models.Ingredient) (float64, bool) {
+	unit, ok := units.Lookup(ingredient.Unit)
+	if !ok {
+		return 0, false
+	}
+	switch unit.Dimension {
+	case units.ToTaste:
+		return 0, true
+	case units.Mass:
+		converted, err := scaling.Convert(scaling.Amount{Quantity: ingredient.Quantity, Unit: unit.Code}, "g")
+		return converted.Quantity, err == nil
+	case units.Volume:
+		converted, err := scaling.Convert(scaling.Amount{Quantity: ingredient.Quantity, Unit: unit.Code}, "ml")
+		if err != nil {
+			return 0, false
+		}
+		density := food.Density
+		if density == 0 {
+			density = 1
+		}
+		return converted.Quantity * density, true
+	}
+	if grams, ok := fixedGrams[unit.Code]; ok {
+		return ingredient.Quantity * grams, true
+	}
+	if food.UnitGrams > 0 {
+		return ingredient.Quantity * food.UnitGrams, true
+	}
+	return 0, false
+}
+
+// Calculate suma los nutrientes de los ingredientes y, si servings > 0, los
+// divide por porción. Los ingredientes "a gusto" no cuentan; los que no se
+// pueden calcular se devuelven en misses y quedan fuera del total.
+func Calculate(database *Database, ingredients []models.Ingredient, servings int) (models.RecipeNutrition, []Miss) {
+	result := models.RecipeNutrition{CalculatedAt: time.Now()}
+	misses := []Miss{}
+	for _, ingredient := range ingredients {
+		if ingredient.Unit == units.CodeToTaste {
+			continue
+		}
+		result.Counted++
+		food, ok := database.Match(ingredient.Name)
+		if !ok {
+			misses = append(misses, Miss{Name: ingredient.Name, Key: shopping.Key(ingredient.Name), Unit: ingredient.Unit, Reason: ReasonUnknown})
+			continue
+		}
+		grams, ok := Grams(food, ingredient)
+		if !ok {
+			misses = append(misses, Miss{Name: ingredient.Name, Key: shopping.Key(ingredient.Name), Unit: ingredient.Unit, Reason: ReasonUnit})
+			continue
+		}
+		result.Matched++
+		result.Total = Add(result.Total, Scale(food.Per100g, grams/100))
+	}
+	for _, miss := range misses {
+		result.Unmatched = append(result.Unmatched, miss.Name)
+	}
+
+	if servings > 0 {
+		perServing := Round(Scale(result.Total, 1/float64(servings)))
+		result.PerServing = &perServing
+	}
+	result.Total = Round(result.Total)
+	return result, misses
+}
+
+// Scale multiplica todos los valores por factor.
+func Scale(nutrients models.Nutrients, factor float64) models.Nutrients {
+	return models.Nutrients{
+		Calories:      nutrients.Calories * factor,
+		Protein:       nutrients.Protein * factor,
+		Fat:           nutrients.Fat * factor,
+		SaturatedFat:  nutrients.SaturatedFat * factor,
+		Carbohydrates: nutrients.Carbohydrates * factor,
+		Sugar:         nutrients.Sugar * factor,
+		Fiber:         nutrients.Fiber * factor,
+		Sodium:        nutrients.Sodium * factor,
+		Calcium:       nutrients.Calcium * factor,
+		Iron:          nutrients.Iron * factor,
+		Potassium:     nutrients.Potassium * factor,
+		VitaminC:      nutrients.VitaminC * factor,
+	}
+}
+
+func Add(a models.Nutrients, b models.Nutrients) models.Nutrients {
+	return models.Nutrients{
+		Calories:      a.Calories + b.Calories,
+		Protein:       a.Protein + b.Protein,
+		Fat:           a.Fat + b.Fat,
+		SaturatedFat:  a.SaturatedFat + b.SaturatedFat,
+		Carbohydrates: a.Carbohydrates + b.Carbohydrates,
+		Sugar:         a.Sugar + b.Sugar,
+		Fiber:         a.Fiber + b.Fiber,
+		Sodium:        a.Sodium + b.Sodium,
+		Calcium:       a.Calcium + b.Calcium,
+		Iron:          a.Iron + b.Iron,
+		Potassium:     a.Potassium + b.Potassium,
+		VitaminC:      a.VitaminC + b.VitaminC,
+	}
+}
+
+// Round deja un decimal, suficiente para una etiqueta nutricional.
+func Round(nutrients models.Nutrients) models.Nutrients {
+	round := func(value float64) float64 { return math.Round(value*10) / 10 }
+	return models.Nutrients{
+		Calories:      round(nutrients.Calories),
+		Protein:       round(nutrients.Protein),
+		Fat:           round(nutrients.Fat),
+		SaturatedFat:  round(nutrients.SaturatedFat),
+		Carbohydrates: round(nutrients.Carbohydrates),
+		Sugar:         round(nutrients.Sugar),
+		Fiber:         round(nutrients.Fiber),
+		Sodium:        round(nutrients.Sodium),
+		Calcium:       round(nutrients.Calcium),
+		Iron:          round(nutrients.Iron),
+		Potassium:     round(nutrients.Potassium),
+		VitaminC:      round(nutrients.VitaminC),
+	}
+}
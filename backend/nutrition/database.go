@@ -0,0 +1,276 @@
+// Package nutrition calcula la información nutricional de las recetas a
+// partir de una base local de alimentos con valores cada 100 g.
+package nutrition
+
+import (
+	"burned/backend/models"
+	"burned/backend/shopping"
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// defaultFoods es la base que viene con el backend: alimentos comunes con
+// valores aproximados de USDA FoodData Central (SR Legacy).
+//
+//go:embed data/foods.csv
+var defaultFoods []byte
+
+var ErrUnknownFood = errors.New("unknown food")
+
+// Food es un alimento de la base. Density (g/ml) permite calcular
+// ingredientes medidos en volumen y UnitGrams los contados por unidad; en 0
+// significan que no se conocen.
+type Food struct {
+	Name      string
+	Aliases   []string
+	Per100g   models.Nutrients
+	Density   float64
+	UnitGrams float64
+}
+
+// Database es una base de alimentos indexada por nombre y alias
+// normalizados (shopping.Key). Es segura para uso concurrente.
+type Database struct {
+	mu    sync.RWMutex
+	foods []Food
+	byKey map[string]int
+}
+
+// columns son los encabezados aceptados para cada dato. El CSV puede traer
+// las columnas en cualquier orden y otras que se ignoran; name y kcal son
+// obligatorias. Los nombres alternativos son los de un export de FoodData Central.
+var columns = map[string][]string{
+	"name":      {"name", "description"},
+	"aliases":   {"aliases"},
+	"kcal":      {"kcal", "energy_kcal", "energy (kcal)", "calories"},
+	"protein":   {"protein_g", "protein (g)", "protein"},
+	"fat":       {"fat_g", "total lipid (fat) (g)", "fat"},
+	"saturated": {"saturated_fat_g", "fatty acids, total saturated (g)", "saturated_fat"},
+	"carbs":     {"carbohydrate_g", "carbohydrate, by difference (g)", "carbohydrate", "carbs"},
+	"sugar":     {"sugar_g", "sugars, total including nlea (g)", "sugars, total (g)", "sugar"},
+	"fiber":     {"fiber_g", "fiber, total dietary (g)", "fiber"},
+	"sodium":    {"sodium_mg", "sodium, na (mg)", "sodium"},
+	"calcium":   {"calcium_mg", "calcium, ca (mg)", "calcium"},
+	"iron":      {"iron_mg", "iron, fe (mg)", "iron"},
+	"potassium": {"potassium_mg", "potassium, k (mg)", "potassium"},
+	"vitaminC":  {"vitamin_c_mg", "vitamin c, total ascorbic acid (mg)", "vitamin_c"},
+	"density":   {"density_g_per_ml", "density"},
+	"unitGrams": {"unit_g", "unit_grams"},
+}
+
+var (
+	defaultOnce     sync.Once
+	defaultDatabase *Database
+)
+
+// Default devuelve la base embebida. Se interpreta una sola vez.
+func Default() *Database {
+	defaultOnce.Do(func() {
+		database, err := Parse(bytes.NewReader(defaultFoods))
+		if err != nil {
+			panic("nutrition: embedded food database is invalid: " + err.Error())
+		}
+		defaultDatabase = database
+	})
+	return defaultDatabase
+}
+
+// LoadFile lee una base desde un archivo CSV.
+func LoadFile(path string) (*Database, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Parse(file)
+}
+
+// Parse lee una base en CSV con encabezado. Los alias van separados por ";".
+// Si un nombre o alias se repite gana la primera fila.
+func Parse(reader io.Reader) (*Database, error) {
+	records := csv.NewReader(reader)
+	records.FieldsPerRecord = -1
+	header, err := records.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+	index := columnIndex(header)
+	if _, ok := index["name"]; !ok {
+		return nil, errors.New("missing name column")
+	}
+	if _, ok := index["kcal"]; !ok {
+		return nil, errors.New("missing kcal column")
+	}
+
+	database := &Database{byKey: map[string]int{}}
+	for line := 2; ; line++ {
+		record, err := records.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		food, err := parseFood(record, index)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if food.Name == "" {
+			continue
+		}
+		database.add(food)
+	}
+	if len(database.foods) == 0 {
+		return nil, errors.New("no foods found")
+	}
+	return database, nil
+}
+
+func columnIndex(header []string) map[string]int {
+	index := map[string]int{}
+	for position, title := range header {
+		title = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(title, "\ufeff")))
+		for field, names := range columns {
+			for _, name := range names {
+				if title == name {
+					if _, ok := index[field]; !ok {
+						index[field] = position
+					}
+				}
+			}
+		}
+	}
+	return index
+}
+
+func parseFood(record []string, index map[string]int) (Food, error) {
+	text := func(field string) string {
+		position, ok := index[field]
+		if !ok || position >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[position])
+	}
+	var parseErr error
+	number := func(field string) float64 {
+		value := text(field)
+		if value == "" || parseErr != nil {
+			return 0
+		}
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed < 0 {
+			parseErr = fmt.Errorf("invalid %s %q", field, value)
+			return 0
+		}
+		return parsed
+	}
+
+	food := Food{
+		Name: text("name"),
+		Per100g: models.Nutrients{
+			Calories:      number("kcal"),
+			Protein:       number("protein"),
+			Fat:           number("fat"),
+			SaturatedFat:  number("saturated"),
+			Carbohydrates: number("carbs"),
+			Sugar:         number("sugar"),
+			Fiber:         number("fiber"),
+			Sodium:        number("sodium"),
+			Calcium:       number("calcium"),
+			Iron:          number("iron"),
+			Potassium:     number("potassium"),
+			VitaminC:      number("vitaminC"),
+		},
+		Density:   number("density"),
+		UnitGrams: number("unitGrams"),
+	}
+	for _, alias := range strings.Split(text("aliases"), ";") {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			food.Aliases = append(food.Aliases, alias)
+		}
+	}
+	return food, parseErr
+}
+
+func (database *Database) add(food Food) {
+	database.foods = append(database.foods, food)
+	position := len(database.foods) - 1
+	for _, name := range append([]string{food.Name}, food.Aliases...) {
+		key := shopping.Key(name)
+		if _, exists := database.byKey[key]; !exists && key != "" {
+			database.byKey[key] = position
+		}
+	}
+}
+
+// Lookup busca un alimento por su nombre o alias exacto (normalizado).
+func (database *Database) Lookup(name string) (Food, bool) {
+	database.mu.RLock()
+	defer database.mu.RUnlock()
+	position, ok := database.byKey[shopping.Key(name)]
+	if !ok {
+		return Food{}, false
+	}
+	return database.foods[position], true
+}
+
+// AddAlias hace que alias se resuelva como el alimento food (nombre o alias
+// existente). Reemplaza lo que alias resolvía antes.
+func (database *Database) AddAlias(alias string, food string) error {
+	database.mu.Lock()
+	defer database.mu.Unlock()
+	position, ok := database.byKey[shopping.Key(food)]
+	if !ok {
+		return ErrUnknownFood
+	}
+	key := shopping.Key(alias)
+	if key == "" {
+		return errors.New("invalid alias")
+	}
+	database.byKey[key] = position
+	return nil
+}
+
+// Match busca el alimento de un ingrediente: primero por el nombre
+// completo y si no, por la frase más larga del nombre que sea un alimento o
+// alias ("tomates perita maduros" -> "tomate perita"). Ver shopping.Terms.
+func (database *Database) Match(name string) (Food, bool) {
+	database.mu.RLock()
+	defer database.mu.RUnlock()
+	terms := shopping.Terms(name)
+	best, bestWords := -1, 0
+	for _, term := range terms {
+		position, ok := database.byKey[term]
+		if !ok {
+			continue
+		}
+		if words := len(strings.Fields(term)); words > bestWords {
+			best, bestWords = position, words
+		}
+	}
+	if best < 0 {
+		return Food{}, false
+	}
+	return database.foods[best], true
+}
+
+// Foods devuelve los nombres de los alimentos ordenados alfabéticamente.
+func (database *Database) Foods() []string {
+	database.mu.RLock()
+	defer database.mu.RUnlock()
+	names := make([]string, 0, len(database.foods))
+	for _, food := range database.foods {
+		names = append(names, food.Name)
+	}
+	sort.Strings(names)
+	return names
+}
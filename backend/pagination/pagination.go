@@ -0,0 +1,256 @@
+package pagination
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrInvalidSort   = errors.New("invalid sort")
+)
+
+// IsRequestError indica si el error se debe a parámetros de paginación mal
+// formados, para que los handlers respondan 400 en lugar de 500.
+func IsRequestError(err error) bool {
+	return errors.Is(err, ErrInvalidCursor) || errors.Is(err, ErrInvalidSort)
+}
+
+// Request es el contrato de paginación que comparten todos los listados:
+// ?limit=&after=&sort=
+type Request struct {
+	Limit int    `form:"limit" json:"limit" binding:"omitempty,min=1,max=100"`
+	After string `form:"after" json:"after" binding:"omitempty,max=512"`
+	Sort  string `form:"sort" json:"sort" binding:"omitempty,max=20"`
+}
+
+// Page es la respuesta paginada. NextCursor viene vacío en la última página.
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// Sort describe el orden de un listado. Siempre se desempata por _id en la
+// misma dirección para que el orden sea total y estable entre páginas.
+// Relevance ordena por el puntaje de $text; como ese puntaje no se puede usar
+// en un filtro, esas páginas avanzan por desplazamiento en lugar de por clave.
+type Sort struct {
+	Name      string
+	Field     string
+	Desc      bool
+	Relevance bool
+}
+
+// cursor es lo que viaja codificado en "after". Se serializa en BSON para
+// conservar el tipo del valor (fecha, entero, decimal) entre páginas.
+type cursor struct {
+	Sort   string             `bson:"s"`
+	Value  interface{}        `bson:"v,omitempty"`
+	ID     primitive.ObjectID `bson:"id,omitempty"`
+	Offset int64              `bson:"o,omitempty"`
+}
+
+// Limit normaliza el tamaño de página pedido.
+func Limit(limit int) int {
+	if limit <= 0 {
+		return DefaultLimit
+	}
+	if limit > MaxLimit {
+		return MaxLimit
+	}
+	return limit
+}
+
+// Map convierte los elementos de una página conservando el cursor.
+func Map[S any, T any](page Page[S], convert func(S) T) Page[T] {
+	items := make([]T, 0, len(page.Items))
+	for _, item := range page.Items {
+		items = append(items, convert(item))
+	}
+	return Page[T]{Items: items, NextCursor: page.NextCursor}
+}
+
+func encode(c cursor) (string, error) {
+	raw, err := bson.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decode(after string, sort Sort) (cursor, error) {
+	var c cursor
+	raw, err := base64.RawURLEncoding.DecodeString(after)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if err := bson.Unmarshal(raw, &c); err != nil {
+		return c, ErrInvalidCursor
+	}
+	// Un cursor solo vale para el orden con el que se generó
+	if c.Sort != sort.Name {
+		return c, ErrInvalidCursor
+	}
+	if !sort.Relevance && c.ID.IsZero() {
+		return c, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+func sortDoc(sort Sort) bson.D {
+	if sort.Relevance {
+		return bson.D{
+			{Key: "score", Value: bson.M{"$meta": "textScore"}},
+			{Key: "_id", Value: -1},
+		}
+	}
+	direction := 1
+	if sort.Desc {
+		direction = -1
+	}
+	return bson.D{{Key: sort.Field, Value: direction}, {Key: "_id", Value: direction}}
+}
+
+// keysetFilter devuelve los documentos que van después del cursor.
+func keysetFilter(sort Sort, c cursor) bson.M {
+	operator := "$gt"
+	if sort.Desc {
+		operator = "$lt"
+	}
+	return bson.M{"$or": []bson.M{
+		{sort.Field: bson.M{operator: c.Value}},
+		{sort.Field: c.Value, "_id": bson.M{operator: c.ID}},
+	}}
+}
+
+// withKeyset agrega la condición del cursor al filtro sin anidarlo, para que
+// operadores que deben ir en el primer nivel (como $text) sigan ahí.
+func withKeyset(filter bson.M, keyset bson.M) bson.M {
+	merged := bson.M{}
+	for key, value := range filter {
+		merged[key] = value
+	}
+	if and, ok := merged["$and"].([]bson.M); ok {
+		merged["$and"] = append(append([]bson.M{}, and...), keyset)
+	} else {
+		merged["$and"] = []bson.M{keyset}
+	}
+	return merged
+}
+
+// Find ejecuta una consulta paginada sobre la colección y decodifica cada
+// documento en T.
+func Find[T any](ctx context.Context, collection *mongo.Collection, filter bson.M, sort Sort, request Request) (Page[T], error) {
+	limit := Limit(request.Limit)
+
+	var offset int64
+	opts := options.Find().SetSort(sortDoc(sort)).SetLimit(int64(limit) + 1)
+	if sort.Relevance {
+		opts.SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}})
+	}
+	if request.After != "" {
+		c, err := decode(request.After, sort)
+		if err != nil {
+			return Page[T]{}, err
+		}
+		if sort.Relevance {
+			offset = c.Offset
+			opts.SetSkip(offset)
+		} else {
+			filter = withKeyset(filter, keysetFilter(sort, c))
+		}
+	}
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return Page[T]{}, err
+	}
+	defer cursor.Close(ctx)
+
+	return readPage[T](ctx, cursor, sort, limit, offset)
+}
+
+// Aggregate pagina el resultado de un pipeline. Las etapas recibidas deben
+// dejar documentos con el campo de orden y _id en el primer nivel.
+func Aggregate[T any](ctx context.Context, collection *mongo.Collection, pipeline mongo.Pipeline, sort Sort, request Request) (Page[T], error) {
+	if sort.Relevance {
+		return Page[T]{}, errors.New("relevance sort is not supported in aggregations")
+	}
+	limit := Limit(request.Limit)
+
+	stages := append(mongo.Pipeline{}, pipeline...)
+	if request.After != "" {
+		c, err := decode(request.After, sort)
+		if err != nil {
+			return Page[T]{}, err
+		}
+		stages = append(stages, bson.D{{Key: "$match", Value: keysetFilter(sort, c)}})
+	}
+	stages = append(stages,
+		bson.D{{Key: "$sort", Value: sortDoc(sort)}},
+		bson.D{{Key: "$limit", Value: limit + 1}},
+	)
+
+	cursor, err := collection.Aggregate(ctx, stages)
+	if err != nil {
+		return Page[T]{}, err
+	}
+	defer cursor.Close(ctx)
+
+	return readPage[T](ctx, cursor, sort, limit, 0)
+}
+
+// readPage lee hasta limit documentos y, si hay uno más, arma el cursor de la
+// página siguiente a partir del último documento devuelto.
+func readPage[T any](ctx context.Context, cursor *mongo.Cursor, sort Sort, limit int, offset int64) (Page[T], error) {
+	page := Page[T]{Items: []T{}}
+	var last bson.Raw
+	for cursor.Next(ctx) {
+		if len(page.Items) == limit {
+			next, err := nextCursor(last, sort, offset+int64(limit))
+			if err != nil {
+				return Page[T]{}, err
+			}
+			page.NextCursor = next
+			break
+		}
+		var item T
+		if err := cursor.Decode(&item); err != nil {
+			return Page[T]{}, err
+		}
+		page.Items = append(page.Items, item)
+		last = append(bson.Raw{}, cursor.Current...)
+	}
+	if err := cursor.Err(); err != nil {
+		return Page[T]{}, err
+	}
+	return page, nil
+}
+
+func nextCursor(last bson.Raw, sort Sort, offset int64) (string, error) {
+	if sort.Relevance {
+		return encode(cursor{Sort: sort.Name, Offset: offset})
+	}
+	var value interface{}
+	if raw, err := last.LookupErr(sort.Field); err == nil {
+		if err := raw.Unmarshal(&value); err != nil {
+			return "", err
+		}
+	}
+	id, ok := last.Lookup("_id").ObjectIDOK()
+	if !ok {
+		return "", errors.New("document without _id")
+	}
+	return encode(cursor{Sort: sort.Name, Value: value, ID: id})
+}
@@ -0,0 +1,34 @@
+package recipeimport
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var isoDuration = regexp.MustCompile(`^P(?:(\d+(?:\.\d+)?)W)?(?:(\d+(?:\.\d+)?)D)?(?:T(?:(\d+(?:\.\d+)?)H)?(?:(\d+(?:\.\d+)?)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// ParseDuration convierte una duración ISO-8601 ("PT1H30M", "P0DT45M") a
+// minutos, redondeando hacia arriba los segundos sueltos.
+func ParseDuration(value string) (int, bool) {
+	normalized := strings.ToUpper(strings.TrimSpace(value))
+	match := isoDuration.FindStringSubmatch(normalized)
+	//"P", "PT" o "P1DT" pasan la expresión pero no son duraciones válidas
+	if match == nil || normalized == "P" || strings.HasSuffix(normalized, "T") {
+		return 0, false
+	}
+	multipliers := []float64{7 * 24 * 60, 24 * 60, 60, 1, 1.0 / 60}
+	var minutes float64
+	for i, multiplier := range multipliers {
+		if match[i+1] == "" {
+			continue
+		}
+		amount, err := strconv.ParseFloat(match[i+1], 64)
+		if err != nil {
+			return 0, false
+		}
+		minutes += amount * multiplier
+	}
+	return int(math.Ceil(minutes)), true
+}
@@ -0,0 +1,120 @@
+package recipeimport
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+var (
+	ErrNoRecipe      = errors.New("no schema.org Recipe found in document")
+	ErrInvalidFormat = errors.New("document is neither JSON-LD nor HTML")
+)
+
+// Extract busca el objeto schema.org Recipe en el contenido recibido, que
+// puede ser un JSON-LD suelto o una página HTML con uno o más bloques
+// <script type="application/ld+json">. No hace ninguna petición de red.
+func Extract(content []byte) (map[string]interface{}, error) {
+	trimmed := bytes.TrimSpace(content)
+	if len(trimmed) == 0 {
+		return nil, ErrInvalidFormat
+	}
+
+	if trimmed[0] == '{' || trimmed[0] == '[' {
+		var document interface{}
+		if err := json.Unmarshal(trimmed, &document); err != nil {
+			return nil, ErrInvalidFormat
+		}
+		if recipe := findRecipe(document); recipe != nil {
+			return recipe, nil
+		}
+		return nil, ErrNoRecipe
+	}
+
+	blocks, err := jsonLDBlocks(trimmed)
+	if err != nil {
+		return nil, err
+	}
+	for _, block := range blocks {
+		var document interface{}
+		// Hay sitios con bloques mal formados; se saltean y se prueba el siguiente
+		if err := json.Unmarshal([]byte(block), &document); err != nil {
+			continue
+		}
+		if recipe := findRecipe(document); recipe != nil {
+			return recipe, nil
+		}
+	}
+	return nil, ErrNoRecipe
+}
+
+func jsonLDBlocks(document []byte) ([]string, error) {
+	root, err := html.Parse(bytes.NewReader(document))
+	if err != nil {
+		return nil, ErrInvalidFormat
+	}
+
+	var blocks []string
+	var walk func(node *html.Node)
+	walk = func(node *html.Node) {
+		if node.Type == html.ElementNode && node.Data == "script" && isJSONLD(node) {
+			var content strings.Builder
+			for child := node.FirstChild; child != nil; child = child.NextSibling {
+				content.WriteString(child.Data)
+			}
+			blocks = append(blocks, content.String())
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(root)
+	return blocks, nil
+}
+
+func isJSONLD(node *html.Node) bool {
+	for _, attribute := range node.Attr {
+		if attribute.Key == "type" && strings.EqualFold(strings.TrimSpace(attribute.Val), "application/ld+json") {
+			return true
+		}
+	}
+	return false
+}
+
+// findRecipe recorre el JSON-LD (objeto, lista o @graph) hasta encontrar un
+// nodo cuyo @type sea o incluya "Recipe".
+func findRecipe(node interface{}) map[string]interface{} {
+	switch value := node.(type) {
+	case []interface{}:
+		for _, item := range value {
+			if recipe := findRecipe(item); recipe != nil {
+				return recipe
+			}
+		}
+	case map[string]interface{}:
+		if hasType(value, "Recipe") {
+			return value
+		}
+		for _, key := range []string{"@graph", "mainEntity", "mainEntityOfPage"} {
+			if nested, ok := value[key]; ok {
+				if recipe := findRecipe(nested); recipe != nil {
+					return recipe
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func hasType(node map[string]interface{}, schemaType string) bool {
+	for _, value := range texts(node["@type"]) {
+		value = strings.TrimPrefix(strings.TrimPrefix(value, "http://schema.org/"), "https://schema.org/")
+		if value == schemaType {
+			return true
+		}
+	}
+	return false
+}
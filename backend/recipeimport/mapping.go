@@ -0,0 +1,222 @@
+package recipeimport
+
+import (
+	"burned/backend/ingredients"
+	"burned/backend/models"
+	"burned/backend/units"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Draft es la receta importada lista para revisar antes de guardarla.
+// Warnings describe lo que no se pudo traer tal cual y se completó o adivinó.
+type Draft struct {
+	Recipe   models.Recipe
+	Warnings []string
+}
+
+var firstNumber = regexp.MustCompile(`\d+`)
+
+// Import extrae el Recipe del documento y lo convierte en un borrador.
+func Import(content []byte) (Draft, error) {
+	node, err := Extract(content)
+	if err != nil {
+		return Draft{}, err
+	}
+	return Map(node), nil
+}
+
+// Map convierte un nodo schema.org Recipe en un models.Recipe. Completa con
+// valores por defecto lo que la receta de Burned exige y schema.org no tiene
+// (dificultad, visibilidad), siempre dejando un aviso.
+func Map(node map[string]interface{}) Draft {
+	var draft Draft
+	recipe := &draft.Recipe
+
+	recipe.Title = truncate(text(node["name"]), 120)
+	if recipe.Title == "" {
+		recipe.Title = "Receta importada"
+		draft.warn("the recipe has no name")
+	}
+	recipe.Description = truncate(text(node["description"]), 350)
+	if len([]rune(recipe.Description)) < 3 {
+		recipe.Description = recipe.Title
+		draft.warn("the recipe has no description; the title was used instead")
+	}
+	recipe.Visibility = "private"
+	recipe.Image = text(node["image"])
+
+	recipe.Ingredients = draft.mapIngredients(node)
+	recipe.Step = draft.mapInstructions(node["recipeInstructions"])
+	recipe.Tags = mapKeywords(node["keywords"])
+	recipe.Servings = mapYield(node["recipeYield"])
+
+	recipe.TotalTime = mapTotalTime(node, recipe.Step)
+	if recipe.TotalTime == 0 {
+		draft.warn("no total time found")
+	}
+	recipe.DificultyLevel = guessDifficulty(recipe.TotalTime, len(recipe.Step), len(recipe.Ingredients))
+	draft.warn("difficulty level was estimated as " + recipe.DificultyLevel)
+	return draft
+}
+
+func (draft *Draft) warn(message string) {
+	draft.Warnings = append(draft.Warnings, message)
+}
+
+func (draft *Draft) mapIngredients(node map[string]interface{}) []models.Ingredient {
+	lines := texts(node["recipeIngredient"])
+	if len(lines) == 0 {
+		// nombre de la propiedad en la versión vieja del vocabulario
+		lines = texts(node["ingredients"])
+	}
+	if len(lines) == 0 {
+		draft.warn("no ingredients found")
+	}
+
+	result := make([]models.Ingredient, 0, len(lines))
+	for _, line := range lines {
+		parsed, err := ingredients.ParseLine(line)
+		if err != nil {
+			draft.warn("could not parse ingredient \"" + line + "\"")
+			result = append(result, models.Ingredient{Name: truncate(line, 120), Unit: units.CodeToTaste})
+			continue
+		}
+		ingredient := parsed.Ingredient()
+		ingredient.Name = truncate(ingredient.Name, 120)
+		result = append(result, ingredient)
+	}
+	return result
+}
+
+// mapInstructions acepta todas las formas en que schema.org permite escribir
+// las instrucciones: un texto, una lista de textos, HowToStep y HowToSection
+// (cuyos pasos llevan el nombre de la sección como prefijo del título).
+func (draft *Draft) mapInstructions(value interface{}) []models.Step {
+	var steps []models.Step
+	var add func(value interface{}, section string)
+	add = func(value interface{}, section string) {
+		switch v := value.(type) {
+		case string:
+			for _, paragraph := range splitParagraphs(v) {
+				steps = append(steps, newStep(len(steps)+1, section, "", paragraph, 0))
+			}
+		case []interface{}:
+			for _, item := range v {
+				add(item, section)
+			}
+		case map[string]interface{}:
+			if hasType(v, "HowToSection") {
+				add(v["itemListElement"], text(v["name"]))
+				return
+			}
+			description := text(v["text"])
+			name := text(v["name"])
+			if description == "" {
+				description = name
+				name = ""
+			}
+			if description == "" {
+				return
+			}
+			minutes := 0
+			for _, key := range []string{"totalTime", "performTime", "timeRequired"} {
+				if parsed, ok := ParseDuration(text(v[key])); ok {
+					minutes = parsed
+					break
+				}
+			}
+			steps = append(steps, newStep(len(steps)+1, section, name, description, minutes))
+		}
+	}
+	add(value, "")
+
+	if len(steps) == 0 {
+		draft.warn("no instructions found")
+	}
+	return steps
+}
+
+func newStep(number int, section string, name string, description string, minutes int) models.Step {
+	title := name
+	// Muchos sitios repiten el texto del paso como name
+	if title == "" || title == description {
+		title = "Paso " + strconv.Itoa(number)
+	}
+	if section != "" {
+		title = section + ": " + title
+	}
+	return models.Step{Title: truncate(title, 120), Descripcion: description, Time: minutes}
+}
+
+func splitParagraphs(value string) []string {
+	var paragraphs []string
+	for _, line := range strings.Split(strings.ReplaceAll(value, "\r", ""), "\n") {
+		if line = cleanText(line); line != "" {
+			paragraphs = append(paragraphs, line)
+		}
+	}
+	return paragraphs
+}
+
+func mapKeywords(value interface{}) []string {
+	var tags []string
+	seen := map[string]bool{}
+	for _, keywords := range texts(value) {
+		for _, keyword := range strings.Split(keywords, ",") {
+			keyword = truncate(strings.TrimSpace(keyword), 30)
+			key := strings.ToLower(keyword)
+			if keyword == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			tags = append(tags, keyword)
+			if len(tags) == 20 {
+				return tags
+			}
+		}
+	}
+	return tags
+}
+
+func mapYield(value interface{}) int {
+	for _, yield := range texts(value) {
+		if match := firstNumber.FindString(yield); match != "" {
+			servings, err := strconv.Atoi(match)
+			if err == nil && servings > 0 && servings <= 100 {
+				return servings
+			}
+		}
+	}
+	return 0
+}
+
+// mapTotalTime usa totalTime; si falta, prepTime + cookTime; y si tampoco
+// están, la suma de los tiempos de los pasos.
+func mapTotalTime(node map[string]interface{}, steps []models.Step) int {
+	if minutes, ok := ParseDuration(text(node["totalTime"])); ok && minutes > 0 {
+		return minutes
+	}
+	prep, _ := ParseDuration(text(node["prepTime"]))
+	cook, _ := ParseDuration(text(node["cookTime"]))
+	if prep+cook > 0 {
+		return prep + cook
+	}
+	total := 0
+	for _, step := range steps {
+		total += step.Time
+	}
+	return total
+}
+
+func guessDifficulty(totalTime int, steps int, ingredientCount int) string {
+	switch {
+	case totalTime > 120 || steps > 12 || ingredientCount > 15:
+		return "hard"
+	case totalTime > 0 && totalTime <= 30 && steps <= 5 && ingredientCount <= 8:
+		return "easy"
+	default:
+		return "medium"
+	}
+}
@@ -0,0 +1,69 @@
+package recipeimport
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var tagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// text lee un valor JSON-LD como texto: acepta strings, números, listas (toma
+// el primer elemento) y objetos con "@value", "text", "name" o "url".
+func text(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return cleanText(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		for _, item := range v {
+			if t := text(item); t != "" {
+				return t
+			}
+		}
+	case map[string]interface{}:
+		for _, key := range []string{"@value", "text", "name", "url"} {
+			if t := text(v[key]); t != "" {
+				return t
+			}
+		}
+	}
+	return ""
+}
+
+// texts lee un valor que puede venir como string o como lista.
+func texts(value interface{}) []string {
+	var result []string
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if t := text(item); t != "" {
+				result = append(result, t)
+			}
+		}
+	default:
+		if t := text(v); t != "" {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// cleanText decodifica entidades HTML, quita etiquetas y compacta espacios;
+// muchos sitios meten HTML dentro de los strings del JSON-LD.
+func cleanText(value string) string {
+	value = html.UnescapeString(value)
+	value = tagPattern.ReplaceAllString(value, " ")
+	value = html.UnescapeString(value)
+	return strings.Join(strings.Fields(value), " ")
+}
+
+func truncate(value string, max int) string {
+	runes := []rune(value)
+	if len(runes) <= max {
+		return value
+	}
+	return strings.TrimSpace(string(runes[:max-1])) + "…"
+}
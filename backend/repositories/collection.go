@@ -0,0 +1,274 @@
+package repositories
+
+import (
+	"burned/backend/database"
+	"burned/backend/models"
+	"burned/backend/pagination"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RecipeCollectionRepositoryInterface interface {
+	EnsureIndexes() error
+	CreateCollection(collection models.RecipeCollection) (models.RecipeCollection, error)
+	UpdateCollection(collection models.RecipeCollection) error
+	DeleteCollection(id primitive.ObjectID) (int64, error)
+	GetCollectionById(id primitive.ObjectID) (models.RecipeCollection, error)
+	GetCollectionByShareToken(token string) (models.RecipeCollection, error)
+	GetCollectionsByUserPaged(userId primitive.ObjectID, onlyPublic bool, page pagination.Request) (pagination.Page[models.RecipeCollection], error)
+	AddItem(id primitive.ObjectID, item models.CollectionItem) (bool, error)
+	RemoveItem(id primitive.ObjectID, recipeId primitive.ObjectID) (bool, error)
+	SetItems(id primitive.ObjectID, items []models.CollectionItem) error
+	RotateShareToken(id primitive.ObjectID) (string, error)
+	AddToDefault(userId primitive.ObjectID, recipeId primitive.ObjectID) error
+	RemoveFromDefault(userId primitive.ObjectID, recipeId primitive.ObjectID) error
+	CountItemsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error)
+	RemoveItemsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error)
+	CountCollectionsByUser(ctx context.Context, userId primitive.ObjectID, ownRecipes []primitive.ObjectID) (int64, error)
+	DeleteCollectionsByUser(ctx context.Context, userId primitive.ObjectID) (int64, error)
+}
+
+// collectionSorts son los órdenes que aceptan los listados de colecciones
+var collectionSorts = map[string]pagination.Sort{
+	"newest":  {Name: "newest", Field: "createdAt", Desc: true},
+	"updated": {Name: "updated", Field: "updatedAt", Desc: true},
+}
+
+type RecipeCollectionRepository struct {
+	db database.DB
+}
+
+func NewRecipeCollectionRepository(db database.DB) *RecipeCollectionRepository {
+	return &RecipeCollectionRepository{db: db}
+}
+
+// NewShareToken genera el identificador aleatorio de los links para compartir.
+func NewShareToken() (string, error) {
+	buffer := make([]byte, 16)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buffer), nil
+}
+
+// EnsureIndexes crea los índices de colecciones: una sola "Saved" por
+// usuario y búsqueda por link compartido.
+func (repository *RecipeCollectionRepository) EnsureIndexes() error {
+	collection := repository.db.GetClient().Database("Burned").Collection("RecipeCollection")
+	_, err := collection.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "isDefault", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"isDefault": true}),
+		},
+		{Keys: bson.D{{Key: "shareToken", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "items.recipeId", Value: 1}}},
+	})
+	return err
+}
+
+func (repository *RecipeCollectionRepository) CreateCollection(recipeCollection models.RecipeCollection) (models.RecipeCollection, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("RecipeCollection")
+	if recipeCollection.ShareToken == "" {
+		token, err := NewShareToken()
+		if err != nil {
+			return models.RecipeCollection{}, err
+		}
+		recipeCollection.ShareToken = token
+	}
+	if recipeCollection.Items == nil {
+		recipeCollection.Items = []models.CollectionItem{}
+	}
+	result, err := collection.InsertOne(context.TODO(), recipeCollection)
+	if err != nil {
+		return models.RecipeCollection{}, err
+	}
+	recipeCollection.ID = result.InsertedID.(primitive.ObjectID)
+	return recipeCollection, nil
+}
+
+func (repository *RecipeCollectionRepository) UpdateCollection(recipeCollection models.RecipeCollection) error {
+	collection := repository.db.GetClient().Database("Burned").Collection("RecipeCollection")
+	update := bson.M{"$set": bson.M{
+		"name":        recipeCollection.Name,
+		"description": recipeCollection.Description,
+		"coverImage":  recipeCollection.CoverImage,
+		"visibility":  recipeCollection.Visibility,
+		"updatedAt":   recipeCollection.UpdatedAt,
+	}}
+	_, err := collection.UpdateOne(context.TODO(), bson.M{"_id": recipeCollection.ID}, update)
+	return err
+}
+
+func (repository *RecipeCollectionRepository) DeleteCollection(id primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("RecipeCollection")
+	result, err := collection.DeleteOne(context.TODO(), bson.M{"_id": id})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+func (repository *RecipeCollectionRepository) GetCollectionById(id primitive.ObjectID) (models.RecipeCollection, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("RecipeCollection")
+	var recipeCollection models.RecipeCollection
+	err := collection.FindOne(context.TODO(), bson.M{"_id": id}).Decode(&recipeCollection)
+	return recipeCollection, err
+}
+
+func (repository *RecipeCollectionRepository) GetCollectionByShareToken(token string) (models.RecipeCollection, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("RecipeCollection")
+	var recipeCollection models.RecipeCollection
+	err := collection.FindOne(context.TODO(), bson.M{"shareToken": token}).Decode(&recipeCollection)
+	return recipeCollection, err
+}
+
+func (repository *RecipeCollectionRepository) GetCollectionsByUserPaged(userId primitive.ObjectID, onlyPublic bool, page pagination.Request) (pagination.Page[models.RecipeCollection], error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("RecipeCollection")
+	if page.Sort == "" {
+		page.Sort = "newest"
+	}
+	sort, ok := collectionSorts[page.Sort]
+	if !ok {
+		return pagination.Page[models.RecipeCollection]{}, pagination.ErrInvalidSort
+	}
+	filter := bson.M{"userId": userId}
+	if onlyPublic {
+		filter["visibility"] = "public"
+	}
+	return pagination.Find[models.RecipeCollection](context.TODO(), collection, filter, sort, page)
+}
+
+// AddItem agrega la receta al final de la colección. Devuelve false si ya estaba.
+func (repository *RecipeCollectionRepository) AddItem(id primitive.ObjectID, item models.CollectionItem) (bool, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("RecipeCollection")
+	filter := bson.M{"_id": id, "items.recipeId": bson.M{"$ne": item.RecipeID}}
+	update := bson.M{
+		"$push": bson.M{"items": item},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+	result, err := collection.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+func (repository *RecipeCollectionRepository) RemoveItem(id primitive.ObjectID, recipeId primitive.ObjectID) (bool, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("RecipeCollection")
+	filter := bson.M{"_id": id, "items.recipeId": recipeId}
+	update := bson.M{
+		"$pull": bson.M{"items": bson.M{"recipeId": recipeId}},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+	result, err := collection.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// SetItems reemplaza los ítems; se usa para reordenar.
+func (repository *RecipeCollectionRepository) SetItems(id primitive.ObjectID, items []models.CollectionItem) error {
+	collection := repository.db.GetClient().Database("Burned").Collection("RecipeCollection")
+	update := bson.M{"$set": bson.M{"items": items, "updatedAt": time.Now()}}
+	_, err := collection.UpdateOne(context.TODO(), bson.M{"_id": id}, update)
+	return err
+}
+
+// RotateShareToken invalida el link anterior generando uno nuevo.
+func (repository *RecipeCollectionRepository) RotateShareToken(id primitive.ObjectID) (string, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("RecipeCollection")
+	token, err := NewShareToken()
+	if err != nil {
+		return "", err
+	}
+	_, err = collection.UpdateOne(context.TODO(), bson.M{"_id": id}, bson.M{"$set": bson.M{"shareToken": token, "updatedAt": time.Now()}})
+	return token, err
+}
+
+// AddToDefault agrega la receta a la colección "Saved" del usuario,
+// creándola si todavía no existe.
+func (repository *RecipeCollectionRepository) AddToDefault(userId primitive.ObjectID, recipeId primitive.ObjectID) error {
+	collection := repository.db.GetClient().Database("Burned").Collection("RecipeCollection")
+	ctx := context.TODO()
+	token, err := NewShareToken()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	defaultFilter := bson.M{"userId": userId, "isDefault": true}
+	_, err = collection.UpdateOne(ctx, defaultFilter, bson.M{"$setOnInsert": bson.M{
+		"name":        models.DefaultCollectionName,
+		"description": "",
+		"coverImage":  "",
+		"visibility":  "private",
+		"shareToken":  token,
+		"items":       []models.CollectionItem{},
+		"createdAt":   now,
+		"updatedAt":   now,
+	}}, options.Update().SetUpsert(true))
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+
+	defaultFilter["items.recipeId"] = bson.M{"$ne": recipeId}
+	_, err = collection.UpdateOne(ctx, defaultFilter, bson.M{
+		"$push": bson.M{"items": models.CollectionItem{RecipeID: recipeId, AddedAt: now}},
+		"$set":  bson.M{"updatedAt": now},
+	})
+	return err
+}
+
+func (repository *RecipeCollectionRepository) RemoveFromDefault(userId primitive.ObjectID, recipeId primitive.ObjectID) error {
+	collection := repository.db.GetClient().Database("Burned").Collection("RecipeCollection")
+	_, err := collection.UpdateOne(context.TODO(), bson.M{"userId": userId, "isDefault": true}, bson.M{
+		"$pull": bson.M{"items": bson.M{"recipeId": recipeId}},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	})
+	return err
+}
+
+func (repository *RecipeCollectionRepository) CountItemsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error) {
+	if len(recipeIds) == 0 {
+		return 0, nil
+	}
+	collection := repository.db.GetClient().Database("Burned").Collection("RecipeCollection")
+	return collection.CountDocuments(ctx, bson.M{"items.recipeId": bson.M{"$in": recipeIds}})
+}
+
+// RemoveItemsByRecipes saca las recetas borradas de todas las colecciones.
+// Devuelve la cantidad de colecciones modificadas.
+func (repository *RecipeCollectionRepository) RemoveItemsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error) {
+	if len(recipeIds) == 0 {
+		return 0, nil
+	}
+	collection := repository.db.GetClient().Database("Burned").Collection("RecipeCollection")
+	result, err := collection.UpdateMany(ctx,
+		bson.M{"items.recipeId": bson.M{"$in": recipeIds}},
+		bson.M{"$pull": bson.M{"items": bson.M{"recipeId": bson.M{"$in": recipeIds}}}})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (repository *RecipeCollectionRepository) CountCollectionsByUser(ctx context.Context, userId primitive.ObjectID, ownRecipes []primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("RecipeCollection")
+	return collection.CountDocuments(ctx, bson.M{"userId": userId})
+}
+
+func (repository *RecipeCollectionRepository) DeleteCollectionsByUser(ctx context.Context, userId primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("RecipeCollection")
+	result, err := collection.DeleteMany(ctx, bson.M{"userId": userId})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
@@ -3,7 +3,9 @@ package repositories
 import (
 	"burned/backend/database"
 	"burned/backend/models"
+	"burned/backend/pagination"
 	"context"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -16,6 +18,29 @@ type CommentRepositoryInterface interface {
 	DeleteComment(id primitive.ObjectID) (*mongo.DeleteResult, error)
 	GetCommentsByRecipe(recipeId primitive.ObjectID) ([]models.Comment, error)
 	GetCommentById(Id primitive.ObjectID) (models.Comment, error)
+	GetCommentsByIds(ids []primitive.ObjectID) ([]models.Comment, error)
+	GetCommentsByRecipePaged(recipeId primitive.ObjectID, page pagination.Request) (pagination.Page[models.Comment], error)
+	GetRepliesPaged(parentId primitive.ObjectID, page pagination.Request) (pagination.Page[models.Comment], error)
+	AddReplyCount(id primitive.ObjectID, delta int) (models.Comment, error)
+	TombstoneComment(id primitive.ObjectID) error
+	SetCommentHidden(id primitive.ObjectID, hidden bool) error
+	UpdateCommentText(id primitive.ObjectID, text string, editedAt time.Time) error
+	SaveCommentEdit(edit models.CommentEdit) error
+	GetCommentEdits(commentId primitive.ObjectID) ([]models.CommentEdit, error)
+	DeleteCommentEdits(commentId primitive.ObjectID) error
+	CountCommentEditsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error)
+	DeleteCommentEditsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error)
+	EnsureIndexes() error
+	CountCommentsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error)
+	DeleteCommentsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error)
+	CountCommentsByUser(ctx context.Context, userId primitive.ObjectID, excludeRecipes []primitive.ObjectID) (int64, error)
+	AnonymizeCommentsByUser(ctx context.Context, userId primitive.ObjectID) (int64, error)
+}
+
+// commentSorts son los órdenes que aceptan los listados de comentarios
+var commentSorts = map[string]pagination.Sort{
+	"newest": {Name: "newest", Field: "createdAt", Desc: true},
+	"oldest": {Name: "oldest", Field: "createdAt"},
 }
 
 type CommentRepository struct {
@@ -26,6 +51,23 @@ func NewCommentRepository(db database.DB) *CommentRepository {
 	return &CommentRepository{db: db}
 }
 
+func (repository *CommentRepository) EnsureIndexes() error {
+	collection := repository.db.GetClient().Database("Burned").Collection("Comment")
+	_, err := collection.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "recipeId", Value: 1}, {Key: "parentId", Value: 1}, {Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "parentId", Value: 1}, {Key: "createdAt", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+	edits := repository.db.GetClient().Database("Burned").Collection("CommentEdit")
+	_, err = edits.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "commentId", Value: 1}, {Key: "replacedAt", Value: -1}}},
+		{Keys: bson.D{{Key: "recipeId", Value: 1}}},
+	})
+	return err
+}
+
 func (repository *CommentRepository) CreateComment(comment models.Comment) (*mongo.InsertOneResult, error) {
 	collection := repository.db.GetClient().Database("Burned").Collection("Comment")
 	return collection.InsertOne(context.TODO(), comment)
@@ -41,7 +83,7 @@ func (repository *CommentRepository) GetCommentsByRecipe(recipeId primitive.Obje
 	collection := repository.db.GetClient().Database("Burned").Collection("Comment")
 	filter := bson.M{"recipeId": recipeId}
 
-	opts := options.Find().SetSort(bson.D{{"createdAt", -1}})
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
 
 	cursor, err := collection.Find(context.TODO(), filter, opts)
 	if err != nil {
@@ -56,6 +98,64 @@ func (repository *CommentRepository) GetCommentsByRecipe(recipeId primitive.Obje
 	return comments, nil
 }
 
+func (repository *CommentRepository) GetCommentsByRecipePaged(recipeId primitive.ObjectID, page pagination.Request) (pagination.Page[models.Comment], error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Comment")
+	if page.Sort == "" {
+		page.Sort = "newest"
+	}
+	sort, ok := commentSorts[page.Sort]
+	if !ok {
+		return pagination.Page[models.Comment]{}, pagination.ErrInvalidSort
+	}
+	//solo los comentarios de primer nivel; las respuestas se piden por hilo
+	filter := bson.M{"recipeId": recipeId, "parentId": nil}
+	return pagination.Find[models.Comment](context.TODO(), collection, filter, sort, page)
+}
+
+// GetRepliesPaged lista las respuestas directas de un comentario, por
+// defecto de la más vieja a la más nueva para leer la conversación en orden.
+func (repository *CommentRepository) GetRepliesPaged(parentId primitive.ObjectID, page pagination.Request) (pagination.Page[models.Comment], error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Comment")
+	if page.Sort == "" {
+		page.Sort = "oldest"
+	}
+	sort, ok := commentSorts[page.Sort]
+	if !ok {
+		return pagination.Page[models.Comment]{}, pagination.ErrInvalidSort
+	}
+	return pagination.Find[models.Comment](context.TODO(), collection, bson.M{"parentId": parentId}, sort, page)
+}
+
+// AddReplyCount suma delta al contador de respuestas y devuelve el
+// comentario actualizado.
+func (repository *CommentRepository) AddReplyCount(id primitive.ObjectID, delta int) (models.Comment, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Comment")
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var comment models.Comment
+	err := collection.FindOneAndUpdate(context.TODO(), bson.M{"_id": id}, bson.M{"$inc": bson.M{"replyCount": delta}}, opts).Decode(&comment)
+	return comment, err
+}
+
+// TombstoneComment borra el contenido y el autor pero deja el documento,
+// para que sus respuestas sigan colgando del hilo.
+func (repository *CommentRepository) TombstoneComment(id primitive.ObjectID) error {
+	collection := repository.db.GetClient().Database("Burned").Collection("Comment")
+	update := bson.M{"$set": bson.M{
+		"deleted":  true,
+		"text":     models.DeletedCommentText,
+		"userId":   primitive.NilObjectID,
+		"userName": models.DeletedUserName,
+	}}
+	_, err := collection.UpdateOne(context.TODO(), bson.M{"_id": id}, update)
+	return err
+}
+
+func (repository *CommentRepository) SetCommentHidden(id primitive.ObjectID, hidden bool) error {
+	collection := repository.db.GetClient().Database("Burned").Collection("Comment")
+	_, err := collection.UpdateOne(context.TODO(), bson.M{"_id": id}, bson.M{"$set": bson.M{"hidden": hidden}})
+	return err
+}
+
 func (repository *CommentRepository) GetCommentById(Id primitive.ObjectID) (models.Comment, error) {
 	collection := repository.db.GetClient().Database("Burned").Collection("Comment")
 	filter := bson.M{"_id": Id}
@@ -67,3 +167,124 @@ func (repository *CommentRepository) GetCommentById(Id primitive.ObjectID) (mode
 
 	return comment, nil
 }
+
+// GetCommentsByIds trae varios comentarios en una consulta, sin orden garantizado.
+func (repository *CommentRepository) GetCommentsByIds(ids []primitive.ObjectID) ([]models.Comment, error) {
+	if len(ids) == 0 {
+		return []models.Comment{}, nil
+	}
+	collection := repository.db.GetClient().Database("Burned").Collection("Comment")
+	cursor, err := collection.Find(context.TODO(), bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var comments []models.Comment
+	if err := cursor.All(context.TODO(), &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+func (repository *CommentRepository) CountCommentsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error) {
+	if len(recipeIds) == 0 {
+		return 0, nil
+	}
+	collection := repository.db.GetClient().Database("Burned").Collection("Comment")
+	return collection.CountDocuments(ctx, bson.M{"recipeId": bson.M{"$in": recipeIds}})
+}
+
+func (repository *CommentRepository) DeleteCommentsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error) {
+	if len(recipeIds) == 0 {
+		return 0, nil
+	}
+	collection := repository.db.GetClient().Database("Burned").Collection("Comment")
+	result, err := collection.DeleteMany(ctx, bson.M{"recipeId": bson.M{"$in": recipeIds}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+func (repository *CommentRepository) CountCommentsByUser(ctx context.Context, userId primitive.ObjectID, excludeRecipes []primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Comment")
+	return collection.CountDocuments(ctx, withoutRecipes(bson.M{"userId": userId}, excludeRecipes))
+}
+
+// AnonymizeCommentsByUser desvincula los comentarios del usuario en lugar de
+// borrarlos, para no romper las conversaciones en recetas ajenas.
+func (repository *CommentRepository) AnonymizeCommentsByUser(ctx context.Context, userId primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Comment")
+	update := bson.M{"$set": bson.M{
+		"userId":   primitive.NilObjectID,
+		"userName": models.DeletedUserName,
+	}}
+	result, err := collection.UpdateMany(ctx, bson.M{"userId": userId}, update)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (repository *CommentRepository) UpdateCommentText(id primitive.ObjectID, text string, editedAt time.Time) error {
+	collection := repository.db.GetClient().Database("Burned").Collection("Comment")
+	update := bson.M{"$set": bson.M{"text": text, "editedAt": editedAt}}
+	result, err := collection.UpdateOne(context.TODO(), bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (repository *CommentRepository) SaveCommentEdit(edit models.CommentEdit) error {
+	collection := repository.db.GetClient().Database("Burned").Collection("CommentEdit")
+	_, err := collection.InsertOne(context.TODO(), edit)
+	return err
+}
+
+// GetCommentEdits devuelve las versiones anteriores, la más reciente primero.
+func (repository *CommentRepository) GetCommentEdits(commentId primitive.ObjectID) ([]models.CommentEdit, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("CommentEdit")
+	opts := options.Find().SetSort(bson.D{{Key: "replacedAt", Value: -1}})
+	cursor, err := collection.Find(context.TODO(), bson.M{"commentId": commentId}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	edits := []models.CommentEdit{}
+	if err = cursor.All(context.TODO(), &edits); err != nil {
+		return nil, err
+	}
+	return edits, nil
+}
+
+func (repository *CommentRepository) DeleteCommentEdits(commentId primitive.ObjectID) error {
+	collection := repository.db.GetClient().Database("Burned").Collection("CommentEdit")
+	_, err := collection.DeleteMany(context.TODO(), bson.M{"commentId": commentId})
+	return err
+}
+
+func (repository *CommentRepository) CountCommentEditsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error) {
+	if len(recipeIds) == 0 {
+		return 0, nil
+	}
+	collection := repository.db.GetClient().Database("Burned").Collection("CommentEdit")
+	return collection.CountDocuments(ctx, bson.M{"recipeId": bson.M{"$in": recipeIds}})
+}
+
+func (repository *CommentRepository) DeleteCommentEditsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error) {
+	if len(recipeIds) == 0 {
+		return 0, nil
+	}
+	collection := repository.db.GetClient().Database("Burned").Collection("CommentEdit")
+	result, err := collection.DeleteMany(ctx, bson.M{"recipeId": bson.M{"$in": recipeIds}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,151 @@
+package repositories
+
+import (
+	"burned/backend/database"
+	"burned/backend/models"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxFeedSessions acota los eventos del feed de calendario
+const maxFeedSessions = 200
+
+type CookSessionRepositoryInterface interface {
+	EnsureIndexes() error
+	CreateSession(session models.CookSession) (models.CookSession, error)
+	DeleteSession(id primitive.ObjectID, userId primitive.ObjectID) (int64, error)
+	GetSessionsByUser(userId primitive.ObjectID, since time.Time) ([]models.CookSession, error)
+	SetFeed(feed models.CalendarFeed) error
+	DeleteFeed(userId primitive.ObjectID) (int64, error)
+	GetFeedByTokenHash(tokenHash string) (models.CalendarFeed, error)
+	CountSessionsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error)
+	DeleteSessionsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error)
+	CountSessionsByUser(ctx context.Context, userId primitive.ObjectID, ownRecipes []primitive.ObjectID) (int64, error)
+	DeleteSessionsByUser(ctx context.Context, userId primitive.ObjectID) (int64, error)
+}
+
+type CookSessionRepository struct {
+	db database.DB
+}
+
+func NewCookSessionRepository(db database.DB) *CookSessionRepository {
+	return &CookSessionRepository{db: db}
+}
+
+func (repository *CookSessionRepository) EnsureIndexes() error {
+	sessions := repository.db.GetClient().Database("Burned").Collection("CookSession")
+	if _, err := sessions.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{{Key: "userId", Value: 1}, {Key: "serveAt", Value: 1}},
+	}); err != nil {
+		return err
+	}
+	feeds := repository.db.GetClient().Database("Burned").Collection("CalendarFeed")
+	_, err := feeds.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "tokenHash", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+func (repository *CookSessionRepository) CreateSession(session models.CookSession) (models.CookSession, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("CookSession")
+	result, err := collection.InsertOne(context.TODO(), session)
+	if err != nil {
+		return models.CookSession{}, err
+	}
+	session.ID = result.InsertedID.(primitive.ObjectID)
+	return session, nil
+}
+
+func (repository *CookSessionRepository) DeleteSession(id primitive.ObjectID, userId primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("CookSession")
+	result, err := collection.DeleteOne(context.TODO(), bson.M{"_id": id, "userId": userId})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// GetSessionsByUser devuelve las sesiones desde una fecha, las más próximas primero.
+func (repository *CookSessionRepository) GetSessionsByUser(userId primitive.ObjectID, since time.Time) ([]models.CookSession, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("CookSession")
+	opts := options.Find().SetSort(bson.D{{Key: "serveAt", Value: 1}}).SetLimit(maxFeedSessions)
+	cursor, err := collection.Find(context.TODO(), bson.M{"userId": userId, "serveAt": bson.M{"$gte": since}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	sessions := []models.CookSession{}
+	if err := cursor.All(context.TODO(), &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// SetFeed crea o reemplaza el feed del usuario; el token anterior deja de valer.
+func (repository *CookSessionRepository) SetFeed(feed models.CalendarFeed) error {
+	collection := repository.db.GetClient().Database("Burned").Collection("CalendarFeed")
+	_, err := collection.ReplaceOne(context.TODO(), bson.M{"_id": feed.UserID}, feed, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (repository *CookSessionRepository) DeleteFeed(userId primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("CalendarFeed")
+	result, err := collection.DeleteOne(context.TODO(), bson.M{"_id": userId})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+func (repository *CookSessionRepository) GetFeedByTokenHash(tokenHash string) (models.CalendarFeed, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("CalendarFeed")
+	var feed models.CalendarFeed
+	err := collection.FindOne(context.TODO(), bson.M{"tokenHash": tokenHash}).Decode(&feed)
+	return feed, err
+}
+
+func (repository *CookSessionRepository) CountSessionsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error) {
+	if len(recipeIds) == 0 {
+		return 0, nil
+	}
+	collection := repository.db.GetClient().Database("Burned").Collection("CookSession")
+	return collection.CountDocuments(ctx, bson.M{"recipeId": bson.M{"$in": recipeIds}})
+}
+
+func (repository *CookSessionRepository) DeleteSessionsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error) {
+	if len(recipeIds) == 0 {
+		return 0, nil
+	}
+	collection := repository.db.GetClient().Database("Burned").Collection("CookSession")
+	result, err := collection.DeleteMany(ctx, bson.M{"recipeId": bson.M{"$in": recipeIds}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+func (repository *CookSessionRepository) CountSessionsByUser(ctx context.Context, userId primitive.ObjectID, ownRecipes []primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("CookSession")
+	return collection.CountDocuments(ctx, withoutRecipes(bson.M{"userId": userId}, ownRecipes))
+}
+
+// DeleteSessionsByUser borra las sesiones y el feed de calendario del usuario.
+func (repository *CookSessionRepository) DeleteSessionsByUser(ctx context.Context, userId primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("CookSession")
+	result, err := collection.DeleteMany(ctx, bson.M{"userId": userId})
+	if err != nil {
+		return 0, err
+	}
+	feeds := repository.db.GetClient().Database("Burned").Collection("CalendarFeed")
+	if _, err := feeds.DeleteOne(ctx, bson.M{"_id": userId}); err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
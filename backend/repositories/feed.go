@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"burned/backend/database"
+	"burned/backend/models"
+	"burned/backend/pagination"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FeedRetention es cuánto dura una entrada en los feeds; las más viejas las
+// borra Mongo solo (índice TTL), así el feed de cada usuario no crece sin fin.
+const FeedRetention = 90 * 24 * time.Hour
+
+type FeedRepositoryInterface interface {
+	EnsureIndexes() error
+	AddItems(items []models.FeedItem) error
+	GetFeedPaged(ownerId primitive.ObjectID, page pagination.Request) (pagination.Page[models.FeedItem], error)
+	GetRecentActivity(actorId primitive.ObjectID, since time.Time, limit int) ([]models.FeedItem, error)
+	DeleteItemsByActor(ownerId primitive.ObjectID, actorId primitive.ObjectID) error
+	CountItemsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error)
+	DeleteItemsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error)
+	CountItemsByUser(ctx context.Context, userId primitive.ObjectID, ownRecipes []primitive.ObjectID) (int64, error)
+	DeleteItemsByUser(ctx context.Context, userId primitive.ObjectID) (int64, error)
+}
+
+// feedSorts: el feed va siempre de lo más nuevo a lo más viejo
+var feedSorts = map[string]pagination.Sort{
+	"newest": {Name: "newest", Field: "createdAt", Desc: true},
+}
+
+type FeedRepository struct {
+	db database.DB
+}
+
+func NewFeedRepository(db database.DB) *FeedRepository {
+	return &FeedRepository{db: db}
+}
+
+// EnsureIndexes crea los índices del feed: lectura por dueño en orden, una
+// sola entrada por publicación en cada feed y vencimiento por antigüedad.
+func (repository *FeedRepository) EnsureIndexes() error {
+	collection := repository.db.GetClient().Database("Burned").Collection("FeedItem")
+	_, err := collection.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "ownerId", Value: 1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}},
+		{
+			Keys:    bson.D{{Key: "ownerId", Value: 1}, {Key: "targetId", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "actorId", Value: 1}}},
+		{Keys: bson.D{{Key: "recipeId", Value: 1}}},
+		{
+			Keys:    bson.D{{Key: "createdAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(FeedRetention.Seconds())),
+		},
+	})
+	return err
+}
+
+// AddItems guarda entradas en los feeds. Las que ya estaban (misma
+// publicación en el mismo feed) se ignoran, así repartir dos veces no duplica.
+func (repository *FeedRepository) AddItems(items []models.FeedItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	collection := repository.db.GetClient().Database("Burned").Collection("FeedItem")
+	documents := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		documents = append(documents, item)
+	}
+	_, err := collection.InsertMany(context.TODO(), documents, options.InsertMany().SetOrdered(false))
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+	return nil
+}
+
+func (repository *FeedRepository) GetFeedPaged(ownerId primitive.ObjectID, page pagination.Request) (pagination.Page[models.FeedItem], error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("FeedItem")
+	if page.Sort == "" {
+		page.Sort = "newest"
+	}
+	sort, ok := feedSorts[page.Sort]
+	if !ok {
+		return pagination.Page[models.FeedItem]{}, pagination.ErrInvalidSort
+	}
+	return pagination.Find[models.FeedItem](context.TODO(), collection, bson.M{"ownerId": ownerId}, sort, page)
+}
+
+// GetRecentActivity arma, sin dueño, las entradas de lo último que publicó
+// actorId: recetas públicas y comentarios. Sirve para llenar el feed de
+// alguien que lo empieza a seguir.
+func (repository *FeedRepository) GetRecentActivity(actorId primitive.ObjectID, since time.Time, limit int) ([]models.FeedItem, error) {
+	burned := repository.db.GetClient().Database("Burned")
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}).SetLimit(int64(limit))
+
+	recipeFilter := bson.M{"userId": actorId, "visibility": "public", "hidden": notHidden, "createdAt": bson.M{"$gte": since}}
+	cursor, err := burned.Collection("Recipe").Find(context.TODO(), recipeFilter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var recipes []models.Recipe
+	if err := cursor.All(context.TODO(), &recipes); err != nil {
+		return nil, err
+	}
+
+	commentFilter := bson.M{"userId": actorId, "deleted": bson.M{"$ne": true}, "hidden": notHidden, "createdAt": bson.M{"$gte": since}}
+	cursor, err = burned.Collection("Comment").Find(context.TODO(), commentFilter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var comments []models.Comment
+	if err := cursor.All(context.TODO(), &comments); err != nil {
+		return nil, err
+	}
+
+	items := make([]models.FeedItem, 0, len(recipes)+len(comments))
+	for _, recipe := range recipes {
+		items = append(items, models.FeedItem{ActorID: actorId, Type: models.FeedRecipe, TargetID: recipe.ID, RecipeID: recipe.ID, CreatedAt: recipe.CreatedAt})
+	}
+	for _, comment := range comments {
+		items = append(items, models.FeedItem{ActorID: actorId, Type: models.FeedComment, TargetID: comment.ID, RecipeID: comment.RecipeID, CreatedAt: comment.CreatedAt})
+	}
+	return items, nil
+}
+
+// DeleteItemsByActor saca del feed de ownerId lo de actorId, al dejar de seguirlo.
+func (repository *FeedRepository) DeleteItemsByActor(ownerId primitive.ObjectID, actorId primitive.ObjectID) error {
+	collection := repository.db.GetClient().Database("Burned").Collection("FeedItem")
+	_, err := collection.DeleteMany(context.TODO(), bson.M{"ownerId": ownerId, "actorId": actorId})
+	return err
+}
+
+func (repository *FeedRepository) CountItemsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error) {
+	if len(recipeIds) == 0 {
+		return 0, nil
+	}
+	collection := repository.db.GetClient().Database("Burned").Collection("FeedItem")
+	return collection.CountDocuments(ctx, bson.M{"recipeId": bson.M{"$in": recipeIds}})
+}
+
+func (repository *FeedRepository) DeleteItemsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error) {
+	if len(recipeIds) == 0 {
+		return 0, nil
+	}
+	collection := repository.db.GetClient().Database("Burned").Collection("FeedItem")
+	result, err := collection.DeleteMany(ctx, bson.M{"recipeId": bson.M{"$in": recipeIds}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// CountItemsByUser cuenta el feed del usuario y lo suyo en feeds ajenos,
+// sin lo de sus recetas, que ya cuenta la cascada de recetas.
+func (repository *FeedRepository) CountItemsByUser(ctx context.Context, userId primitive.ObjectID, ownRecipes []primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("FeedItem")
+	filter := bson.M{"$or": []bson.M{{"ownerId": userId}, {"actorId": userId}}}
+	return collection.CountDocuments(ctx, withoutRecipes(filter, ownRecipes))
+}
+
+func (repository *FeedRepository) DeleteItemsByUser(ctx context.Context, userId primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("FeedItem")
+	result, err := collection.DeleteMany(ctx, bson.M{"$or": []bson.M{{"ownerId": userId}, {"actorId": userId}}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,196 @@
+package repositories
+
+import (
+	"burned/backend/database"
+	"burned/backend/models"
+	"burned/backend/pagination"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type FollowRepositoryInterface interface {
+	EnsureIndexes() error
+	Follow(follow models.Follow) (bool, error)
+	Unfollow(followerId primitive.ObjectID, followeeId primitive.ObjectID) (bool, error)
+	IsFollowing(followerId primitive.ObjectID, followeeId primitive.ObjectID) (bool, error)
+	GetFollowersPaged(userId primitive.ObjectID, page pagination.Request) (pagination.Page[models.Follow], error)
+	GetFollowingPaged(userId primitive.ObjectID, page pagination.Request) (pagination.Page[models.Follow], error)
+	GetFollowerBatch(userId primitive.ObjectID, after primitive.ObjectID, limit int) ([]models.Follow, error)
+	CountFollowsByUser(ctx context.Context, userId primitive.ObjectID, ownRecipes []primitive.ObjectID) (int64, error)
+	DeleteFollowsByUser(ctx context.Context, userId primitive.ObjectID) (int64, error)
+}
+
+// followSorts son los órdenes de los listados de seguidores y seguidos
+var followSorts = map[string]pagination.Sort{
+	"newest": {Name: "newest", Field: "createdAt", Desc: true},
+	"oldest": {Name: "oldest", Field: "createdAt"},
+}
+
+type FollowRepository struct {
+	db database.DB
+}
+
+func NewFollowRepository(db database.DB) *FollowRepository {
+	return &FollowRepository{db: db}
+}
+
+// EnsureIndexes crea los índices de seguimientos: una sola relación por par
+// y listados por cada lado.
+func (repository *FollowRepository) EnsureIndexes() error {
+	collection := repository.db.GetClient().Database("Burned").Collection("Follow")
+	_, err := collection.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "followerId", Value: 1}, {Key: "followeeId", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "followeeId", Value: 1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "followerId", Value: 1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "followeeId", Value: 1}, {Key: "_id", Value: 1}}},
+	})
+	return err
+}
+
+// Follow guarda la relación y actualiza los contadores de los dos usuarios.
+// Devuelve false si ya lo seguía.
+func (repository *FollowRepository) Follow(follow models.Follow) (bool, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Follow")
+	if _, err := collection.InsertOne(context.TODO(), follow); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	//mantenemos los contadores en el usuario para no contar en cada perfil
+	if err := repository.incrementCounts(context.TODO(), follow.FollowerID, follow.FolloweeID, 1); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Unfollow borra la relación. Devuelve false si no lo seguía.
+func (repository *FollowRepository) Unfollow(followerId primitive.ObjectID, followeeId primitive.ObjectID) (bool, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Follow")
+	result, err := collection.DeleteOne(context.TODO(), bson.M{"followerId": followerId, "followeeId": followeeId})
+	if err != nil {
+		return false, err
+	}
+	if result.DeletedCount == 0 {
+		return false, nil
+	}
+	if err := repository.incrementCounts(context.TODO(), followerId, followeeId, -1); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (repository *FollowRepository) incrementCounts(ctx context.Context, followerId primitive.ObjectID, followeeId primitive.ObjectID, delta int) error {
+	users := repository.db.GetClient().Database("Burned").Collection("User")
+	if _, err := users.UpdateOne(ctx, bson.M{"_id": followerId}, bson.M{"$inc": bson.M{"followingCount": delta}}); err != nil {
+		return err
+	}
+	_, err := users.UpdateOne(ctx, bson.M{"_id": followeeId}, bson.M{"$inc": bson.M{"followerCount": delta}})
+	return err
+}
+
+func (repository *FollowRepository) IsFollowing(followerId primitive.ObjectID, followeeId primitive.ObjectID) (bool, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Follow")
+	count, err := collection.CountDocuments(context.TODO(), bson.M{"followerId": followerId, "followeeId": followeeId}, options.Count().SetLimit(1))
+	return count > 0, err
+}
+
+// GetFollowersPaged lista a quienes siguen a userId.
+func (repository *FollowRepository) GetFollowersPaged(userId primitive.ObjectID, page pagination.Request) (pagination.Page[models.Follow], error) {
+	return repository.findPaged(bson.M{"followeeId": userId}, page)
+}
+
+// GetFollowingPaged lista a quienes sigue userId.
+func (repository *FollowRepository) GetFollowingPaged(userId primitive.ObjectID, page pagination.Request) (pagination.Page[models.Follow], error) {
+	return repository.findPaged(bson.M{"followerId": userId}, page)
+}
+
+func (repository *FollowRepository) findPaged(filter bson.M, page pagination.Request) (pagination.Page[models.Follow], error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Follow")
+	if page.Sort == "" {
+		page.Sort = "newest"
+	}
+	sort, ok := followSorts[page.Sort]
+	if !ok {
+		return pagination.Page[models.Follow]{}, pagination.ErrInvalidSort
+	}
+	return pagination.Find[models.Follow](context.TODO(), collection, filter, sort, page)
+}
+
+// GetFollowerBatch recorre los seguidores de userId por tandas, en orden de
+// _id a partir de after, para repartir una publicación en sus feeds.
+func (repository *FollowRepository) GetFollowerBatch(userId primitive.ObjectID, after primitive.ObjectID, limit int) ([]models.Follow, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Follow")
+	filter := bson.M{"followeeId": userId}
+	if !after.IsZero() {
+		filter["_id"] = bson.M{"$gt": after}
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{"followerId": 1})
+	cursor, err := collection.Find(context.TODO(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	follows := []models.Follow{}
+	if err := cursor.All(context.TODO(), &follows); err != nil {
+		return nil, err
+	}
+	return follows, nil
+}
+
+func (repository *FollowRepository) CountFollowsByUser(ctx context.Context, userId primitive.ObjectID, ownRecipes []primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Follow")
+	return collection.CountDocuments(ctx, bson.M{"$or": []bson.M{{"followerId": userId}, {"followeeId": userId}}})
+}
+
+// DeleteFollowsByUser borra las relaciones del usuario en los dos sentidos y
+// descuenta los contadores de los demás.
+func (repository *FollowRepository) DeleteFollowsByUser(ctx context.Context, userId primitive.ObjectID) (int64, error) {
+	burned := repository.db.GetClient().Database("Burned")
+	collection := burned.Collection("Follow")
+	filter := bson.M{"$or": []bson.M{{"followerId": userId}, {"followeeId": userId}}}
+	cursor, err := collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"followerId": 1, "followeeId": 1}))
+	if err != nil {
+		return 0, err
+	}
+	var follows []models.Follow
+	if err := cursor.All(ctx, &follows); err != nil {
+		return 0, err
+	}
+	var followers, followees []primitive.ObjectID
+	for _, follow := range follows {
+		if follow.FollowerID == userId {
+			followees = append(followees, follow.FolloweeID)
+		} else {
+			followers = append(followers, follow.FollowerID)
+		}
+	}
+
+	users := burned.Collection("User")
+	if len(followees) > 0 {
+		if _, err := users.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": followees}}, bson.M{"$inc": bson.M{"followerCount": -1}}); err != nil {
+			return 0, err
+		}
+	}
+	if len(followers) > 0 {
+		if _, err := users.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": followers}}, bson.M{"$inc": bson.M{"followingCount": -1}}); err != nil {
+			return 0, err
+		}
+	}
+	result, err := collection.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
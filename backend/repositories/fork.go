@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"burned/backend/models"
+	"burned/backend/pagination"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EnsureForkIndex indexa el linaje para contar y listar versiones derivadas.
+func (repository *RecipeRepository) EnsureForkIndex() error {
+	collection := repository.db.GetClient().Database("Burned").Collection("Recipe")
+	_, err := collection.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "forkedFrom.recipeId", Value: 1}}},
+		{Keys: bson.D{{Key: "lineage.recipeId", Value: 1}}},
+	})
+	return err
+}
+
+// CountForks cuenta las versiones públicas copiadas directamente de la receta.
+func (repository *RecipeRepository) CountForks(recipeId primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Recipe")
+	return collection.CountDocuments(context.TODO(), bson.M{"forkedFrom.recipeId": recipeId, "visibility": "public", "hidden": notHidden})
+}
+
+// GetForksPaged lista las versiones públicas de una receta. Con descendants
+// incluye también las versiones de versiones.
+func (repository *RecipeRepository) GetForksPaged(recipeId primitive.ObjectID, descendants bool, page pagination.Request) (pagination.Page[models.Recipe], error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Recipe")
+	sort, err := recipeSort(page.Sort, "newest")
+	if err != nil {
+		return pagination.Page[models.Recipe]{}, err
+	}
+	filter := bson.M{"forkedFrom.recipeId": recipeId, "visibility": "public", "hidden": notHidden}
+	if descendants {
+		filter = bson.M{"lineage.recipeId": recipeId, "visibility": "public", "hidden": notHidden}
+	}
+	return pagination.Find[models.Recipe](context.TODO(), collection, filter, sort, page)
+}
+
+// CountLineageByUser cuenta las recetas ajenas que atribuyen al usuario.
+func (repository *RecipeRepository) CountLineageByUser(ctx context.Context, userId primitive.ObjectID, ownRecipes []primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Recipe")
+	filter := bson.M{"lineage.userId": userId}
+	if len(ownRecipes) > 0 {
+		filter["_id"] = bson.M{"$nin": ownRecipes}
+	}
+	return collection.CountDocuments(ctx, filter)
+}
+
+// AnonymizeLineageByUser reemplaza el nombre del usuario en la atribución de
+// las versiones derivadas; el linaje se conserva, sin datos personales.
+func (repository *RecipeRepository) AnonymizeLineageByUser(ctx context.Context, userId primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Recipe")
+	update := bson.M{"$set": bson.M{
+		"lineage.$[origin].userId":   primitive.NilObjectID,
+		"lineage.$[origin].userName": models.DeletedUserName,
+	}}
+	opts := options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{bson.M{"origin.userId": userId}}})
+	result, err := collection.UpdateMany(ctx, bson.M{"lineage.userId": userId}, update, opts)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := collection.UpdateMany(ctx, bson.M{"forkedFrom.userId": userId}, bson.M{"$set": bson.M{
+		"forkedFrom.userId":   primitive.NilObjectID,
+		"forkedFrom.userName": models.DeletedUserName,
+	}}); err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	"burned/backend/database"
+	"burned/backend/models"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MealPlanRepositoryInterface interface {
+	EnsureIndexes() error
+	CreateEntries(entries []models.MealPlanEntry) ([]models.MealPlanEntry, error)
+	UpdateEntry(entry models.MealPlanEntry) error
+	DeleteEntry(id primitive.ObjectID, userId primitive.ObjectID) (int64, error)
+	GetEntryById(id primitive.ObjectID) (models.MealPlanEntry, error)
+	GetEntriesByRange(userId primitive.ObjectID, from string, to string) ([]models.MealPlanEntry, error)
+	DeleteEntriesByRange(userId primitive.ObjectID, from string, to string) (int64, error)
+	CountEntriesByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error)
+	DeleteEntriesByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error)
+	CountEntriesByUser(ctx context.Context, userId primitive.ObjectID, ownRecipes []primitive.ObjectID) (int64, error)
+	DeleteEntriesByUser(ctx context.Context, userId primitive.ObjectID) (int64, error)
+}
+
+type MealPlanRepository struct {
+	db database.DB
+}
+
+func NewMealPlanRepository(db database.DB) *MealPlanRepository {
+	return &MealPlanRepository{db: db}
+}
+
+func (repository *MealPlanRepository) EnsureIndexes() error {
+	collection := repository.db.GetClient().Database("Burned").Collection("MealPlan")
+	_, err := collection.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "date", Value: 1}}},
+		{Keys: bson.D{{Key: "recipeId", Value: 1}}},
+	})
+	return err
+}
+
+func (repository *MealPlanRepository) CreateEntries(entries []models.MealPlanEntry) ([]models.MealPlanEntry, error) {
+	if len(entries) == 0 {
+		return []models.MealPlanEntry{}, nil
+	}
+	collection := repository.db.GetClient().Database("Burned").Collection("MealPlan")
+	documents := make([]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		documents = append(documents, entry)
+	}
+	result, err := collection.InsertMany(context.TODO(), documents)
+	if err != nil {
+		return nil, err
+	}
+	for i, id := range result.InsertedIDs {
+		entries[i].ID = id.(primitive.ObjectID)
+	}
+	return entries, nil
+}
+
+func (repository *MealPlanRepository) UpdateEntry(entry models.MealPlanEntry) error {
+	collection := repository.db.GetClient().Database("Burned").Collection("MealPlan")
+	update := bson.M{"$set": bson.M{
+		"recipeId":  entry.RecipeID,
+		"date":      entry.Date,
+		"slot":      entry.Slot,
+		"servings":  entry.Servings,
+		"updatedAt": entry.UpdatedAt,
+	}}
+	_, err := collection.UpdateOne(context.TODO(), bson.M{"_id": entry.ID, "userId": entry.UserID}, update)
+	return err
+}
+
+func (repository *MealPlanRepository) DeleteEntry(id primitive.ObjectID, userId primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("MealPlan")
+	result, err := collection.DeleteOne(context.TODO(), bson.M{"_id": id, "userId": userId})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+func (repository *MealPlanRepository) GetEntryById(id primitive.ObjectID) (models.MealPlanEntry, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("MealPlan")
+	var entry models.MealPlanEntry
+	err := collection.FindOne(context.TODO(), bson.M{"_id": id}).Decode(&entry)
+	return entry, err
+}
+
+// GetEntriesByRange devuelve el plan entre dos fechas inclusive, por día y en
+// el orden en que se cargaron.
+func (repository *MealPlanRepository) GetEntriesByRange(userId primitive.ObjectID, from string, to string) ([]models.MealPlanEntry, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("MealPlan")
+	filter := bson.M{"userId": userId, "date": bson.M{"$gte": from, "$lte": to}}
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}, {Key: "createdAt", Value: 1}})
+
+	cursor, err := collection.Find(context.TODO(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	entries := []models.MealPlanEntry{}
+	if err := cursor.All(context.TODO(), &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (repository *MealPlanRepository) DeleteEntriesByRange(userId primitive.ObjectID, from string, to string) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("MealPlan")
+	result, err := collection.DeleteMany(context.TODO(), bson.M{"userId": userId, "date": bson.M{"$gte": from, "$lte": to}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+func (repository *MealPlanRepository) CountEntriesByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error) {
+	if len(recipeIds) == 0 {
+		return 0, nil
+	}
+	collection := repository.db.GetClient().Database("Burned").Collection("MealPlan")
+	return collection.CountDocuments(ctx, bson.M{"recipeId": bson.M{"$in": recipeIds}})
+}
+
+func (repository *MealPlanRepository) DeleteEntriesByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error) {
+	if len(recipeIds) == 0 {
+		return 0, nil
+	}
+	collection := repository.db.GetClient().Database("Burned").Collection("MealPlan")
+	result, err := collection.DeleteMany(ctx, bson.M{"recipeId": bson.M{"$in": recipeIds}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+func (repository *MealPlanRepository) CountEntriesByUser(ctx context.Context, userId primitive.ObjectID, ownRecipes []primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("MealPlan")
+	return collection.CountDocuments(ctx, withoutRecipes(bson.M{"userId": userId}, ownRecipes))
+}
+
+func (repository *MealPlanRepository) DeleteEntriesByUser(ctx context.Context, userId primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("MealPlan")
+	result, err := collection.DeleteMany(ctx, bson.M{"userId": userId})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
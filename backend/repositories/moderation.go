@@ -0,0 +1,381 @@
+package repositories
+
+import (
+	"burned/backend/database"
+	"burned/backend/models"
+	"burned/backend/pagination"
+	"burned/backend/screening"
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// notHidden deja afuera lo ocultado por moderación en los listados públicos
+var notHidden = bson.M{"$ne": true}
+
+type ModerationRepositoryInterface interface {
+	EnsureIndexes() error
+	CreateReport(report models.Report) (models.Report, error)
+	SetReportCase(reportId primitive.ObjectID, caseId primitive.ObjectID) error
+	GetReportsByCase(caseId primitive.ObjectID) ([]models.Report, error)
+	AddCaseReport(report models.Report, authorId primitive.ObjectID) (models.ModerationCase, error)
+	AddCaseFindings(moderationCase models.ModerationCase) error
+	RecentPosts(kind string, authorId primitive.ObjectID, exclude primitive.ObjectID, since time.Time) ([]screening.Post, error)
+	GetCaseById(id primitive.ObjectID) (models.ModerationCase, error)
+	GetCasesPaged(status string, page pagination.Request) (pagination.Page[models.ModerationCase], error)
+	SetCaseAutoHidden(id primitive.ObjectID) error
+	ResolveCase(id primitive.ObjectID, action string, moderatorId primitive.ObjectID, at time.Time) error
+	ReopenCase(id primitive.ObjectID) error
+	CreateDecision(decision models.ModerationDecision) error
+	GetDecisionsByCase(caseId primitive.ObjectID) ([]models.ModerationDecision, error)
+	GetDecisionsPaged(page pagination.Request) (pagination.Page[models.ModerationDecision], error)
+	CreateWarning(warning models.UserWarning) error
+	GetWarningsByUser(userId primitive.ObjectID) ([]models.UserWarning, error)
+	CountReportsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error)
+	DeleteReportsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error)
+	CountReportsByUser(ctx context.Context, userId primitive.ObjectID, ownRecipes []primitive.ObjectID) (int64, error)
+	DeleteReportsByUser(ctx context.Context, userId primitive.ObjectID) (int64, error)
+	CountWarningsByUser(ctx context.Context, userId primitive.ObjectID, ownRecipes []primitive.ObjectID) (int64, error)
+	DeleteWarningsByUser(ctx context.Context, userId primitive.ObjectID) (int64, error)
+}
+
+// caseSorts son los órdenes de la cola; por defecto los más denunciados primero
+var caseSorts = map[string]pagination.Sort{
+	"reports": {Name: "reports", Field: "reportCount", Desc: true},
+	"newest":  {Name: "newest", Field: "lastReportAt", Desc: true},
+	"oldest":  {Name: "oldest", Field: "createdAt"},
+}
+
+type ModerationRepository struct {
+	db database.DB
+}
+
+func NewModerationRepository(db database.DB) *ModerationRepository {
+	return &ModerationRepository{db: db}
+}
+
+// EnsureIndexes crea el índice único de denuncias por usuario y contenido, y
+// el que garantiza un solo caso abierto por contenido.
+func (repository *ModerationRepository) EnsureIndexes() error {
+	burned := repository.db.GetClient().Database("Burned")
+	_, err := burned.Collection("Report").Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "targetType", Value: 1}, {Key: "targetId", Value: 1}, {Key: "reporterId", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "caseId", Value: 1}}},
+		{Keys: bson.D{{Key: "recipeId", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = burned.Collection("ModerationCase").Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "targetType", Value: 1}, {Key: "targetId", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"status": models.CaseOpen}),
+		},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "reportCount", Value: -1}}},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = burned.Collection("ModerationDecision").Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{{Key: "caseId", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = burned.Collection("UserWarning").Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: -1}},
+	})
+	return err
+}
+
+func (repository *ModerationRepository) CreateReport(report models.Report) (models.Report, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Report")
+	result, err := collection.InsertOne(context.TODO(), report)
+	if mongo.IsDuplicateKeyError(err) {
+		return models.Report{}, errors.New("content already reported")
+	}
+	if err != nil {
+		return models.Report{}, err
+	}
+	report.ID = result.InsertedID.(primitive.ObjectID)
+	return report, nil
+}
+
+func (repository *ModerationRepository) SetReportCase(reportId primitive.ObjectID, caseId primitive.ObjectID) error {
+	collection := repository.db.GetClient().Database("Burned").Collection("Report")
+	_, err := collection.UpdateOne(context.TODO(), bson.M{"_id": reportId}, bson.M{"$set": bson.M{"caseId": caseId}})
+	return err
+}
+
+func (repository *ModerationRepository) GetReportsByCase(caseId primitive.ObjectID) ([]models.Report, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Report")
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cursor, err := collection.Find(context.TODO(), bson.M{"caseId": caseId}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	reports := []models.Report{}
+	if err = cursor.All(context.TODO(), &reports); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// AddCaseReport suma la denuncia al caso abierto del contenido, o abre uno
+// nuevo si no había. Devuelve el caso actualizado.
+func (repository *ModerationRepository) AddCaseReport(report models.Report, authorId primitive.ObjectID) (models.ModerationCase, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("ModerationCase")
+	filter := bson.M{"targetType": report.TargetType, "targetId": report.TargetID, "status": models.CaseOpen}
+	update := bson.M{
+		"$inc":      bson.M{"reportCount": 1},
+		"$addToSet": bson.M{"reasons": report.Reason},
+		"$set":      bson.M{"lastReportAt": report.CreatedAt},
+		"$setOnInsert": bson.M{
+			"recipeId":  report.RecipeID,
+			"authorId":  authorId,
+			"createdAt": report.CreatedAt,
+		},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var moderationCase models.ModerationCase
+	err := collection.FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&moderationCase)
+	return moderationCase, err
+}
+
+// AddCaseFindings suma lo que encontró la moderación automática al caso
+// abierto del contenido, o abre uno. No cuenta como denuncia.
+func (repository *ModerationRepository) AddCaseFindings(moderationCase models.ModerationCase) error {
+	collection := repository.db.GetClient().Database("Burned").Collection("ModerationCase")
+	filter := bson.M{"targetType": moderationCase.TargetType, "targetId": moderationCase.TargetID, "status": models.CaseOpen}
+	set := bson.M{"lastReportAt": moderationCase.LastReportAt}
+	if moderationCase.AutoHidden {
+		set["autoHidden"] = true
+	}
+	update := bson.M{
+		"$addToSet": bson.M{
+			"reasons":  models.ReasonAutomatic,
+			"findings": bson.M{"$each": moderationCase.Findings},
+		},
+		"$set": set,
+		"$setOnInsert": bson.M{
+			"recipeId":    moderationCase.RecipeID,
+			"authorId":    moderationCase.AuthorID,
+			"reportCount": 0,
+			"createdAt":   moderationCase.LastReportAt,
+		},
+	}
+	_, err := collection.UpdateOne(context.TODO(), filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// recentPostsLimit acota cuánto historial se lee por publicación.
+const recentPostsLimit = 200
+
+// RecentPosts devuelve lo que publicó el autor desde since, para detectar
+// textos repetidos. En una receta se compara título y descripción.
+func (repository *ModerationRepository) RecentPosts(kind string, authorId primitive.ObjectID, exclude primitive.ObjectID, since time.Time) ([]screening.Post, error) {
+	burned := repository.db.GetClient().Database("Burned")
+	filter := bson.M{"userId": authorId, "createdAt": bson.M{"$gte": since}}
+	if !exclude.IsZero() {
+		filter["_id"] = bson.M{"$ne": exclude}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}).SetLimit(recentPostsLimit)
+
+	collection := burned.Collection("Comment")
+	opts.SetProjection(bson.M{"text": 1, "createdAt": 1})
+	if kind == models.TargetRecipe {
+		collection = burned.Collection("Recipe")
+		opts.SetProjection(bson.M{"title": 1, "description": 1, "createdAt": 1})
+	}
+	cursor, err := collection.Find(context.TODO(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	posts := []screening.Post{}
+	for cursor.Next(context.TODO()) {
+		var post struct {
+			Text        string    `bson:"text"`
+			Title       string    `bson:"title"`
+			Description string    `bson:"description"`
+			CreatedAt   time.Time `bson:"createdAt"`
+		}
+		if err := cursor.Decode(&post); err != nil {
+			return nil, err
+		}
+		body := post.Text
+		if kind == models.TargetRecipe {
+			body = post.Title + "\n" + post.Description
+		}
+		posts = append(posts, screening.Post{Body: body, CreatedAt: post.CreatedAt})
+	}
+	return posts, cursor.Err()
+}
+
+func (repository *ModerationRepository) GetCaseById(id primitive.ObjectID) (models.ModerationCase, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("ModerationCase")
+	var moderationCase models.ModerationCase
+	err := collection.FindOne(context.TODO(), bson.M{"_id": id}).Decode(&moderationCase)
+	return moderationCase, err
+}
+
+func (repository *ModerationRepository) GetCasesPaged(status string, page pagination.Request) (pagination.Page[models.ModerationCase], error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("ModerationCase")
+	if page.Sort == "" {
+		page.Sort = "reports"
+	}
+	sort, ok := caseSorts[page.Sort]
+	if !ok {
+		return pagination.Page[models.ModerationCase]{}, pagination.ErrInvalidSort
+	}
+	return pagination.Find[models.ModerationCase](context.TODO(), collection, bson.M{"status": status}, sort, page)
+}
+
+func (repository *ModerationRepository) SetCaseAutoHidden(id primitive.ObjectID) error {
+	collection := repository.db.GetClient().Database("Burned").Collection("ModerationCase")
+	_, err := collection.UpdateOne(context.TODO(), bson.M{"_id": id}, bson.M{"$set": bson.M{"autoHidden": true}})
+	return err
+}
+
+// ResolveCase cierra un caso abierto. Si otro moderador lo cerró antes
+// devuelve error, para que una misma denuncia no se resuelva dos veces.
+func (repository *ModerationRepository) ResolveCase(id primitive.ObjectID, action string, moderatorId primitive.ObjectID, at time.Time) error {
+	collection := repository.db.GetClient().Database("Burned").Collection("ModerationCase")
+	update := bson.M{"$set": bson.M{
+		"status":     models.CaseResolved,
+		"action":     action,
+		"resolvedBy": moderatorId,
+		"resolvedAt": at,
+	}}
+	result, err := collection.UpdateOne(context.TODO(), bson.M{"_id": id, "status": models.CaseOpen}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("case already resolved")
+	}
+	return nil
+}
+
+// ReopenCase deshace ResolveCase cuando la acción no se pudo aplicar.
+func (repository *ModerationRepository) ReopenCase(id primitive.ObjectID) error {
+	collection := repository.db.GetClient().Database("Burned").Collection("ModerationCase")
+	update := bson.M{
+		"$set":   bson.M{"status": models.CaseOpen},
+		"$unset": bson.M{"action": "", "resolvedBy": "", "resolvedAt": ""},
+	}
+	_, err := collection.UpdateOne(context.TODO(), bson.M{"_id": id}, update)
+	return err
+}
+
+func (repository *ModerationRepository) CreateDecision(decision models.ModerationDecision) error {
+	collection := repository.db.GetClient().Database("Burned").Collection("ModerationDecision")
+	_, err := collection.InsertOne(context.TODO(), decision)
+	return err
+}
+
+func (repository *ModerationRepository) GetDecisionsByCase(caseId primitive.ObjectID) ([]models.ModerationDecision, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("ModerationDecision")
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cursor, err := collection.Find(context.TODO(), bson.M{"caseId": caseId}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	decisions := []models.ModerationDecision{}
+	if err = cursor.All(context.TODO(), &decisions); err != nil {
+		return nil, err
+	}
+	return decisions, nil
+}
+
+// GetDecisionsPaged es el registro de moderación, lo más nuevo primero.
+func (repository *ModerationRepository) GetDecisionsPaged(page pagination.Request) (pagination.Page[models.ModerationDecision], error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("ModerationDecision")
+	if page.Sort != "" && page.Sort != "newest" {
+		return pagination.Page[models.ModerationDecision]{}, pagination.ErrInvalidSort
+	}
+	sort := pagination.Sort{Name: "newest", Field: "createdAt", Desc: true}
+	return pagination.Find[models.ModerationDecision](context.TODO(), collection, bson.M{}, sort, page)
+}
+
+func (repository *ModerationRepository) CreateWarning(warning models.UserWarning) error {
+	collection := repository.db.GetClient().Database("Burned").Collection("UserWarning")
+	_, err := collection.InsertOne(context.TODO(), warning)
+	return err
+}
+
+func (repository *ModerationRepository) GetWarningsByUser(userId primitive.ObjectID) ([]models.UserWarning, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("UserWarning")
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := collection.Find(context.TODO(), bson.M{"userId": userId}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	warnings := []models.UserWarning{}
+	if err = cursor.All(context.TODO(), &warnings); err != nil {
+		return nil, err
+	}
+	return warnings, nil
+}
+
+// CountReportsByRecipes cuenta las denuncias a las recetas y a sus
+// comentarios. Los casos y decisiones se conservan como registro.
+func (repository *ModerationRepository) CountReportsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error) {
+	if len(recipeIds) == 0 {
+		return 0, nil
+	}
+	collection := repository.db.GetClient().Database("Burned").Collection("Report")
+	return collection.CountDocuments(ctx, bson.M{"recipeId": bson.M{"$in": recipeIds}})
+}
+
+func (repository *ModerationRepository) DeleteReportsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error) {
+	if len(recipeIds) == 0 {
+		return 0, nil
+	}
+	collection := repository.db.GetClient().Database("Burned").Collection("Report")
+	result, err := collection.DeleteMany(ctx, bson.M{"recipeId": bson.M{"$in": recipeIds}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+func (repository *ModerationRepository) CountReportsByUser(ctx context.Context, userId primitive.ObjectID, ownRecipes []primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Report")
+	return collection.CountDocuments(ctx, withoutRecipes(bson.M{"reporterId": userId}, ownRecipes))
+}
+
+func (repository *ModerationRepository) DeleteReportsByUser(ctx context.Context, userId primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Report")
+	result, err := collection.DeleteMany(ctx, bson.M{"reporterId": userId})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+func (repository *ModerationRepository) CountWarningsByUser(ctx context.Context, userId primitive.ObjectID, ownRecipes []primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("UserWarning")
+	return collection.CountDocuments(ctx, bson.M{"userId": userId})
+}
+
+func (repository *ModerationRepository) DeleteWarningsByUser(ctx context.Context, userId primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("UserWarning")
+	result, err := collection.DeleteMany(ctx, bson.M{"userId": userId})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,145 @@
+package repositories
+
+import (
+	"burned/backend/database"
+	"burned/backend/models"
+	"burned/backend/pagination"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// unmatchedSort lista primero los nombres sin resolver que más aparecen
+var unmatchedSort = pagination.Sort{Name: "occurrences", Field: "occurrences", Desc: true}
+
+type NutritionRepositoryInterface interface {
+	EnsureIndexes() error
+	RecordUnmatched(unmatched models.UnmatchedIngredient) error
+	GetUnmatchedPaged(page pagination.Request) (pagination.Page[models.UnmatchedIngredient], error)
+	GetUnmatchedById(id primitive.ObjectID) (models.UnmatchedIngredient, error)
+	DeleteUnmatched(id primitive.ObjectID) (int64, error)
+	DeleteUnmatchedByKey(key string) (int64, error)
+	SaveAlias(alias models.NutritionAlias) error
+	GetAliases() ([]models.NutritionAlias, error)
+}
+
+type NutritionRepository struct {
+	db database.DB
+}
+
+func NewNutritionRepository(db database.DB) *NutritionRepository {
+	return &NutritionRepository{db: db}
+}
+
+// EnsureIndexes crea el índice único por nombre normalizado (cada nombre sin
+// resolver se registra una vez) y el de orden del listado.
+func (repository *NutritionRepository) EnsureIndexes() error {
+	collection := repository.db.GetClient().Database("Burned").Collection("UnmatchedIngredient")
+	_, err := collection.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "key", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "occurrences", Value: -1}, {Key: "_id", Value: -1}}},
+	})
+	return err
+}
+
+// RecordUnmatched suma una aparición del nombre, o lo registra si es nuevo.
+func (repository *NutritionRepository) RecordUnmatched(unmatched models.UnmatchedIngredient) error {
+	collection := repository.db.GetClient().Database("Burned").Collection("UnmatchedIngredient")
+	update := bson.M{
+		"$set": bson.M{
+			"name":         unmatched.Name,
+			"reason":       unmatched.Reason,
+			"unit":         unmatched.Unit,
+			"lastRecipeId": unmatched.LastRecipeID,
+			"lastSeen":     unmatched.LastSeen,
+		},
+		"$inc":         bson.M{"occurrences": 1},
+		"$setOnInsert": bson.M{"firstSeen": unmatched.LastSeen},
+	}
+	_, err := collection.UpdateOne(context.TODO(), bson.M{"key": unmatched.Key}, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (repository *NutritionRepository) GetUnmatchedPaged(page pagination.Request) (pagination.Page[models.UnmatchedIngredient], error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("UnmatchedIngredient")
+	return pagination.Find[models.UnmatchedIngredient](context.TODO(), collection, bson.M{}, unmatchedSort, page)
+}
+
+func (repository *NutritionRepository) GetUnmatchedById(id primitive.ObjectID) (models.UnmatchedIngredient, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("UnmatchedIngredient")
+	var unmatched models.UnmatchedIngredient
+	err := collection.FindOne(context.TODO(), bson.M{"_id": id}).Decode(&unmatched)
+	return unmatched, err
+}
+
+func (repository *NutritionRepository) DeleteUnmatched(id primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("UnmatchedIngredient")
+	result, err := collection.DeleteOne(context.TODO(), bson.M{"_id": id})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+func (repository *NutritionRepository) DeleteUnmatchedByKey(key string) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("UnmatchedIngredient")
+	result, err := collection.DeleteOne(context.TODO(), bson.M{"key": key})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// SaveAlias crea el alias o lo reemplaza si el nombre ya apuntaba a otro alimento.
+func (repository *NutritionRepository) SaveAlias(alias models.NutritionAlias) error {
+	collection := repository.db.GetClient().Database("Burned").Collection("NutritionAlias")
+	_, err := collection.ReplaceOne(context.TODO(), bson.M{"_id": alias.Key}, alias, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (repository *NutritionRepository) GetAliases() ([]models.NutritionAlias, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("NutritionAlias")
+	cursor, err := collection.Find(context.TODO(), bson.M{}, options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	aliases := []models.NutritionAlias{}
+	if err := cursor.All(context.TODO(), &aliases); err != nil {
+		return nil, err
+	}
+	return aliases, nil
+}
+
+// maxNutritionRecalc acota las recetas que se recalculan al cargar un alias
+const maxNutritionRecalc = 500
+
+// GetRecipesByIngredientTerm devuelve las recetas con algún ingrediente que
+// contiene la frase (ver shopping.Terms), para recalcularlas.
+func (repository *RecipeRepository) GetRecipesByIngredientTerm(term string) ([]models.Recipe, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Recipe")
+	opts := options.Find().SetLimit(maxNutritionRecalc)
+	cursor, err := collection.Find(context.TODO(), bson.M{"ingredients.terms": term}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	recipes := []models.Recipe{}
+	if err := cursor.All(context.TODO(), &recipes); err != nil {
+		return nil, err
+	}
+	return recipes, nil
+}
+
+// SetNutrition guarda la información nutricional sin tocar updatedAt: no es
+// una edición de la receta.
+func (repository *RecipeRepository) SetNutrition(id primitive.ObjectID, nutrition models.RecipeNutrition) error {
+	collection := repository.db.GetClient().Database("Burned").Collection("Recipe")
+	_, err := collection.UpdateOne(context.TODO(), bson.M{"_id": id}, bson.M{"$set": bson.M{"nutrition": nutrition}})
+	return err
+}
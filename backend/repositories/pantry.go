@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"burned/backend/database"
+	"burned/backend/models"
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxPantryItems acota lo que se lee de la despensa de un usuario
+const maxPantryItems = 500
+
+type PantryRepositoryInterface interface {
+	EnsureIndexes() error
+	CreateItem(item models.PantryItem) (models.PantryItem, error)
+	UpdateItem(item models.PantryItem) (int64, error)
+	DeleteItem(id primitive.ObjectID, userId primitive.ObjectID) (int64, error)
+	GetItemById(id primitive.ObjectID) (models.PantryItem, error)
+	GetItemsByUser(userId primitive.ObjectID) ([]models.PantryItem, error)
+	CountItemsByUser(ctx context.Context, userId primitive.ObjectID, ownRecipes []primitive.ObjectID) (int64, error)
+	DeleteItemsByUser(ctx context.Context, userId primitive.ObjectID) (int64, error)
+}
+
+type PantryRepository struct {
+	db database.DB
+}
+
+func NewPantryRepository(db database.DB) *PantryRepository {
+	return &PantryRepository{db: db}
+}
+
+// EnsureIndexes crea el índice único por usuario y nombre normalizado: cada
+// ingrediente aparece una sola vez en la despensa.
+func (repository *PantryRepository) EnsureIndexes() error {
+	collection := repository.db.GetClient().Database("Burned").Collection("Pantry")
+	_, err := collection.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "key", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+func (repository *PantryRepository) CreateItem(item models.PantryItem) (models.PantryItem, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Pantry")
+	result, err := collection.InsertOne(context.TODO(), item)
+	if mongo.IsDuplicateKeyError(err) {
+		return models.PantryItem{}, errors.New("pantry item already exists")
+	}
+	if err != nil {
+		return models.PantryItem{}, err
+	}
+	item.ID = result.InsertedID.(primitive.ObjectID)
+	return item, nil
+}
+
+func (repository *PantryRepository) UpdateItem(item models.PantryItem) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Pantry")
+	update := bson.M{"$set": bson.M{
+		"name":      item.Name,
+		"key":       item.Key,
+		"quantity":  item.Quantity,
+		"unit":      item.Unit,
+		"expiresAt": item.ExpiresAt,
+		"updatedAt": item.UpdatedAt,
+	}}
+	result, err := collection.UpdateOne(context.TODO(), bson.M{"_id": item.ID, "userId": item.UserID}, update)
+	if mongo.IsDuplicateKeyError(err) {
+		return 0, errors.New("pantry item already exists")
+	}
+	if err != nil {
+		return 0, err
+	}
+	return result.MatchedCount, nil
+}
+
+func (repository *PantryRepository) DeleteItem(id primitive.ObjectID, userId primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Pantry")
+	result, err := collection.DeleteOne(context.TODO(), bson.M{"_id": id, "userId": userId})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+func (repository *PantryRepository) GetItemById(id primitive.ObjectID) (models.PantryItem, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Pantry")
+	var item models.PantryItem
+	err := collection.FindOne(context.TODO(), bson.M{"_id": id}).Decode(&item)
+	return item, err
+}
+
+// GetItemsByUser devuelve la despensa ordenada por nombre.
+func (repository *PantryRepository) GetItemsByUser(userId primitive.ObjectID) ([]models.PantryItem, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Pantry")
+	opts := options.Find().SetSort(bson.D{{Key: "key", Value: 1}}).SetLimit(maxPantryItems)
+	cursor, err := collection.Find(context.TODO(), bson.M{"userId": userId}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	items := []models.PantryItem{}
+	if err := cursor.All(context.TODO(), &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (repository *PantryRepository) CountItemsByUser(ctx context.Context, userId primitive.ObjectID, ownRecipes []primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Pantry")
+	return collection.CountDocuments(ctx, bson.M{"userId": userId})
+}
+
+func (repository *PantryRepository) DeleteItemsByUser(ctx context.Context, userId primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Pantry")
+	result, err := collection.DeleteMany(ctx, bson.M{"userId": userId})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"burned/backend/dtos"
+	"burned/backend/models"
+	"burned/backend/pagination"
+	"burned/backend/search"
+	"burned/backend/units"
+	"context"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// expiringBoost es lo que suma al puntaje cada ingrediente por vencer que la
+// receta aprovecha: usar algo que vence pesa como tener un 25% más de la receta.
+const expiringBoost = 0.25
+
+// pantrySort ordena por pantryScore, el campo que calcula GetRecipesByPantry.
+var pantrySort = pagination.Sort{Name: "pantry", Field: "pantryScore", Desc: true}
+
+// EnsurePantryIndex indexa las frases de los ingredientes para la búsqueda por despensa.
+func (repository *RecipeRepository) EnsurePantryIndex() error {
+	collection := repository.db.GetClient().Database("Burned").Collection("Recipe")
+	_, err := collection.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{{Key: "ingredients.terms", Value: 1}},
+	})
+	return err
+}
+
+// GetRecipesByPantry busca recetas públicas con al menos un ingrediente de
+// have (claves de shopping.Key) y las ordena por la proporción de
+// ingredientes necesarios que ya se tienen, más expiringBoost por cada uno
+// de expiring que usan. Opcionales y "a gusto" no cuentan como necesarios.
+// Con maxMissing >= 0 se descartan las recetas a las que les faltan más.
+// Admite los mismos filtros que GetRecipesPaged y, en ?sort=, también sus órdenes.
+func (repository *RecipeRepository) GetRecipesByPantry(filters dtos.RecipeSearchRequest, have []string, expiring []string, maxMissing int, page pagination.Request) (pagination.Page[models.Recipe], error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Recipe")
+
+	sort := pantrySort
+	if page.Sort != "" && page.Sort != pantrySort.Name {
+		var err error
+		if sort, err = recipeSort(page.Sort, ""); err != nil {
+			return pagination.Page[models.Recipe]{}, err
+		}
+	}
+
+	match := recipeSearchFilters(filters)
+	query := search.Parse(strings.Join([]string{filters.Query, filters.Title, filters.Description}, " "))
+	if !query.IsEmpty() {
+		match["$text"] = search.TextFilter(query)["$text"]
+	}
+	match["ingredients.terms"] = bson.M{"$in": have}
+
+	// un ingrediente está en la despensa si alguna de sus frases coincide
+	inList := func(list []string) bson.M {
+		return bson.M{"$gt": bson.A{
+			bson.M{"$size": bson.M{"$setIntersection": bson.A{bson.M{"$ifNull": bson.A{"$$this.terms", bson.A{}}}, list}}},
+			0,
+		}}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$addFields", Value: bson.M{
+			"pantryNeeded": bson.M{"$filter": bson.M{
+				"input": "$ingredients",
+				"cond": bson.M{"$and": bson.A{
+					bson.M{"$ne": bson.A{"$$this.optional", true}},
+					bson.M{"$ne": bson.A{"$$this.unit", units.CodeToTaste}},
+				}},
+			}},
+		}}},
+		{{Key: "$addFields", Value: bson.M{
+			"pantryMatched":  bson.M{"$size": bson.M{"$filter": bson.M{"input": "$pantryNeeded", "cond": inList(have)}}},
+			"pantryExpiring": bson.M{"$size": bson.M{"$filter": bson.M{"input": "$ingredients", "cond": inList(expiring)}}},
+			"pantryNeeded":   bson.M{"$size": "$pantryNeeded"},
+		}}},
+		{{Key: "$addFields", Value: bson.M{
+			"pantryScore": bson.M{"$add": bson.A{
+				bson.M{"$cond": bson.A{
+					bson.M{"$eq": bson.A{"$pantryNeeded", 0}},
+					0,
+					bson.M{"$divide": bson.A{"$pantryMatched", "$pantryNeeded"}},
+				}},
+				bson.M{"$multiply": bson.A{"$pantryExpiring", expiringBoost}},
+			}},
+		}}},
+	}
+	if maxMissing >= 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"$expr": bson.M{
+			"$lte": bson.A{bson.M{"$subtract": bson.A{"$pantryNeeded", "$pantryMatched"}}, maxMissing},
+		}}}})
+	}
+
+	return pagination.Aggregate[models.Recipe](context.TODO(), collection, pipeline, sort, page)
+}
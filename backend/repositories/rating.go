@@ -18,6 +18,10 @@ type RatingRepositoryInterface interface {
 	UpsertRating(model models.Rating) (float64, error)
 	GetRatingByUserAndRecipe(model models.Rating) (models.Rating, error)
 	GetRatingByRecipe(recipeId primitive.ObjectID) (dtos.Avg, error)
+	CountRatingsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error)
+	DeleteRatingsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error)
+	CountRatingsByUser(ctx context.Context, userId primitive.ObjectID, excludeRecipes []primitive.ObjectID) (int64, error)
+	DeleteRatingsByUser(ctx context.Context, userId primitive.ObjectID) (int64, error)
 }
 
 type RatingRepository struct {
@@ -54,12 +58,12 @@ func (repository *RatingRepository) UpsertRating(model models.Rating) (float64,
 	if err != nil {
 		return 0, fmt.Errorf("error guardando rating: %v", err)
 	}
-	matchStage := bson.D{{"$match", bson.D{{"recipeId", model.RecipeID}}}}
+	matchStage := bson.D{{Key: "$match", Value: bson.D{{Key: "recipeId", Value: model.RecipeID}}}}
 
 	groupStage := bson.D{
-		{"$group", bson.D{
-			{"_id", "$recipeId"},
-			{"averageRating", bson.D{{"$avg", "$stars"}}},
+		{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$recipeId"},
+			{Key: "averageRating", Value: bson.D{{Key: "$avg", Value: "$stars"}}},
 		}},
 	}
 
@@ -121,12 +125,12 @@ func (repository *RatingRepository) GetRatingByRecipe(recipeId primitive.ObjectI
 
 	pipeline := mongo.Pipeline{
 		{
-			{"$match", bson.M{
+			{Key: "$match", Value: bson.M{
 				"recipeId": recipeId,
 			}},
 		},
 		{
-			{"$group", bson.M{
+			{Key: "$group", Value: bson.M{
 				"_id":   "$recipeId",
 				"avg":   bson.M{"$avg": "$stars"},
 				"count": bson.M{"$sum": 1},
@@ -150,3 +154,81 @@ func (repository *RatingRepository) GetRatingByRecipe(recipeId primitive.ObjectI
 	// No hay ratings
 	return res, nil
 }
+
+func (repository *RatingRepository) CountRatingsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error) {
+	if len(recipeIds) == 0 {
+		return 0, nil
+	}
+	collection := repository.db.GetClient().Database("Burned").Collection("Rating")
+	return collection.CountDocuments(ctx, bson.M{"recipeId": bson.M{"$in": recipeIds}})
+}
+
+func (repository *RatingRepository) DeleteRatingsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error) {
+	if len(recipeIds) == 0 {
+		return 0, nil
+	}
+	collection := repository.db.GetClient().Database("Burned").Collection("Rating")
+	result, err := collection.DeleteMany(ctx, bson.M{"recipeId": bson.M{"$in": recipeIds}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+func (repository *RatingRepository) CountRatingsByUser(ctx context.Context, userId primitive.ObjectID, excludeRecipes []primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Rating")
+	return collection.CountDocuments(ctx, withoutRecipes(bson.M{"userId": userId}, excludeRecipes))
+}
+
+// DeleteRatingsByUser borra los votos del usuario y recalcula el promedio de
+// cada receta que había votado.
+func (repository *RatingRepository) DeleteRatingsByUser(ctx context.Context, userId primitive.ObjectID) (int64, error) {
+	ratingCollection := repository.db.GetClient().Database("Burned").Collection("Rating")
+	recipeCollection := repository.db.GetClient().Database("Burned").Collection("Recipe")
+
+	rated, err := ratingCollection.Distinct(ctx, "recipeId", bson.M{"userId": userId})
+	if err != nil {
+		return 0, err
+	}
+	result, err := ratingCollection.DeleteMany(ctx, bson.M{"userId": userId})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, value := range rated {
+		recipeId, ok := value.(primitive.ObjectID)
+		if !ok {
+			continue
+		}
+		avg, err := repository.averageByRecipe(ctx, recipeId)
+		if err != nil {
+			return 0, err
+		}
+		_, err = recipeCollection.UpdateOne(ctx, bson.M{"_id": recipeId}, bson.M{"$set": bson.M{"averageRating": avg}})
+		if err != nil {
+			return 0, fmt.Errorf("error actualizando promedio en receta: %v", err)
+		}
+	}
+	return result.DeletedCount, nil
+}
+
+func (repository *RatingRepository) averageByRecipe(ctx context.Context, recipeId primitive.ObjectID) (float64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Rating")
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"recipeId": recipeId}}},
+		{{Key: "$group", Value: bson.M{"_id": "$recipeId", "avg": bson.M{"$avg": "$stars"}}}},
+	}
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var res dtos.Avg
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&res); err != nil {
+			return 0, err
+		}
+	}
+	return res.Avg, cursor.Err()
+}
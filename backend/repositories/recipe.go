@@ -4,7 +4,11 @@ import (
 	"burned/backend/database"
 	"burned/backend/dtos"
 	"burned/backend/models"
+	"burned/backend/pagination"
+	"burned/backend/search"
 	"context"
+	"regexp"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -22,6 +26,53 @@ type RecipeRepositoryInterface interface {
 	GetRecipesByUser(id primitive.ObjectID) ([]models.Recipe, error)
 	GetAll() ([]models.Recipe, error)
 	GetTopRecipesLimit(limit int) ([]models.Recipe, error)
+	EnsureSearchIndex() error
+	GetRecipesPaged(filters dtos.RecipeSearchRequest, page pagination.Request) (pagination.Page[models.Recipe], error)
+	GetRecipesByUserPaged(id primitive.ObjectID, page pagination.Request) (pagination.Page[models.Recipe], error)
+	GetAllPaged(page pagination.Request) (pagination.Page[models.Recipe], error)
+	GetRecipeIdsByUser(ctx context.Context, userId primitive.ObjectID) ([]primitive.ObjectID, error)
+	DeleteRecipesByIds(ctx context.Context, ids []primitive.ObjectID) (int64, error)
+	GetRecipesByIds(ids []primitive.ObjectID) ([]models.Recipe, error)
+	EnsureForkIndex() error
+	CountForks(recipeId primitive.ObjectID) (int64, error)
+	GetForksPaged(recipeId primitive.ObjectID, descendants bool, page pagination.Request) (pagination.Page[models.Recipe], error)
+	CountLineageByUser(ctx context.Context, userId primitive.ObjectID, ownRecipes []primitive.ObjectID) (int64, error)
+	AnonymizeLineageByUser(ctx context.Context, userId primitive.ObjectID) (int64, error)
+	EnsurePantryIndex() error
+	GetRecipesByPantry(filters dtos.RecipeSearchRequest, have []string, expiring []string, maxMissing int, page pagination.Request) (pagination.Page[models.Recipe], error)
+	GetRecipesByIngredientTerm(term string) ([]models.Recipe, error)
+	SetNutrition(id primitive.ObjectID, nutrition models.RecipeNutrition) error
+	SetHidden(id primitive.ObjectID, hidden bool) error
+}
+
+// recipeSorts son los órdenes que aceptan los listados de recetas en ?sort=
+var recipeSorts = map[string]pagination.Sort{
+	"newest": {Name: "newest", Field: "createdAt", Desc: true},
+	"rating": {Name: "rating", Field: "averageRating", Desc: true},
+	"time":   {Name: "time", Field: "totalTime"},
+	"saved":  {Name: "saved", Field: "savedCount", Desc: true},
+}
+
+// withoutRecipes agrega al filtro la condición de no pertenecer a las recetas
+// dadas. Se usa al borrar un usuario para no contar dos veces lo que ya se
+// elimina junto con sus recetas.
+func withoutRecipes(filter bson.M, recipeIds []primitive.ObjectID) bson.M {
+	if len(recipeIds) > 0 {
+		filter["recipeId"] = bson.M{"$nin": recipeIds}
+	}
+	return filter
+}
+
+// recipeSort resuelve el orden pedido; si viene vacío usa fallback.
+func recipeSort(name string, fallback string) (pagination.Sort, error) {
+	if name == "" {
+		name = fallback
+	}
+	sort, ok := recipeSorts[name]
+	if !ok {
+		return pagination.Sort{}, pagination.ErrInvalidSort
+	}
+	return sort, nil
 }
 
 type RecipeRepository struct {
@@ -49,6 +100,7 @@ func (repository *RecipeRepository) UpdateRecipe(recipe models.Recipe) (*mongo.U
 		"description":    recipe.Description,
 		"visibility":     recipe.Visibility,
 		"totalTime":      recipe.TotalTime,
+		"servings":       recipe.Servings,
 		"step":           recipe.Step,
 		"dificultyLevel": recipe.DificultyLevel,
 		"tags":           recipe.Tags,
@@ -56,6 +108,10 @@ func (repository *RecipeRepository) UpdateRecipe(recipe models.Recipe) (*mongo.U
 		"image":          recipe.Image,
 		"updatedAt":      recipe.UpdatedAt,
 		"averageRating":  recipe.AverageRating,
+		"allergens":      recipe.Allergens,
+		"diets":          recipe.Diets,
+		"unclassified":   recipe.Unclassified,
+		"nutrition":      recipe.Nutrition,
 	}}
 	return collection.UpdateOne(context.TODO(), filter, update)
 }
@@ -65,20 +121,53 @@ func (repository *RecipeRepository) DeleteRecipe(id primitive.ObjectID) (*mongo.
 	filter := bson.M{"_id": id}
 	return collection.DeleteOne(context.TODO(), filter)
 }
+
+// SetHidden oculta o vuelve a mostrar una receta; no toca su visibilidad.
+func (repository *RecipeRepository) SetHidden(id primitive.ObjectID, hidden bool) error {
+	collection := repository.db.GetClient().Database("Burned").Collection("Recipe")
+	_, err := collection.UpdateOne(context.TODO(), bson.M{"_id": id}, bson.M{"$set": bson.M{"hidden": hidden}})
+	return err
+}
+
 func (repository *RecipeRepository) GetRecipes(filters dtos.RecipeSearchRequest) ([]models.Recipe, error) {
 	collection := repository.db.GetClient().Database("Burned").Collection("Recipe")
 
-	filtersMap := bson.M{}
-	filtersMap["visibility"] = "public"
-	// Filtros básicos
-	if filters.Title != "" {
-		filtersMap["title"] = bson.M{"$regex": filters.Title, "$options": "i"}
+	filtersMap := recipeSearchFilters(filters)
+	query := search.Parse(strings.Join([]string{filters.Query, filters.Title, filters.Description}, " "))
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	if !query.IsEmpty() {
+		filtersMap["$text"] = search.TextFilter(query)["$text"]
+		opts = options.Find().SetProjection(search.ScoreProjection()).SetSort(search.ScoreSort())
 	}
-	if filters.Description != "" {
-		filtersMap["description"] = bson.M{"$regex": filters.Description, "$options": "i"}
+
+	cursor, err := collection.Find(context.TODO(), filtersMap, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	var recipes []models.Recipe
+	if err = cursor.All(context.Background(), &recipes); err != nil {
+		return nil, err
+	}
+
+	// $text solo encuentra palabras completas; mientras el usuario escribe
+	// ("past" en vez de "pasta") buscamos por prefijo en el título
+	if prefix, ok := query.PrefixTerm(); ok && len(recipes) == 0 {
+		return repository.getRecipesByTitlePrefix(recipeSearchFilters(filters), prefix)
 	}
+	return recipes, nil
+}
+
+// recipeSearchFilters arma los filtros estructurados (no de texto) de una búsqueda.
+// Todo valor del usuario que termina en un $regex se escapa con QuoteMeta.
+func recipeSearchFilters(filters dtos.RecipeSearchRequest) bson.M {
+	filtersMap := bson.M{}
+	filtersMap["visibility"] = "public"
+	filtersMap["hidden"] = notHidden
 	if filters.DificultyLevel != "" {
-		filtersMap["dificultyLevel"] = bson.M{"$regex": filters.DificultyLevel, "$options": "i"}
+		filtersMap["dificultyLevel"] = strings.ToLower(filters.DificultyLevel)
 	}
 	if filters.TotalTime != 0 {
 		filtersMap["totalTime"] = bson.M{"$lte": filters.TotalTime}
@@ -86,16 +175,30 @@ func (repository *RecipeRepository) GetRecipes(filters dtos.RecipeSearchRequest)
 	if len(filters.Tags) > 0 {
 		var tagConditions []bson.M
 		for _, tag := range filters.Tags {
-
 			tagConditions = append(tagConditions, bson.M{
-				"tags": bson.M{"$regex": tag, "$options": "i"},
+				"tags": bson.M{"$regex": regexp.QuoteMeta(tag), "$options": "i"},
 			})
 		}
-
 		filtersMap["$and"] = tagConditions
 	}
+	if len(filters.ExcludeAllergens) > 0 {
+		filtersMap["allergens"] = bson.M{"$nin": filters.ExcludeAllergens}
+		//con ingredientes sin clasificar no se puede asegurar que no los tenga
+		filtersMap["unclassified.0"] = bson.M{"$exists": false}
+	}
+	if len(filters.Diets) > 0 {
+		filtersMap["diets"] = bson.M{"$all": filters.Diets}
+	}
+	return filtersMap
+}
+
+func (repository *RecipeRepository) getRecipesByTitlePrefix(filtersMap bson.M, prefix string) ([]models.Recipe, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Recipe")
+
+	filtersMap["title"] = bson.M{"$regex": `(^|\s)` + regexp.QuoteMeta(prefix), "$options": "i"}
+	opts := options.Find().SetSort(bson.D{{Key: "averageRating", Value: -1}}).SetLimit(20)
 
-	cursor, err := collection.Find(context.TODO(), filtersMap)
+	cursor, err := collection.Find(context.TODO(), filtersMap, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -108,6 +211,115 @@ func (repository *RecipeRepository) GetRecipes(filters dtos.RecipeSearchRequest)
 	return recipes, nil
 }
 
+func (repository *RecipeRepository) GetRecipesPaged(filters dtos.RecipeSearchRequest, page pagination.Request) (pagination.Page[models.Recipe], error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Recipe")
+
+	filtersMap := recipeSearchFilters(filters)
+	query := search.Parse(strings.Join([]string{filters.Query, filters.Title, filters.Description}, " "))
+
+	var sort pagination.Sort
+	if !query.IsEmpty() {
+		filtersMap["$text"] = search.TextFilter(query)["$text"]
+	}
+	if !query.IsEmpty() && (page.Sort == "" || page.Sort == "relevance") {
+		sort = pagination.Sort{Name: "relevance", Relevance: true}
+	} else {
+		var err error
+		if page.Sort == "relevance" {
+			page.Sort = ""
+		}
+		if sort, err = recipeSort(page.Sort, "newest"); err != nil {
+			return pagination.Page[models.Recipe]{}, err
+		}
+	}
+
+	result, err := pagination.Find[models.Recipe](context.TODO(), collection, filtersMap, sort, page)
+	if err != nil {
+		return pagination.Page[models.Recipe]{}, err
+	}
+
+	// Mismo respaldo por prefijo que GetRecipes, solo en la primera página
+	if prefix, ok := query.PrefixTerm(); ok && len(result.Items) == 0 && page.After == "" {
+		recipes, err := repository.getRecipesByTitlePrefix(recipeSearchFilters(filters), prefix)
+		if err != nil {
+			return pagination.Page[models.Recipe]{}, err
+		}
+		if len(recipes) > pagination.Limit(page.Limit) {
+			recipes = recipes[:pagination.Limit(page.Limit)]
+		}
+		if recipes == nil {
+			recipes = []models.Recipe{}
+		}
+		return pagination.Page[models.Recipe]{Items: recipes}, nil
+	}
+	return result, nil
+}
+
+func (repository *RecipeRepository) GetRecipesByUserPaged(id primitive.ObjectID, page pagination.Request) (pagination.Page[models.Recipe], error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Recipe")
+	sort, err := recipeSort(page.Sort, "newest")
+	if err != nil {
+		return pagination.Page[models.Recipe]{}, err
+	}
+	return pagination.Find[models.Recipe](context.TODO(), collection, bson.M{"userId": id}, sort, page)
+}
+
+func (repository *RecipeRepository) GetAllPaged(page pagination.Request) (pagination.Page[models.Recipe], error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Recipe")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Por defecto, igual que GetAll, las mejor valoradas primero
+	sort, err := recipeSort(page.Sort, "rating")
+	if err != nil {
+		return pagination.Page[models.Recipe]{}, err
+	}
+	return pagination.Find[models.Recipe](ctx, collection, bson.M{"hidden": notHidden}, sort, page)
+}
+
+func (repository *RecipeRepository) GetRecipeIdsByUser(ctx context.Context, userId primitive.ObjectID) ([]primitive.ObjectID, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("Recipe")
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+
+	cursor, err := collection.Find(ctx, bson.M{"userId": userId}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var ids []primitive.ObjectID
+	for cursor.Next(ctx) {
+		var recipe struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&recipe); err != nil {
+			return nil, err
+		}
+		ids = append(ids, recipe.ID)
+	}
+	return ids, cursor.Err()
+}
+
+func (repository *RecipeRepository) DeleteRecipesByIds(ctx context.Context, ids []primitive.ObjectID) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	collection := repository.db.GetClient().Database("Burned").Collection("Recipe")
+	result, err := collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// EnsureSearchIndex crea el índice de texto de recetas si todavía no existe.
+func (repository *RecipeRepository) EnsureSearchIndex() error {
+	collection := repository.db.GetClient().Database("Burned").Collection("Recipe")
+	_, err := collection.Indexes().CreateOne(context.TODO(), search.RecipeTextIndex())
+	return err
+}
+
 func (repository *RecipeRepository) GetAll() ([]models.Recipe, error) {
 	collection := repository.db.GetClient().Database("Burned").Collection("Recipe")
 
@@ -118,7 +330,7 @@ func (repository *RecipeRepository) GetAll() ([]models.Recipe, error) {
 	// 1. Definimos las opciones de ordenamiento AQUÍ
 	// "rating": -1 significa Descendente (Mayor a menor)
 	// Si tu campo se llama diferente en Mongo (ej: "average_rating"), cámbialo aquí.
-	opts := options.Find().SetSort(bson.D{{"rating", -1}})
+	opts := options.Find().SetSort(bson.D{{Key: "rating", Value: -1}})
 
 	// 2. Pasamos las opciones al Find
 	cursor, err := collection.Find(ctx, bson.M{}, opts)
@@ -146,6 +358,25 @@ func (repository *RecipeRepository) GetRecipeById(id primitive.ObjectID) (models
 	return recipe, nil
 }
 
+// GetRecipesByIds trae varias recetas en una consulta, sin orden garantizado.
+func (repository *RecipeRepository) GetRecipesByIds(ids []primitive.ObjectID) ([]models.Recipe, error) {
+	if len(ids) == 0 {
+		return []models.Recipe{}, nil
+	}
+	collection := repository.db.GetClient().Database("Burned").Collection("Recipe")
+	cursor, err := collection.Find(context.TODO(), bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var recipes []models.Recipe
+	if err := cursor.All(context.TODO(), &recipes); err != nil {
+		return nil, err
+	}
+	return recipes, nil
+}
+
 func (repository *RecipeRepository) GetRecipesByUser(id primitive.ObjectID) ([]models.Recipe, error) {
 	collection := repository.db.GetClient().Database("Burned").Collection("Recipe")
 	filter := bson.M{"userId": id}
@@ -191,9 +422,9 @@ func (repository *RecipeRepository) GetTopRecipesLimit(limit int) ([]models.Reci
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	opts := options.Find().SetSort(bson.D{{"averageRating", -1}}).SetLimit(int64(limit))
+	opts := options.Find().SetSort(bson.D{{Key: "averageRating", Value: -1}}).SetLimit(int64(limit))
 
-	filter := bson.M{"visibility": "public"}
+	filter := bson.M{"visibility": "public", "hidden": notHidden}
 
 	cursor, err := collection.Find(ctx, filter, opts)
 	if err != nil {
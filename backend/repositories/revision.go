@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"burned/backend/database"
+	"burned/backend/models"
+	"burned/backend/pagination"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RecipeRevisionRepositoryInterface interface {
+	EnsureIndexes() error
+	CreateRevision(revision models.RecipeRevision) (models.RecipeRevision, error)
+	CountRevisionsByRecipe(recipeId primitive.ObjectID) (int64, error)
+	GetRevision(recipeId primitive.ObjectID, number int) (models.RecipeRevision, error)
+	GetRevisionsByRecipePaged(recipeId primitive.ObjectID, page pagination.Request) (pagination.Page[models.RecipeRevision], error)
+	CountRevisionsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error)
+	DeleteRevisionsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error)
+}
+
+// revisionSorts son los órdenes que aceptan los listados de revisiones
+var revisionSorts = map[string]pagination.Sort{
+	"newest": {Name: "newest", Field: "number", Desc: true},
+	"oldest": {Name: "oldest", Field: "number"},
+}
+
+// createRevisionAttempts acota los reintentos cuando dos ediciones
+// simultáneas eligen el mismo número de revisión.
+const createRevisionAttempts = 3
+
+type RecipeRevisionRepository struct {
+	db database.DB
+}
+
+func NewRecipeRevisionRepository(db database.DB) *RecipeRevisionRepository {
+	return &RecipeRevisionRepository{db: db}
+}
+
+// EnsureIndexes crea el índice único (recipeId, number) que garantiza la
+// numeración correlativa.
+func (repository *RecipeRevisionRepository) EnsureIndexes() error {
+	collection := repository.db.GetClient().Database("Burned").Collection("RecipeRevision")
+	_, err := collection.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "recipeId", Value: 1}, {Key: "number", Value: -1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// CreateRevision asigna el siguiente número de la receta y guarda la revisión.
+func (repository *RecipeRevisionRepository) CreateRevision(revision models.RecipeRevision) (models.RecipeRevision, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("RecipeRevision")
+	var err error
+	for attempt := 0; attempt < createRevisionAttempts; attempt++ {
+		var last models.RecipeRevision
+		opts := options.FindOne().SetSort(bson.D{{Key: "number", Value: -1}}).SetProjection(bson.M{"number": 1})
+		err = collection.FindOne(context.TODO(), bson.M{"recipeId": revision.RecipeID}, opts).Decode(&last)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return models.RecipeRevision{}, err
+		}
+		revision.Number = last.Number + 1
+
+		var result *mongo.InsertOneResult
+		result, err = collection.InsertOne(context.TODO(), revision)
+		if err == nil {
+			revision.ID = result.InsertedID.(primitive.ObjectID)
+			return revision, nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return models.RecipeRevision{}, err
+		}
+	}
+	return models.RecipeRevision{}, err
+}
+
+func (repository *RecipeRevisionRepository) CountRevisionsByRecipe(recipeId primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("RecipeRevision")
+	return collection.CountDocuments(context.TODO(), bson.M{"recipeId": recipeId})
+}
+
+func (repository *RecipeRevisionRepository) GetRevision(recipeId primitive.ObjectID, number int) (models.RecipeRevision, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("RecipeRevision")
+	var revision models.RecipeRevision
+	err := collection.FindOne(context.TODO(), bson.M{"recipeId": recipeId, "number": number}).Decode(&revision)
+	return revision, err
+}
+
+func (repository *RecipeRevisionRepository) GetRevisionsByRecipePaged(recipeId primitive.ObjectID, page pagination.Request) (pagination.Page[models.RecipeRevision], error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("RecipeRevision")
+	if page.Sort == "" {
+		page.Sort = "newest"
+	}
+	sort, ok := revisionSorts[page.Sort]
+	if !ok {
+		return pagination.Page[models.RecipeRevision]{}, pagination.ErrInvalidSort
+	}
+	return pagination.Find[models.RecipeRevision](context.TODO(), collection, bson.M{"recipeId": recipeId}, sort, page)
+}
+
+func (repository *RecipeRevisionRepository) CountRevisionsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error) {
+	if len(recipeIds) == 0 {
+		return 0, nil
+	}
+	collection := repository.db.GetClient().Database("Burned").Collection("RecipeRevision")
+	return collection.CountDocuments(ctx, bson.M{"recipeId": bson.M{"$in": recipeIds}})
+}
+
+func (repository *RecipeRevisionRepository) DeleteRevisionsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error) {
+	if len(recipeIds) == 0 {
+		return 0, nil
+	}
+	collection := repository.db.GetClient().Database("Burned").Collection("RecipeRevision")
+	result, err := collection.DeleteMany(ctx, bson.M{"recipeId": bson.M{"$in": recipeIds}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
@@ -3,11 +3,13 @@ package repositories
 import (
 	"burned/backend/database"
 	"burned/backend/models"
+	"burned/backend/pagination"
 	"context"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type SavedRecipeRepositoryInterface interface {
@@ -17,6 +19,14 @@ type SavedRecipeRepositoryInterface interface {
 	GetSavedCountByRecipe(idRecipe primitive.ObjectID) (int64, error)
 	GetTop10MostSaved() ([]models.TopSavedRecipe, error)
 	GetSavedRecipesSavedByUserAndRecipe(idUser primitive.ObjectID, idRecipe primitive.ObjectID) ([]models.SavedRecipe, error)
+	GetRecipesSavedByUserPaged(idUser primitive.ObjectID, page pagination.Request) (pagination.Page[models.Recipe], error)
+	SyncSavedCounts() error
+	CountSavedByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error)
+	DeleteSavedByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error)
+	CountSavedByUser(ctx context.Context, userId primitive.ObjectID, excludeRecipes []primitive.ObjectID) (int64, error)
+	DeleteSavedByUser(ctx context.Context, userId primitive.ObjectID) (int64, error)
+	SetNotes(userId primitive.ObjectID, recipeId primitive.ObjectID, notes *models.RecipeNotes) (int64, error)
+	GetNotes(userId primitive.ObjectID, recipeId primitive.ObjectID) (*models.RecipeNotes, error)
 }
 
 type SavedRecipeRepository struct {
@@ -29,7 +39,15 @@ func NewSavedRecipeRepository(db database.DB) *SavedRecipeRepository {
 
 func (repository *SavedRecipeRepository) SavedRecipe(saved models.SavedRecipe) (*mongo.InsertOneResult, error) {
 	collection := repository.db.GetClient().Database("Burned").Collection("SavedRecipes")
-	return collection.InsertOne(context.TODO(), saved)
+	result, err := collection.InsertOne(context.TODO(), saved)
+	if err != nil {
+		return nil, err
+	}
+	//mantenemos el contador de la receta para poder ordenar por "más guardadas"
+	if err := repository.incrementSavedCount(saved.RecipeID, 1); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
 func (repository *SavedRecipeRepository) UnsavedRecipe(userId primitive.ObjectID, recipeId primitive.ObjectID) (*mongo.DeleteResult, error) {
@@ -37,8 +55,82 @@ func (repository *SavedRecipeRepository) UnsavedRecipe(userId primitive.ObjectID
 
 	filter := bson.M{"userId": userId, "recipeId": recipeId}
 
-	return collection.DeleteMany(context.TODO(), filter)
+	result, err := collection.DeleteMany(context.TODO(), filter)
+	if err != nil {
+		return nil, err
+	}
+	if result.DeletedCount > 0 {
+		if err := repository.incrementSavedCount(recipeId, -result.DeletedCount); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (repository *SavedRecipeRepository) incrementSavedCount(recipeId primitive.ObjectID, delta int64) error {
+	return repository.incrementSavedCountWithContext(context.TODO(), recipeId, delta)
+}
 
+func (repository *SavedRecipeRepository) incrementSavedCountWithContext(ctx context.Context, recipeId primitive.ObjectID, delta int64) error {
+	collection := repository.db.GetClient().Database("Burned").Collection("Recipe")
+	_, err := collection.UpdateOne(ctx, bson.M{"_id": recipeId}, bson.M{"$inc": bson.M{"savedCount": delta}})
+	return err
+}
+
+// SyncSavedCounts recalcula savedCount de todas las recetas a partir de la
+// colección SavedRecipes. Se corre al iniciar para completar recetas
+// anteriores al contador y corregir cualquier desvío.
+func (repository *SavedRecipeRepository) SyncSavedCounts() error {
+	ctx := context.TODO()
+	recipeCollection := repository.db.GetClient().Database("Burned").Collection("Recipe")
+
+	counts, err := repository.savedCounts(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = recipeCollection.UpdateMany(ctx, bson.M{"savedCount": bson.M{"$exists": false}}, bson.M{"$set": bson.M{"savedCount": 0}})
+	if err != nil {
+		return err
+	}
+	if len(counts) == 0 {
+		return nil
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(counts))
+	for _, count := range counts {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": count.RecipeID}).
+			SetUpdate(bson.M{"$set": bson.M{"savedCount": count.Count}}))
+	}
+	_, err = recipeCollection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+func (repository *SavedRecipeRepository) savedCounts(ctx context.Context) ([]models.TopSavedRecipe, error) {
+	return repository.savedCountsMatching(ctx, bson.M{})
+}
+
+func (repository *SavedRecipeRepository) savedCountsMatching(ctx context.Context, filter bson.M) ([]models.TopSavedRecipe, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("SavedRecipes")
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$recipeId",
+			"count": bson.M{"$sum": 1},
+		}}},
+	}
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var counts []models.TopSavedRecipe
+	if err := cursor.All(ctx, &counts); err != nil {
+		return nil, err
+	}
+	return counts, nil
 }
 
 func (repository *SavedRecipeRepository) GetRecipesSavedByUser(idUser primitive.ObjectID) ([]models.SavedRecipe, error) {
@@ -64,6 +156,34 @@ func (repository *SavedRecipeRepository) GetRecipesSavedByUser(idUser primitive.
 	}
 	return recipes, nil
 }
+
+// GetRecipesSavedByUserPaged devuelve las recetas (no los SavedRecipe) guardadas
+// por el usuario, paginadas y ordenadas con los mismos criterios que el resto
+// de los listados de recetas.
+func (repository *SavedRecipeRepository) GetRecipesSavedByUserPaged(idUser primitive.ObjectID, page pagination.Request) (pagination.Page[models.Recipe], error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("SavedRecipes")
+
+	sort, err := recipeSort(page.Sort, "newest")
+	if err != nil {
+		return pagination.Page[models.Recipe]{}, err
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"userId": idUser}}},
+		//una receta guardada dos veces aparece una sola vez
+		{{Key: "$group", Value: bson.M{"_id": "$recipeId"}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         "Recipe",
+			"localField":   "_id",
+			"foreignField": "_id",
+			"as":           "recipe",
+		}}},
+		{{Key: "$unwind", Value: "$recipe"}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$recipe"}}},
+	}
+	return pagination.Aggregate[models.Recipe](context.TODO(), collection, pipeline, sort, page)
+}
+
 func (repository *SavedRecipeRepository) GetSavedCountByRecipe(idRecipe primitive.ObjectID) (int64, error) {
 	collection := repository.db.GetClient().
 		Database("Burned").
@@ -130,3 +250,79 @@ func (repository *SavedRecipeRepository) GetSavedRecipesSavedByUserAndRecipe(idU
 
 	return savedRecipes, nil
 }
+
+func (repository *SavedRecipeRepository) CountSavedByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error) {
+	if len(recipeIds) == 0 {
+		return 0, nil
+	}
+	collection := repository.db.GetClient().Database("Burned").Collection("SavedRecipes")
+	return collection.CountDocuments(ctx, bson.M{"recipeId": bson.M{"$in": recipeIds}})
+}
+
+func (repository *SavedRecipeRepository) DeleteSavedByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error) {
+	if len(recipeIds) == 0 {
+		return 0, nil
+	}
+	collection := repository.db.GetClient().Database("Burned").Collection("SavedRecipes")
+	result, err := collection.DeleteMany(ctx, bson.M{"recipeId": bson.M{"$in": recipeIds}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+func (repository *SavedRecipeRepository) CountSavedByUser(ctx context.Context, userId primitive.ObjectID, excludeRecipes []primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("SavedRecipes")
+	return collection.CountDocuments(ctx, withoutRecipes(bson.M{"userId": userId}, excludeRecipes))
+}
+
+// DeleteSavedByUser borra todo lo guardado por el usuario y descuenta esos
+// guardados del savedCount de cada receta.
+func (repository *SavedRecipeRepository) DeleteSavedByUser(ctx context.Context, userId primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("SavedRecipes")
+
+	counts, err := repository.savedCountsMatching(ctx, bson.M{"userId": userId})
+	if err != nil {
+		return 0, err
+	}
+	result, err := collection.DeleteMany(ctx, bson.M{"userId": userId})
+	if err != nil {
+		return 0, err
+	}
+	for _, count := range counts {
+		if err := repository.incrementSavedCountWithContext(ctx, count.RecipeID, -count.Count); err != nil {
+			return 0, err
+		}
+	}
+	return result.DeletedCount, nil
+}
+
+// SetNotes guarda (o con nil borra) las notas del usuario sobre la receta.
+// Devuelve cuántos guardados coincidieron: 0 si la receta no está guardada.
+func (repository *SavedRecipeRepository) SetNotes(userId primitive.ObjectID, recipeId primitive.ObjectID, notes *models.RecipeNotes) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("SavedRecipes")
+	update := bson.M{"$set": bson.M{"notes": notes}}
+	if notes == nil {
+		update = bson.M{"$unset": bson.M{"notes": ""}}
+	}
+	result, err := collection.UpdateMany(context.TODO(), bson.M{"userId": userId, "recipeId": recipeId}, update)
+	if err != nil {
+		return 0, err
+	}
+	return result.MatchedCount, nil
+}
+
+// GetNotes devuelve nil sin error si la receta está guardada pero sin notas.
+func (repository *SavedRecipeRepository) GetNotes(userId primitive.ObjectID, recipeId primitive.ObjectID) (*models.RecipeNotes, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("SavedRecipes")
+	filter := bson.M{"userId": userId, "recipeId": recipeId, "notes": bson.M{"$exists": true}}
+	var saved models.SavedRecipe
+	err := collection.FindOne(context.TODO(), filter).Decode(&saved)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return saved.Notes, nil
+}
@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"burned/backend/database"
+	"burned/backend/models"
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ShoppingListRepositoryInterface interface {
+	GetList(userId primitive.ObjectID) (models.ShoppingList, error)
+	SaveList(list models.ShoppingList) error
+	AddItem(userId primitive.ObjectID, item models.ShoppingItem) error
+	UpdateItem(userId primitive.ObjectID, item models.ShoppingItem) (int64, error)
+	DeleteItem(userId primitive.ObjectID, itemId primitive.ObjectID) (int64, error)
+	DeleteCheckedItems(userId primitive.ObjectID) error
+	DeleteList(userId primitive.ObjectID) error
+	CountListByUser(ctx context.Context, userId primitive.ObjectID, ownRecipes []primitive.ObjectID) (int64, error)
+	DeleteListByUser(ctx context.Context, userId primitive.ObjectID) (int64, error)
+}
+
+type ShoppingListRepository struct {
+	db database.DB
+}
+
+func NewShoppingListRepository(db database.DB) *ShoppingListRepository {
+	return &ShoppingListRepository{db: db}
+}
+
+// GetList devuelve la lista del usuario; si todavía no tiene una devuelve una vacía.
+func (repository *ShoppingListRepository) GetList(userId primitive.ObjectID) (models.ShoppingList, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("ShoppingList")
+	var list models.ShoppingList
+	err := collection.FindOne(context.TODO(), bson.M{"_id": userId}).Decode(&list)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return models.ShoppingList{UserID: userId, Items: []models.ShoppingItem{}, Recipes: []models.ShoppingSource{}}, nil
+	}
+	if err != nil {
+		return models.ShoppingList{}, err
+	}
+	if list.Items == nil {
+		list.Items = []models.ShoppingItem{}
+	}
+	if list.Recipes == nil {
+		list.Recipes = []models.ShoppingSource{}
+	}
+	return list, nil
+}
+
+func (repository *ShoppingListRepository) SaveList(list models.ShoppingList) error {
+	collection := repository.db.GetClient().Database("Burned").Collection("ShoppingList")
+	opts := options.Replace().SetUpsert(true)
+	_, err := collection.ReplaceOne(context.TODO(), bson.M{"_id": list.UserID}, list, opts)
+	return err
+}
+
+func (repository *ShoppingListRepository) AddItem(userId primitive.ObjectID, item models.ShoppingItem) error {
+	collection := repository.db.GetClient().Database("Burned").Collection("ShoppingList")
+	update := bson.M{
+		"$push":        bson.M{"items": item},
+		"$set":         bson.M{"updatedAt": time.Now()},
+		"$setOnInsert": bson.M{"recipes": bson.A{}},
+	}
+	_, err := collection.UpdateOne(context.TODO(), bson.M{"_id": userId}, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// UpdateItem guarda los campos editables de un ítem y devuelve cuántos encontró.
+func (repository *ShoppingListRepository) UpdateItem(userId primitive.ObjectID, item models.ShoppingItem) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("ShoppingList")
+	update := bson.M{"$set": bson.M{
+		"items.$.name":    item.Name,
+		"items.$.key":     item.Key,
+		"items.$.aisle":   item.Aisle,
+		"items.$.amounts": item.Amounts,
+		"items.$.checked": item.Checked,
+		"updatedAt":       time.Now(),
+	}}
+	result, err := collection.UpdateOne(context.TODO(), bson.M{"_id": userId, "items._id": item.ID}, update)
+	if err != nil {
+		return 0, err
+	}
+	return result.MatchedCount, nil
+}
+
+func (repository *ShoppingListRepository) DeleteItem(userId primitive.ObjectID, itemId primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("ShoppingList")
+	update := bson.M{
+		"$pull": bson.M{"items": bson.M{"_id": itemId}},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+	result, err := collection.UpdateOne(context.TODO(), bson.M{"_id": userId, "items._id": itemId}, update)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (repository *ShoppingListRepository) DeleteCheckedItems(userId primitive.ObjectID) error {
+	collection := repository.db.GetClient().Database("Burned").Collection("ShoppingList")
+	update := bson.M{
+		"$pull": bson.M{"items": bson.M{"checked": true}},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+	_, err := collection.UpdateOne(context.TODO(), bson.M{"_id": userId}, update)
+	return err
+}
+
+func (repository *ShoppingListRepository) DeleteList(userId primitive.ObjectID) error {
+	collection := repository.db.GetClient().Database("Burned").Collection("ShoppingList")
+	_, err := collection.DeleteOne(context.TODO(), bson.M{"_id": userId})
+	return err
+}
+
+func (repository *ShoppingListRepository) CountListByUser(ctx context.Context, userId primitive.ObjectID, ownRecipes []primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("ShoppingList")
+	return collection.CountDocuments(ctx, bson.M{"_id": userId})
+}
+
+func (repository *ShoppingListRepository) DeleteListByUser(ctx context.Context, userId primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("ShoppingList")
+	result, err := collection.DeleteOne(ctx, bson.M{"_id": userId})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"burned/backend/database"
+	"burned/backend/models"
+	"burned/backend/pagination"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// substitutionSort lista las reglas por ingrediente
+var substitutionSort = pagination.Sort{Name: "ingredient", Field: "key"}
+
+type SubstitutionRepositoryInterface interface {
+	EnsureIndexes() error
+	CreateRule(rule models.SubstitutionRule) (models.SubstitutionRule, error)
+	UpdateRule(rule models.SubstitutionRule) (int64, error)
+	DeleteRule(id primitive.ObjectID) (int64, error)
+	GetRuleById(id primitive.ObjectID) (models.SubstitutionRule, error)
+	GetRulesByKeys(keys []string) ([]models.SubstitutionRule, error)
+	GetRulesPaged(page pagination.Request) (pagination.Page[models.SubstitutionRule], error)
+}
+
+type SubstitutionRepository struct {
+	db database.DB
+}
+
+func NewSubstitutionRepository(db database.DB) *SubstitutionRepository {
+	return &SubstitutionRepository{db: db}
+}
+
+func (repository *SubstitutionRepository) EnsureIndexes() error {
+	collection := repository.db.GetClient().Database("Burned").Collection("SubstitutionRule")
+	_, err := collection.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{{Key: "key", Value: 1}, {Key: "_id", Value: 1}},
+	})
+	return err
+}
+
+func (repository *SubstitutionRepository) CreateRule(rule models.SubstitutionRule) (models.SubstitutionRule, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("SubstitutionRule")
+	result, err := collection.InsertOne(context.TODO(), rule)
+	if err != nil {
+		return models.SubstitutionRule{}, err
+	}
+	rule.ID = result.InsertedID.(primitive.ObjectID)
+	return rule, nil
+}
+
+func (repository *SubstitutionRepository) UpdateRule(rule models.SubstitutionRule) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("SubstitutionRule")
+	update := bson.M{"$set": bson.M{
+		"ingredient":   rule.Ingredient,
+		"key":          rule.Key,
+		"quantity":     rule.Quantity,
+		"unit":         rule.Unit,
+		"substitute":   rule.Substitute,
+		"note":         rule.Note,
+		"allergens":    rule.Allergens,
+		"diets":        rule.Diets,
+		"unclassified": rule.Unclassified,
+		"updatedAt":    rule.UpdatedAt,
+	}}
+	result, err := collection.UpdateOne(context.TODO(), bson.M{"_id": rule.ID}, update)
+	if err != nil {
+		return 0, err
+	}
+	return result.MatchedCount, nil
+}
+
+func (repository *SubstitutionRepository) DeleteRule(id primitive.ObjectID) (int64, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("SubstitutionRule")
+	result, err := collection.DeleteOne(context.TODO(), bson.M{"_id": id})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+func (repository *SubstitutionRepository) GetRuleById(id primitive.ObjectID) (models.SubstitutionRule, error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("SubstitutionRule")
+	var rule models.SubstitutionRule
+	err := collection.FindOne(context.TODO(), bson.M{"_id": id}).Decode(&rule)
+	return rule, err
+}
+
+// GetRulesByKeys trae las reglas de los ingredientes con esas claves, en el
+// orden en que se cargaron.
+func (repository *SubstitutionRepository) GetRulesByKeys(keys []string) ([]models.SubstitutionRule, error) {
+	if len(keys) == 0 {
+		return []models.SubstitutionRule{}, nil
+	}
+	collection := repository.db.GetClient().Database("Burned").Collection("SubstitutionRule")
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := collection.Find(context.TODO(), bson.M{"key": bson.M{"$in": keys}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	rules := []models.SubstitutionRule{}
+	if err := cursor.All(context.TODO(), &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (repository *SubstitutionRepository) GetRulesPaged(page pagination.Request) (pagination.Page[models.SubstitutionRule], error) {
+	collection := repository.db.GetClient().Database("Burned").Collection("SubstitutionRule")
+	return pagination.Find[models.SubstitutionRule](context.TODO(), collection, bson.M{}, substitutionSort, page)
+}
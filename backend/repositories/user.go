@@ -15,7 +15,9 @@ type UserRepositoryInterface interface {
 	UpdateUser(user models.User) (*mongo.UpdateResult, error)
 	UpdatePassword(user models.User, password string) (*mongo.UpdateResult, error)
 	DeleteUser(id primitive.ObjectID) (*mongo.DeleteResult, error)
+	DeleteUserWithContext(ctx context.Context, id primitive.ObjectID) (*mongo.DeleteResult, error)
 	GetUserById(id primitive.ObjectID) (models.User, error)
+	GetUsersByIds(ids []primitive.ObjectID) ([]models.User, error)
 	GetUserByEmail(email string) (models.User, error)
 	GetUserByName(name string) (models.User, error)
 	GetUserByGoogleID(googleID string) (models.User, error)
@@ -61,9 +63,13 @@ func (repository *UserRepository) UpdateUser(user models.User) (*mongo.UpdateRes
 }
 
 func (repository *UserRepository) DeleteUser(id primitive.ObjectID) (*mongo.DeleteResult, error) {
+	return repository.DeleteUserWithContext(context.TODO(), id)
+}
+
+func (repository *UserRepository) DeleteUserWithContext(ctx context.Context, id primitive.ObjectID) (*mongo.DeleteResult, error) {
 	collection := repository.db.GetClient().Database("Burned").Collection("User")
 	filter := bson.M{"_id": id}
-	return collection.DeleteOne(context.TODO(), filter)
+	return collection.DeleteOne(ctx, filter)
 }
 
 func (repository *UserRepository) GetUserById(id primitive.ObjectID) (models.User, error) {
@@ -110,3 +116,22 @@ func (repository *UserRepository) GetUserByGoogleID(googleID string) (models.Use
 	}
 	return user, nil
 }
+
+// GetUsersByIds trae varios usuarios en una consulta, sin orden garantizado.
+func (repository *UserRepository) GetUsersByIds(ids []primitive.ObjectID) ([]models.User, error) {
+	if len(ids) == 0 {
+		return []models.User{}, nil
+	}
+	collection := repository.db.GetClient().Database("Burned").Collection("User")
+	cursor, err := collection.Find(context.TODO(), bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var users []models.User
+	if err := cursor.All(context.TODO(), &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
@@ -0,0 +1,147 @@
+// Package revisions compara dos fotos de una receta campo por campo.
+package revisions
+
+import (
+	"burned/backend/models"
+	"burned/backend/units"
+	"reflect"
+	"strconv"
+)
+
+const (
+	Added   = "added"
+	Removed = "removed"
+	Changed = "changed"
+)
+
+// Change es una diferencia entre dos revisiones. Key identifica el elemento
+// dentro de una lista: el nombre del ingrediente, el número de paso o el tag.
+type Change struct {
+	Field  string      `json:"field"`
+	Key    string      `json:"key,omitempty"`
+	Kind   string      `json:"kind"`
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+// Diff devuelve lo que cambió de from a to. Los ingredientes se emparejan por
+// nombre normalizado, los pasos por posición y los tags como conjunto.
+func Diff(from models.Recipe, to models.Recipe) []Change {
+	changes := []Change{}
+	scalar := func(field string, before, after interface{}) {
+		if before != after {
+			changes = append(changes, Change{Field: field, Kind: Changed, Before: before, After: after})
+		}
+	}
+	scalar("title", from.Title, to.Title)
+	scalar("description", from.Description, to.Description)
+	scalar("visibility", from.Visibility, to.Visibility)
+	scalar("totalTime", from.TotalTime, to.TotalTime)
+	scalar("servings", from.Servings, to.Servings)
+	scalar("dificultyLevel", from.DificultyLevel, to.DificultyLevel)
+	scalar("image", from.Image, to.Image)
+
+	changes = append(changes, diffIngredients(from.Ingredients, to.Ingredients)...)
+	changes = append(changes, diffSteps(from.Step, to.Step)...)
+	changes = append(changes, diffTags(from.Tags, to.Tags)...)
+	return changes
+}
+
+func diffIngredients(from []models.Ingredient, to []models.Ingredient) []Change {
+	changes := []Change{}
+	before := keyIngredients(from)
+	after := keyIngredients(to)
+
+	for _, key := range orderedKeys(from, before) {
+		previous := before[key]
+		current, ok := after[key]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Field: "ingredients", Key: key, Kind: Removed, Before: previous})
+		case !reflect.DeepEqual(previous, current):
+			changes = append(changes, Change{Field: "ingredients", Key: key, Kind: Changed, Before: previous, After: current})
+		}
+	}
+	for _, key := range orderedKeys(to, after) {
+		if _, ok := before[key]; !ok {
+			changes = append(changes, Change{Field: "ingredients", Key: key, Kind: Added, After: after[key]})
+		}
+	}
+	return changes
+}
+
+// keyIngredients indexa por nombre normalizado; si un nombre se repite, las
+// siguientes apariciones llevan "#2", "#3"...
+func keyIngredients(ingredients []models.Ingredient) map[string]models.Ingredient {
+	keyed := make(map[string]models.Ingredient, len(ingredients))
+	for _, ingredient := range ingredients {
+		// Terms se deriva del nombre: no es un cambio del autor
+		ingredient.Terms = nil
+		keyed[ingredientKey(keyed, ingredient)] = ingredient
+	}
+	return keyed
+}
+
+func ingredientKey(seen map[string]models.Ingredient, ingredient models.Ingredient) string {
+	base := units.Normalize(ingredient.Name)
+	key := base
+	for n := 2; ; n++ {
+		if _, ok := seen[key]; !ok {
+			return key
+		}
+		key = base + " #" + strconv.Itoa(n)
+	}
+}
+
+// orderedKeys devuelve las claves en el orden en que aparecen en la receta.
+func orderedKeys(ingredients []models.Ingredient, keyed map[string]models.Ingredient) []string {
+	keys := make([]string, 0, len(ingredients))
+	seen := make(map[string]models.Ingredient, len(ingredients))
+	for _, ingredient := range ingredients {
+		key := ingredientKey(seen, ingredient)
+		seen[key] = ingredient
+		if _, ok := keyed[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func diffSteps(from []models.Step, to []models.Step) []Change {
+	changes := []Change{}
+	for i := 0; i < len(from) || i < len(to); i++ {
+		key := strconv.Itoa(i + 1)
+		switch {
+		case i >= len(to):
+			changes = append(changes, Change{Field: "step", Key: key, Kind: Removed, Before: from[i]})
+		case i >= len(from):
+			changes = append(changes, Change{Field: "step", Key: key, Kind: Added, After: to[i]})
+		case !reflect.DeepEqual(from[i], to[i]):
+			changes = append(changes, Change{Field: "step", Key: key, Kind: Changed, Before: from[i], After: to[i]})
+		}
+	}
+	return changes
+}
+
+func diffTags(from []string, to []string) []Change {
+	changes := []Change{}
+	before := make(map[string]bool, len(from))
+	for _, tag := range from {
+		before[tag] = true
+	}
+	after := make(map[string]bool, len(to))
+	for _, tag := range to {
+		after[tag] = true
+	}
+	for _, tag := range from {
+		if !after[tag] {
+			changes = append(changes, Change{Field: "tags", Key: tag, Kind: Removed})
+		}
+	}
+	for _, tag := range to {
+		if !before[tag] {
+			changes = append(changes, Change{Field: "tags", Key: tag, Kind: Added})
+		}
+	}
+	return changes
+}
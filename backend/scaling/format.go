@@ -0,0 +1,46 @@
+package scaling
+
+import (
+	"burned/backend/units"
+	"strconv"
+	"strings"
+)
+
+// Format arma el texto que se muestra para una cantidad: "1 1/2 cup",
+// "250 g" o "to taste".
+func Format(amount Amount) string {
+	unit, ok := units.Lookup(amount.Unit)
+	if ok && unit.Dimension == units.ToTaste {
+		return "to taste"
+	}
+
+	var quantity string
+	if ok && unit.System == units.Metric {
+		quantity = FormatDecimal(amount.Quantity)
+	} else {
+		quantity = FormatFraction(amount.Quantity)
+	}
+	if amount.Unit == "" || amount.Unit == units.CodeUnit {
+		return quantity
+	}
+	return quantity + " " + strings.ReplaceAll(amount.Unit, "_", " ")
+}
+
+// FormatDecimal escribe una cantidad con hasta dos decimales, sin ceros de
+// más: "1.15", "250".
+func FormatDecimal(quantity float64) string {
+	return strconv.FormatFloat(roundDecimals(quantity), 'f', -1, 64)
+}
+
+// FormatFraction escribe una cantidad como entero y fracción de cocina.
+func FormatFraction(quantity float64) string {
+	whole, numerator, denominator := Fraction(quantity)
+	switch {
+	case numerator == 0:
+		return strconv.Itoa(whole)
+	case whole == 0:
+		return strconv.Itoa(numerator) + "/" + strconv.Itoa(denominator)
+	default:
+		return strconv.Itoa(whole) + " " + strconv.Itoa(numerator) + "/" + strconv.Itoa(denominator)
+	}
+}
@@ -0,0 +1,206 @@
+package scaling
+
+import (
+	"burned/backend/models"
+	"burned/backend/units"
+	"errors"
+	"math"
+)
+
+var (
+	ErrUnknownUnit      = errors.New("unknown unit")
+	ErrIncompatibleUnit = errors.New("units measure different things")
+	ErrInvalidSystem    = errors.New("invalid unit system")
+)
+
+// Amount es una cantidad con su unidad del catálogo de backend/units.
+type Amount struct {
+	Quantity float64
+	Unit     string
+}
+
+// displayUnits son las unidades que se eligen al expresar una cantidad en un
+// sistema, de mayor a menor, con el mínimo a partir del cual se usan. Así
+// 0.3 tazas quedan en tazas pero 0.1 tazas pasan a cucharadas.
+var displayUnits = map[units.System]map[units.Dimension][]struct {
+	code    string
+	minimum float64
+}{
+	units.Metric: {
+		units.Mass:   {{"kg", 1}, {"g", 0}},
+		units.Volume: {{"l", 1}, {"ml", 0}},
+	},
+	units.Imperial: {
+		units.Mass:   {{"lb", 1}, {"oz", 0}},
+		units.Volume: {{"gallon", 1}, {"quart", 1}, {"cup", 0.25}, {"tbsp", 1}, {"tsp", 0}},
+	},
+}
+
+// ParseSystem valida el sistema pedido. Vacío significa "el de cada ingrediente".
+func ParseSystem(system string) (units.System, error) {
+	switch units.System(system) {
+	case "":
+		return "", nil
+	case units.Metric, units.Imperial:
+		return units.System(system), nil
+	}
+	return "", ErrInvalidSystem
+}
+
+// Factor calcula el multiplicador para pasar de una cantidad de porciones a otra.
+func Factor(fromServings int, toServings int) (float64, error) {
+	if fromServings <= 0 || toServings <= 0 {
+		return 0, errors.New("servings must be greater than zero")
+	}
+	return float64(toServings) / float64(fromServings), nil
+}
+
+// Convert expresa la cantidad en otra unidad de la misma dimensión.
+func Convert(amount Amount, target string) (Amount, error) {
+	from, ok := units.Lookup(amount.Unit)
+	if !ok {
+		return Amount{}, ErrUnknownUnit
+	}
+	to, ok := units.Lookup(target)
+	if !ok {
+		return Amount{}, ErrUnknownUnit
+	}
+	if from.Code == to.Code {
+		return amount, nil
+	}
+	if from.Dimension != to.Dimension || (from.Dimension != units.Mass && from.Dimension != units.Volume) {
+		return Amount{}, ErrIncompatibleUnit
+	}
+	return Amount{Quantity: amount.Quantity * from.ToBase / to.ToBase, Unit: to.Code}, nil
+}
+
+// ToSystem expresa la cantidad en la unidad más cómoda del sistema dado. Si
+// system está vacío se usa el de la propia unidad. Conteos, "a gusto" y
+// unidades desconocidas se devuelven sin cambios.
+func ToSystem(amount Amount, system units.System) Amount {
+	unit, ok := units.Lookup(amount.Unit)
+	if !ok || unit.System == "" {
+		return amount
+	}
+	if system == "" {
+		system = unit.System
+	}
+	candidates := displayUnits[system][unit.Dimension]
+	for _, candidate := range candidates {
+		converted, err := Convert(amount, candidate.code)
+		if err != nil {
+			continue
+		}
+		if converted.Quantity >= candidate.minimum {
+			return converted
+		}
+	}
+	return amount
+}
+
+// ScaleIngredients multiplica las cantidades por factor, las pasa al sistema
+// pedido y las redondea a medidas de cocina. No modifica el slice recibido.
+func ScaleIngredients(ingredients []models.Ingredient, factor float64, system units.System) []models.Ingredient {
+	scaled := make([]models.Ingredient, 0, len(ingredients))
+	for _, ingredient := range ingredients {
+		amount := Amount{Quantity: ingredient.Quantity * factor, Unit: ingredient.Unit}
+		// Con factor 1 y sin cambio de sistema respetamos la unidad del autor
+		if factor != 1 || system != "" {
+			amount = ToSystem(amount, system)
+		}
+		amount.Quantity = Round(amount)
+
+		ingredient.Quantity = amount.Quantity
+		ingredient.Unit = amount.Unit
+		ingredient.Display = Format(amount)
+		scaled = append(scaled, ingredient)
+	}
+	return scaled
+}
+
+// Round lleva la cantidad a un valor que se pueda medir en la cocina:
+// fracciones simples en unidades imperiales y de conteo, y pasos redondos
+// en unidades métricas.
+func Round(amount Amount) float64 {
+	unit, ok := units.Lookup(amount.Unit)
+	if !ok {
+		return amount.Quantity
+	}
+	switch {
+	case unit.Dimension == units.ToTaste:
+		return 0
+	case unit.System == units.Metric:
+		return roundMetric(amount.Quantity, unit)
+	case unit.Dimension == units.Count:
+		// medio huevo se puede, un octavo no
+		return math.Max(roundToStep(amount.Quantity, 0.5), 0.5)
+	default:
+		whole, numerator, denominator := Fraction(amount.Quantity)
+		return float64(whole) + float64(numerator)/float64(denominator)
+	}
+}
+
+func roundMetric(quantity float64, unit units.Unit) float64 {
+	// kg y l se expresan con hasta dos decimales; g y ml en pasos según tamaño
+	if unit.ToBase >= 1000 {
+		return math.Max(roundToStep(quantity, 0.05), 0.05)
+	}
+	switch {
+	case quantity < 10:
+		return math.Max(roundToStep(quantity, 0.5), 0.5)
+	case quantity < 100:
+		return roundToStep(quantity, 1)
+	case quantity < 1000:
+		return roundToStep(quantity, 5)
+	default:
+		return roundToStep(quantity, 10)
+	}
+}
+
+// roundToStep redondea al múltiplo de step más cercano. Multiplicar por step
+// deja restos de punto flotante (1.1500000000000001), así que el resultado se
+// recorta a los dos decimales que alcanzan para todos los pasos.
+func roundToStep(quantity float64, step float64) float64 {
+	return roundDecimals(math.Round(quantity/step) * step)
+}
+
+func roundDecimals(quantity float64) float64 {
+	return math.Round(quantity*100) / 100
+}
+
+// kitchenDenominators son las fracciones que tienen medida en una cocina.
+var kitchenDenominators = []int{2, 3, 4, 8}
+
+// Fraction aproxima quantity a un entero más una fracción de cocina (1/2,
+// 1/3, 1/4, 1/8 y sus múltiplos). Nunca devuelve cero para una cantidad
+// positiva: lo mínimo es 1/8.
+func Fraction(quantity float64) (whole int, numerator int, denominator int) {
+	if quantity <= 0 {
+		return 0, 0, 1
+	}
+	whole = int(math.Floor(quantity))
+	rest := quantity - float64(whole)
+
+	bestNumerator, bestDenominator := 0, 1
+	bestError := rest
+	if 1-rest < bestError {
+		bestNumerator, bestDenominator, bestError = 1, 1, 1-rest
+	}
+	for _, candidate := range kitchenDenominators {
+		n := int(math.Round(rest * float64(candidate)))
+		if n == 0 || n == candidate {
+			continue
+		}
+		if diff := math.Abs(rest - float64(n)/float64(candidate)); diff < bestError-1e-9 {
+			bestNumerator, bestDenominator, bestError = n, candidate, diff
+		}
+	}
+
+	if bestNumerator == bestDenominator {
+		return whole + 1, 0, 1
+	}
+	if whole == 0 && bestNumerator == 0 {
+		return 0, 1, 8
+	}
+	return whole, bestNumerator, bestDenominator
+}
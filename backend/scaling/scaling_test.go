@@ -0,0 +1,51 @@
+package scaling
+
+import (
+	"burned/backend/models"
+	"burned/backend/units"
+	"testing"
+)
+
+func TestScaleIngredientsDisplay(t *testing.T) {
+	tests := []struct {
+		name       string
+		ingredient models.Ingredient
+		factor     float64
+		system     units.System
+		want       string
+	}{
+		{"kg without float residue", models.Ingredient{Quantity: 0.23, Unit: "kg"}, 5, "", "1.15 kg"},
+		{"kg step of 0.05", models.Ingredient{Quantity: 0.7, Unit: "kg"}, 3, "", "2.1 kg"},
+		{"liters", models.Ingredient{Quantity: 0.35, Unit: "l"}, 3, "", "1.05 l"},
+		{"small grams", models.Ingredient{Quantity: 1.3, Unit: "g"}, 3, "", "4 g"},
+		{"grams to kg", models.Ingredient{Quantity: 350, Unit: "g"}, 3, "", "1.05 kg"},
+		{"cups as fraction", models.Ingredient{Quantity: 0.5, Unit: "cup"}, 3, "", "1 1/2 cup"},
+		{"count in halves", models.Ingredient{Quantity: 3, Unit: "unit"}, 0.5, "", "1 1/2"},
+		{"imperial to metric", models.Ingredient{Quantity: 1, Unit: "cup"}, 1, units.Metric, "235 ml"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scaled := ScaleIngredients([]models.Ingredient{test.ingredient}, test.factor, test.system)
+			if got := scaled[0].Display; got != test.want {
+				t.Errorf("Display = %q, want %q (quantity %v)", got, test.want, scaled[0].Quantity)
+			}
+		})
+	}
+}
+
+func TestFormatDecimal(t *testing.T) {
+	tests := []struct {
+		quantity float64
+		want     string
+	}{
+		{1.1500000000000001, "1.15"},
+		{0.1 + 0.2, "0.3"},
+		{250, "250"},
+		{2.006, "2.01"},
+	}
+	for _, test := range tests {
+		if got := FormatDecimal(test.quantity); got != test.want {
+			t.Errorf("FormatDecimal(%v) = %q, want %q", test.quantity, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package screening
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Post es una publicación reciente del autor, con el mismo Body que Content.
+type Post struct {
+	Body      string
+	CreatedAt time.Time
+}
+
+// History da las publicaciones recientes de un autor para un tipo de
+// contenido, sin la que se está editando.
+type History interface {
+	RecentPosts(kind string, authorId primitive.ObjectID, exclude primitive.ObjectID, since time.Time) ([]Post, error)
+}
+
+// RepeatedRule detecta el mismo texto publicado varias veces y las ráfagas
+// de publicaciones. Un texto igual a otro reciente queda para revisar, y a
+// partir de RejectAt copias se rechaza. Los textos cortos ("¡Gracias!") no
+// cuentan como repetidos.
+type RepeatedRule struct {
+	history     History
+	Window      time.Duration
+	RejectAt    int
+	MinLength   int
+	FloodWindow time.Duration
+	FloodLimit  int
+	now         func() time.Time
+}
+
+func NewRepeatedRule(history History) *RepeatedRule {
+	return &RepeatedRule{
+		history:     history,
+		Window:      24 * time.Hour,
+		RejectAt:    2,
+		MinLength:   15,
+		FloodWindow: 10 * time.Minute,
+		FloodLimit:  10,
+		now:         time.Now,
+	}
+}
+
+func (rule *RepeatedRule) Name() string {
+	return "repeated"
+}
+
+func (rule *RepeatedRule) Check(content Content) ([]Finding, error) {
+	now := rule.now()
+	since := now.Add(-max(rule.Window, rule.FloodWindow))
+	posts, err := rule.history.RecentPosts(content.Kind, content.AuthorID, content.ID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := []Finding{}
+	body := strings.Join(Words(content.Body), " ")
+	copies, recent := 0, 0
+	for _, post := range posts {
+		if post.CreatedAt.After(now.Add(-rule.FloodWindow)) {
+			recent++
+		}
+		if len(body) >= rule.MinLength && post.CreatedAt.After(now.Add(-rule.Window)) && strings.Join(Words(post.Body), " ") == body {
+			copies++
+		}
+	}
+	switch {
+	case copies > 0 && rule.RejectAt > 0 && copies >= rule.RejectAt:
+		findings = append(findings, Finding{Verdict: Reject, Reason: fmt.Sprintf("same text already posted %d times", copies)})
+	case copies > 0:
+		findings = append(findings, Finding{Verdict: Hold, Reason: "same text already posted"})
+	}
+	if rule.FloodLimit > 0 && recent >= rule.FloodLimit {
+		findings = append(findings, Finding{Verdict: Hold, Reason: fmt.Sprintf("%d posts in the last %s", recent, rule.FloodWindow)})
+	}
+	return findings, nil
+}
@@ -0,0 +1,207 @@
+// Package screening revisa el texto de comentarios y recetas antes de
+// guardarlos. Cada regla decide si el contenido se publica, se publica
+// marcado para moderación, queda oculto hasta que lo revisen o se rechaza.
+package screening
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Verdict es lo que se hace con el contenido; de menor a mayor severidad,
+// así el resultado del pipeline es el máximo de sus reglas.
+type Verdict int
+
+const (
+	Allow  Verdict = iota // se publica
+	Flag                  // se publica y se abre un caso para moderación
+	Hold                  // se guarda oculto hasta que un moderador lo revise
+	Reject                // no se guarda
+)
+
+var verdictNames = []string{"allow", "flag", "hold", "reject"}
+
+func (verdict Verdict) String() string {
+	if verdict < Allow || verdict > Reject {
+		return "unknown"
+	}
+	return verdictNames[verdict]
+}
+
+// ParseVerdict interpreta "allow", "flag", "hold" o "reject".
+func ParseVerdict(name string) (Verdict, error) {
+	for i, verdictName := range verdictNames {
+		if strings.EqualFold(strings.TrimSpace(name), verdictName) {
+			return Verdict(i), nil
+		}
+	}
+	return Allow, errors.New("invalid verdict: " + name)
+}
+
+// Content es lo que se revisa. Body es el texto principal (el comentario, o
+// título y descripción de una receta), que además se compara contra las
+// publicaciones recientes del autor; Extra es el resto del texto, que solo
+// pasa por las reglas de contenido. ID va vacío en un alta.
+type Content struct {
+	Kind     string
+	ID       primitive.ObjectID
+	AuthorID primitive.ObjectID
+	Body     string
+	Extra    []string
+}
+
+func (content Content) texts() []string {
+	return append([]string{content.Body}, content.Extra...)
+}
+
+// Finding es lo que encontró una regla.
+type Finding struct {
+	Rule    string
+	Verdict Verdict
+	Reason  string
+}
+
+func (finding Finding) String() string {
+	return finding.Rule + ": " + finding.Reason + " (" + finding.Verdict.String() + ")"
+}
+
+// Result es la decisión del pipeline: el veredicto más severo y todo lo que
+// se encontró.
+type Result struct {
+	Verdict  Verdict
+	Findings []Finding
+}
+
+// Reason es el motivo de la regla que definió el veredicto.
+func (result Result) Reason() string {
+	for _, finding := range result.Findings {
+		if finding.Verdict == result.Verdict {
+			return finding.Reason
+		}
+	}
+	return ""
+}
+
+// Rule es una regla del pipeline.
+type Rule interface {
+	Name() string
+	Check(content Content) ([]Finding, error)
+}
+
+// Pipeline aplica sus reglas en orden. Se arma en main y se le pueden
+// agregar reglas propias con Add.
+type Pipeline struct {
+	rules []Rule
+}
+
+func NewPipeline(rules ...Rule) *Pipeline {
+	return &Pipeline{rules: rules}
+}
+
+func (pipeline *Pipeline) Add(rule Rule) {
+	pipeline.rules = append(pipeline.rules, rule)
+}
+
+// Check corre todas las reglas. Si alguna falla (por ejemplo, no pudo leer
+// el historial) se sigue con las demás y el error se devuelve junto con el
+// resultado: moderar no debe impedir publicar por un problema interno.
+func (pipeline *Pipeline) Check(content Content) (Result, error) {
+	result := Result{Verdict: Allow, Findings: []Finding{}}
+	var failures []error
+	for _, rule := range pipeline.rules {
+		findings, err := rule.Check(content)
+		if err != nil {
+			failures = append(failures, errors.New(rule.Name()+": "+err.Error()))
+			continue
+		}
+		for _, finding := range findings {
+			if finding.Rule == "" {
+				finding.Rule = rule.Name()
+			}
+			if finding.Verdict > result.Verdict {
+				result.Verdict = finding.Verdict
+			}
+			result.Findings = append(result.Findings, finding)
+		}
+	}
+	return result, errors.Join(failures...)
+}
+
+// leet son los reemplazos habituales para esquivar filtros ("m1erd4").
+var leet = map[rune]rune{'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't', '@': 'a', '$': 's'}
+
+// Fold lleva el texto a la forma que comparan las reglas: minúsculas, sin
+// acentos (pero con ñ, para no confundir "año" con "ano") y sin espacios
+// repetidos.
+func Fold(text string) string {
+	folded := []rune{}
+	for _, r := range norm.NFD.String(strings.ToLower(text)) {
+		switch {
+		case r == 0x0303 && len(folded) > 0 && folded[len(folded)-1] == 'n':
+			folded[len(folded)-1] = 'ñ'
+		case r >= 0x0300 && r <= 0x036f:
+			continue
+		default:
+			folded = append(folded, r)
+		}
+	}
+	return strings.Join(strings.Fields(string(folded)), " ")
+}
+
+// Words separa el texto plegado en palabras. Dentro de una palabra con
+// letras los números y símbolos de leet se leen como letras, y las letras
+// repetidas tres o más veces cuentan una sola ("mieeerda").
+func Words(text string) []string {
+	fields := strings.FieldsFunc(Fold(text), func(r rune) bool {
+		_, isLeet := leet[r]
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !isLeet
+	})
+	words := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if word := unleet(field); word != "" {
+			words = append(words, squeeze(word))
+		}
+	}
+	return words
+}
+
+func unleet(field string) string {
+	hasLetter := false
+	for _, r := range field {
+		if unicode.IsLetter(r) {
+			hasLetter = true
+			break
+		}
+	}
+	if !hasLetter {
+		return strings.Trim(field, "@$")
+	}
+	return strings.Map(func(r rune) rune {
+		if replacement, ok := leet[r]; ok {
+			return replacement
+		}
+		return r
+	}, field)
+}
+
+func squeeze(word string) string {
+	runes := []rune(word)
+	squeezed := make([]rune, 0, len(runes))
+	for i := 0; i < len(runes); {
+		j := i
+		for j < len(runes) && runes[j] == runes[i] {
+			j++
+		}
+		if j-i >= 3 {
+			squeezed = append(squeezed, runes[i])
+		} else {
+			squeezed = append(squeezed, runes[i:j]...)
+		}
+		i = j
+	}
+	return string(squeezed)
+}
@@ -0,0 +1,65 @@
+package screening
+
+import "testing"
+
+func defaultPipeline() *Pipeline {
+	return NewPipeline(NewWordRule(DefaultWords()), NewLinkRule(), NewSpamRule())
+}
+
+// benignSentences son textos comunes de recetas que no pueden frenar ni
+// marcar una publicación.
+var benignSentences = []string{
+	"Retard the dough in the fridge overnight",
+	"Faggots and peas with onion gravy",
+	"La receta de mi tío Kike para el asado",
+	"Batir los huevos con la leche y agregar la harina de a poco",
+	"Rellenar las conchas de pan dulce con crema",
+	"Mix the spices: cumin, paprika and a pinch of salt",
+	"Hornear a 180 grados durante 25-30 minutos",
+	"Dejar leudar la masa hasta que duplique su volumen",
+	"Hervir (350-400) (180-200) grados",
+	"Horno a 180 - 200 grados, 200-220 si es eléctrico, 10-12 minutos",
+}
+
+func TestBenignCookingTextIsAllowed(t *testing.T) {
+	pipeline := defaultPipeline()
+	for _, sentence := range benignSentences {
+		result, err := pipeline.Check(Content{Kind: "comment", Body: sentence})
+		if err != nil {
+			t.Fatalf("%q: %v", sentence, err)
+		}
+		if result.Verdict != Allow {
+			t.Errorf("%q: verdict %s, findings %v", sentence, result.Verdict, result.Findings)
+		}
+	}
+}
+
+func TestSlursAreStillRejected(t *testing.T) {
+	pipeline := defaultPipeline()
+	for _, sentence := range []string{"sos un maricon", "kill yourself"} {
+		result, _ := pipeline.Check(Content{Kind: "comment", Body: sentence})
+		if result.Verdict != Reject {
+			t.Errorf("%q: verdict %s, want reject", sentence, result.Verdict)
+		}
+	}
+}
+
+func TestHasPhone(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"escribime al +54 9 11 4567-8901", true},
+		{"llamame al 1145678901", true},
+		{"whatsapp (011) 4567-8901", true},
+		{"al 15-5555-5555", true},
+		{"Hervir (350-400) (180-200) grados", false},
+		{"180 - 200 grados por 25-30 minutos", false},
+		{"500 g de harina y 2 huevos", false},
+	}
+	for _, test := range tests {
+		if got := hasPhone(test.text); got != test.want {
+			t.Errorf("hasPhone(%q) = %v, want %v", test.text, got, test.want)
+		}
+	}
+}
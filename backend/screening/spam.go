@@ -0,0 +1,189 @@
+package screening
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// domainPattern distingue mayúsculas a propósito: "bien.Es importante" es
+// un espacio olvidado y no un dominio.
+var (
+	urlPattern    = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"]+`)
+	domainPattern = regexp.MustCompile(`\b[a-z0-9][a-z0-9-]*(?:\.[a-z0-9-]+)*\.(?:com|net|org|info|biz|xyz|ru|io|co|ar|es|mx|cl|uy|pe|ly|me|link|click|top|shop|store|online|site)\b(?:/[^\s<>"]*)?`)
+	emailPattern  = regexp.MustCompile(`(?i)\b[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}\b`)
+	phonePattern  = regexp.MustCompile(`\+?\d[\d\s().-]{6,}\d`)
+	dashSpaces    = regexp.MustCompile(`\s*-\s*`)
+	shortRange    = regexp.MustCompile(`^\d{1,3}-\d{1,3}$`)
+)
+
+// phoneDigits es lo mínimo que tiene un teléfono sin contar separadores.
+const phoneDigits = 8
+
+// shorteners esconden el destino real del link.
+var shorteners = []string{"bit.ly", "tinyurl.com", "t.co", "goo.gl", "ow.ly", "is.gd", "cutt.ly", "rebrand.ly", "shorturl.at", "rb.gy"}
+
+// LinkRule cuenta los links del texto. Uno se publica marcado (una receta
+// puede citar su fuente); a partir de HoldAt, o si alguno es un acortador,
+// queda para revisar.
+type LinkRule struct {
+	HoldAt int
+}
+
+func NewLinkRule() *LinkRule {
+	return &LinkRule{HoldAt: 3}
+}
+
+func (rule *LinkRule) Name() string {
+	return "links"
+}
+
+func (rule *LinkRule) Check(content Content) ([]Finding, error) {
+	links := map[string]bool{}
+	for _, text := range content.texts() {
+		for _, link := range urlPattern.FindAllString(text, -1) {
+			links[strings.ToLower(link)] = true
+		}
+		//los dominios sueltos ("miweb.com") que no estaban ya dentro de una URL
+		withoutURLs := urlPattern.ReplaceAllString(emailPattern.ReplaceAllString(text, " "), " ")
+		for _, link := range domainPattern.FindAllString(withoutURLs, -1) {
+			links[strings.ToLower(link)] = true
+		}
+	}
+	if len(links) == 0 {
+		return nil, nil
+	}
+	for link := range links {
+		host := strings.TrimPrefix(strings.TrimPrefix(strings.TrimPrefix(link, "https://"), "http://"), "www.")
+		for _, shortener := range shorteners {
+			if host == shortener || strings.HasPrefix(host, shortener+"/") {
+				return []Finding{{Verdict: Hold, Reason: "contains a shortened link"}}, nil
+			}
+		}
+	}
+	if rule.HoldAt > 0 && len(links) >= rule.HoldAt {
+		return []Finding{{Verdict: Hold, Reason: fmt.Sprintf("contains %d links", len(links))}}, nil
+	}
+	return []Finding{{Verdict: Flag, Reason: fmt.Sprintf("contains %d link(s)", len(links))}}, nil
+}
+
+// spamPhrases son frases típicas de publicidad o estafas. Las de hold no
+// tienen uso en una receta; las de flag pueden tenerlo ("lo compartí por
+// WhatsApp") pero conviene mirarlas.
+var spamPhrases = map[string]Verdict{
+	"gana dinero":         Hold,
+	"ganar dinero":        Hold,
+	"dinero facil":        Hold,
+	"trabaja desde casa":  Hold,
+	"trabajar desde casa": Hold,
+	"haz click":           Hold,
+	"hace click":          Hold,
+	"click aqui":          Hold,
+	"click here":          Hold,
+	"earn money":          Hold,
+	"make money":          Hold,
+	"work from home":      Hold,
+	"free money":          Hold,
+	"seguidores gratis":   Hold,
+	"free followers":      Hold,
+	"viagra":              Hold,
+	"cialis":              Hold,
+	"casino":              Hold,
+	"forex":               Hold,
+	"onlyfans":            Hold,
+	"bitcoin":             Flag,
+	"crypto":              Flag,
+	"criptomonedas":       Flag,
+	"apuestas":            Flag,
+	"whatsapp":            Flag,
+	"telegram":            Flag,
+	"codigo de descuento": Flag,
+	"promo code":          Flag,
+	"discount code":       Flag,
+}
+
+// SpamRule busca frases de spam y señales de texto automático: mayúsculas
+// sostenidas, caracteres repetidos, mails y teléfonos.
+type SpamRule struct {
+	phrases *WordList
+}
+
+func NewSpamRule() *SpamRule {
+	list := &WordList{entries: map[string]Verdict{}}
+	for phrase, verdict := range spamPhrases {
+		words := Words(phrase)
+		list.entries[strings.Join(words, " ")] = verdict
+		list.longest = max(list.longest, len(words))
+	}
+	return &SpamRule{phrases: list}
+}
+
+func (rule *SpamRule) Name() string {
+	return "spam"
+}
+
+func (rule *SpamRule) Check(content Content) ([]Finding, error) {
+	findings := phraseFindings(rule.phrases.find(content.texts()), "contains spam phrase")
+
+	var letters, upper int
+	repeated, contact := false, false
+	for _, text := range content.texts() {
+		run, last := 0, rune(0)
+		for _, r := range text {
+			if unicode.IsLetter(r) {
+				letters++
+				if unicode.IsUpper(r) {
+					upper++
+				}
+			}
+			if r == last && !unicode.IsSpace(r) {
+				run++
+			} else {
+				run, last = 1, r
+			}
+			if run >= 8 {
+				repeated = true
+			}
+		}
+		if emailPattern.MatchString(text) || hasPhone(text) {
+			contact = true
+		}
+	}
+	if letters >= 20 && float64(upper) > 0.7*float64(letters) {
+		findings = append(findings, Finding{Verdict: Flag, Reason: "mostly uppercase"})
+	}
+	if repeated {
+		findings = append(findings, Finding{Verdict: Flag, Reason: "long run of repeated characters"})
+	}
+	if contact {
+		findings = append(findings, Finding{Verdict: Flag, Reason: "contains contact details"})
+	}
+	return findings, nil
+}
+
+// hasPhone busca un teléfono: al menos phoneDigits dígitos entre separadores.
+// Los rangos de números cortos ("180-200 grados", "(350-400)") no cuentan,
+// así una lista de temperaturas o tiempos no parece un número.
+func hasPhone(text string) bool {
+	for _, candidate := range phonePattern.FindAllString(text, -1) {
+		tokens := strings.FieldsFunc(dashSpaces.ReplaceAllString(candidate, "-"), func(r rune) bool {
+			return unicode.IsSpace(r) || r == '(' || r == ')'
+		})
+		digits := 0
+		for _, token := range tokens {
+			if shortRange.MatchString(token) {
+				continue
+			}
+			for _, r := range token {
+				if unicode.IsDigit(r) {
+					digits++
+				}
+			}
+		}
+		if digits >= phoneDigits {
+			return true
+		}
+	}
+	return false
+}
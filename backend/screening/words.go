@@ -0,0 +1,169 @@
+package screening
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// defaultWords es la lista que viene con el backend; se reemplaza con
+// MODERATION_WORDLIST_PATH.
+//
+//go:embed data/words.txt
+var defaultWords []byte
+
+// maxPhraseWords acota las frases de la lista.
+const maxPhraseWords = 6
+
+// WordList son palabras y frases con la acción que corresponde a cada una,
+// ya en la forma de Words.
+type WordList struct {
+	entries map[string]Verdict
+	longest int
+}
+
+var (
+	defaultOnce sync.Once
+	defaultList *WordList
+)
+
+// DefaultWords devuelve la lista embebida. Si no se puede leer es un error
+// de compilación del backend, así que entra en pánico.
+func DefaultWords() *WordList {
+	defaultOnce.Do(func() {
+		list, err := ParseWords(defaultWords)
+		if err != nil {
+			panic("screening: invalid embedded word list: " + err.Error())
+		}
+		defaultList = list
+	})
+	return defaultList
+}
+
+// LoadWordsFile lee una lista propia con el mismo formato que data/words.txt.
+func LoadWordsFile(path string) (*WordList, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseWords(data)
+}
+
+// ParseWords lee líneas "<acción> <palabra o frase>"; las vacías y las que
+// empiezan con # se ignoran.
+func ParseWords(data []byte) (*WordList, error) {
+	list := &WordList{entries: map[string]Verdict{}}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		action, phrase, ok := strings.Cut(text, " ")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"<action> <word>\"", line)
+		}
+		verdict, err := ParseVerdict(action)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		words := Words(phrase)
+		if len(words) == 0 || len(words) > maxPhraseWords {
+			return nil, fmt.Errorf("line %d: phrase must have between 1 and %d words", line, maxPhraseWords)
+		}
+		key := strings.Join(words, " ")
+		if verdict > list.entries[key] {
+			list.entries[key] = verdict
+		}
+		if len(words) > list.longest {
+			list.longest = len(words)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (list *WordList) Len() int {
+	return len(list.entries)
+}
+
+// match busca la frase de la lista que empieza en words[start], la más larga
+// primero; una palabra suelta coincide también en plural.
+func (list *WordList) match(words []string, start int) (string, Verdict, int) {
+	for size := min(list.longest, len(words)-start); size > 0; size-- {
+		phrase := strings.Join(words[start:start+size], " ")
+		if verdict, ok := list.entries[phrase]; ok {
+			return phrase, verdict, size
+		}
+		if size == 1 {
+			for _, suffix := range []string{"s", "es"} {
+				singular := strings.TrimSuffix(phrase, suffix)
+				if singular == phrase {
+					continue
+				}
+				if verdict, ok := list.entries[singular]; ok {
+					return singular, verdict, 1
+				}
+			}
+		}
+	}
+	return "", Allow, 0
+}
+
+// WordRule busca en el texto las palabras de una lista.
+type WordRule struct {
+	list *WordList
+}
+
+func NewWordRule(list *WordList) *WordRule {
+	return &WordRule{list: list}
+}
+
+func (rule *WordRule) Name() string {
+	return "words"
+}
+
+func (rule *WordRule) Check(content Content) ([]Finding, error) {
+	return phraseFindings(rule.list.find(content.texts()), "contains blocked language"), nil
+}
+
+// find devuelve, por acción, la primera frase de la lista que aparece en
+// los textos.
+func (list *WordList) find(texts []string) map[Verdict]string {
+	found := map[Verdict]string{}
+	for _, text := range texts {
+		words := Words(text)
+		for i := 0; i < len(words); {
+			phrase, verdict, size := list.match(words, i)
+			if size == 0 {
+				i++
+				continue
+			}
+			if _, seen := found[verdict]; !seen {
+				found[verdict] = phrase
+			}
+			i += size
+		}
+	}
+	return found
+}
+
+// phraseFindings arma una entrada por acción: alcanza para decidir y no
+// repite en el caso todo lo que se encontró.
+func phraseFindings(found map[Verdict]string, reason string) []Finding {
+	findings := []Finding{}
+	for _, verdict := range []Verdict{Reject, Hold, Flag} {
+		if phrase, ok := found[verdict]; ok {
+			findings = append(findings, Finding{Verdict: verdict, Reason: fmt.Sprintf("%s (%q)", reason, phrase)})
+		}
+	}
+	return findings
+}
@@ -0,0 +1,59 @@
+package search
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RecipeTextIndexName es el nombre del índice de texto de la colección Recipe.
+// Mongo permite un solo índice de texto por colección, así que si se cambian
+// los campos o los pesos hay que borrar el índice anterior a mano.
+const RecipeTextIndexName = "recipe_text_search"
+
+// recipeTextWeights define cuánto aporta cada campo al puntaje de relevancia.
+// Un acierto en el título pesa mucho más que uno en la descripción de un paso.
+var recipeTextWeights = bson.D{
+	{Key: "title", Value: 10},
+	{Key: "tags", Value: 6},
+	{Key: "ingredients.name", Value: 4},
+	{Key: "description", Value: 2},
+	{Key: "step.title", Value: 1},
+	{Key: "step.descripcion", Value: 1},
+}
+
+// RecipeTextIndex devuelve la definición del índice de texto ponderado sobre
+// título, descripción, tags, nombres de ingredientes y texto de los pasos.
+func RecipeTextIndex() mongo.IndexModel {
+	keys := bson.D{}
+	for _, field := range recipeTextWeights {
+		keys = append(keys, bson.E{Key: field.Key, Value: "text"})
+	}
+
+	opts := options.Index().
+		SetName(RecipeTextIndexName).
+		SetWeights(recipeTextWeights).
+		SetDefaultLanguage("spanish").
+		// Evita que un campo "language" en el documento cambie el idioma del stemming
+		SetLanguageOverride("searchLanguage")
+
+	return mongo.IndexModel{Keys: keys, Options: opts}
+}
+
+// TextFilter devuelve el filtro $text para la consulta dada.
+func TextFilter(query Query) bson.M {
+	return bson.M{"$text": bson.M{"$search": query.TextSearch()}}
+}
+
+// ScoreProjection agrega el puntaje de relevancia al documento como "score".
+func ScoreProjection() bson.M {
+	return bson.M{"score": bson.M{"$meta": "textScore"}}
+}
+
+// ScoreSort ordena por relevancia y, ante empate, por valoración.
+func ScoreSort() bson.D {
+	return bson.D{
+		{Key: "score", Value: bson.M{"$meta": "textScore"}},
+		{Key: "averageRating", Value: -1},
+	}
+}
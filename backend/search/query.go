@@ -0,0 +1,126 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Query es la búsqueda del usuario ya separada en términos, frases exactas
+// y exclusiones. Nunca se pasa el texto crudo a Mongo: siempre se reconstruye
+// a partir de estas partes para que comillas o guiones sueltos no cambien el
+// significado de la consulta.
+type Query struct {
+	Terms           []string
+	Phrases         []string
+	ExcludedTerms   []string
+	ExcludedPhrases []string
+}
+
+// Parse interpreta una consulta de texto libre.
+//
+//	pasta "salsa de tomate" -picante -"sin gluten"
+//
+// devuelve Terms=[pasta], Phrases=[salsa de tomate], ExcludedTerms=[picante]
+// y ExcludedPhrases=[sin gluten]. Una comilla sin cerrar se toma como frase
+// hasta el final del texto.
+func Parse(raw string) Query {
+	var query Query
+	runes := []rune(raw)
+
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		negated := false
+		if runes[i] == '-' {
+			negated = true
+			i++
+			if i >= len(runes) || unicode.IsSpace(runes[i]) {
+				continue
+			}
+		}
+
+		if runes[i] == '"' {
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			phrase := normalizePhrase(string(runes[i+1 : end]))
+			i = end + 1
+			if phrase == "" {
+				continue
+			}
+			if negated {
+				query.ExcludedPhrases = append(query.ExcludedPhrases, phrase)
+			} else {
+				query.Phrases = append(query.Phrases, phrase)
+			}
+			continue
+		}
+
+		end := i
+		for end < len(runes) && !unicode.IsSpace(runes[end]) && runes[end] != '"' {
+			end++
+		}
+		term := cleanTerm(string(runes[i:end]))
+		i = end
+		if term == "" {
+			continue
+		}
+		if negated {
+			query.ExcludedTerms = append(query.ExcludedTerms, term)
+		} else {
+			query.Terms = append(query.Terms, term)
+		}
+	}
+
+	return query
+}
+
+// IsEmpty indica si la consulta no tiene nada positivo que buscar. Mongo no
+// acepta una búsqueda $text formada solo por exclusiones.
+func (query Query) IsEmpty() bool {
+	return len(query.Terms) == 0 && len(query.Phrases) == 0
+}
+
+// TextSearch arma el valor de $search para un filtro $text.
+func (query Query) TextSearch() string {
+	parts := make([]string, 0, len(query.Terms)+len(query.Phrases)+len(query.ExcludedTerms)+len(query.ExcludedPhrases))
+	for _, phrase := range query.Phrases {
+		parts = append(parts, `"`+phrase+`"`)
+	}
+	parts = append(parts, query.Terms...)
+	for _, term := range query.ExcludedTerms {
+		parts = append(parts, "-"+term)
+	}
+	for _, phrase := range query.ExcludedPhrases {
+		parts = append(parts, `-"`+phrase+`"`)
+	}
+	return strings.Join(parts, " ")
+}
+
+// PrefixTerm devuelve el único término de la consulta cuando esta es una sola
+// palabra sin frases ni exclusiones. Se usa para el autocompletado, donde el
+// usuario todavía no terminó de escribir la palabra y $text no encuentra nada.
+func (query Query) PrefixTerm() (string, bool) {
+	if len(query.Terms) != 1 || len(query.Phrases) != 0 || len(query.ExcludedTerms) != 0 || len(query.ExcludedPhrases) != 0 {
+		return "", false
+	}
+	return query.Terms[0], true
+}
+
+// cleanTerm quita los caracteres que Mongo interpreta dentro de $search
+// (comillas y guiones iniciales) y la puntuación de los bordes.
+func cleanTerm(term string) string {
+	term = strings.ReplaceAll(term, `"`, "")
+	term = strings.TrimLeft(term, "-")
+	return strings.TrimFunc(term, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func normalizePhrase(phrase string) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(phrase, `"`, "")), " ")
+}
@@ -0,0 +1,323 @@
+package services
+
+import (
+	"burned/backend/dtos"
+	"burned/backend/models"
+	"burned/backend/pagination"
+	"burned/backend/repositories"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxCollectionItems acota el tamaño de una colección; los ítems viven dentro
+// del documento y el detalle los devuelve todos.
+const maxCollectionItems = 500
+
+type CollectionServiceInterface interface {
+	CreateCollection(collection dtos.CollectionRequest, userId string) (dtos.CollectionResponse, error)
+	UpdateCollection(id string, collection dtos.CollectionRequest, userId string) (dtos.CollectionResponse, error)
+	DeleteCollection(id string, userId string) error
+	GetCollection(id string, requesterId string) (dtos.CollectionResponse, error)
+	GetSharedCollection(token string, requesterId string) (dtos.CollectionResponse, error)
+	GetCollectionsByUser(userId string, requesterId string, page pagination.Request) (pagination.Page[dtos.CollectionResponse], error)
+	AddRecipe(id string, recipeId string, userId string) (dtos.CollectionResponse, error)
+	RemoveRecipe(id string, recipeId string, userId string) error
+	ReorderRecipes(id string, recipeIds []string, userId string) (dtos.CollectionResponse, error)
+	RotateShareToken(id string, userId string) (dtos.ShareLinkResponse, error)
+}
+
+type CollectionService struct {
+	collectionRepo  repositories.RecipeCollectionRepositoryInterface
+	recipeRepo      repositories.RecipeRepositoryInterface
+	savedRecipeRepo repositories.SavedRecipeRepositoryInterface
+	userRepo        repositories.UserRepositoryInterface
+}
+
+func NewCollectionService(collectionRepo repositories.RecipeCollectionRepositoryInterface, recipeRepo repositories.RecipeRepositoryInterface, savedRecipeRepo repositories.SavedRecipeRepositoryInterface, userRepo repositories.UserRepositoryInterface) *CollectionService {
+	return &CollectionService{collectionRepo: collectionRepo, recipeRepo: recipeRepo, savedRecipeRepo: savedRecipeRepo, userRepo: userRepo}
+}
+
+func (service *CollectionService) CreateCollection(collection dtos.CollectionRequest, userId string) (dtos.CollectionResponse, error) {
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return dtos.CollectionResponse{}, errors.New("invalid id")
+	}
+	now := time.Now()
+	created, err := service.collectionRepo.CreateCollection(models.RecipeCollection{
+		UserID:      userOid,
+		Name:        collection.Name,
+		Description: collection.Description,
+		CoverImage:  collection.CoverImage,
+		Visibility:  collection.Visibility,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	if err != nil {
+		return dtos.CollectionResponse{}, err
+	}
+	return service.detail(created, userOid), nil
+}
+
+func (service *CollectionService) UpdateCollection(id string, collection dtos.CollectionRequest, userId string) (dtos.CollectionResponse, error) {
+	current, userOid, err := service.owned(id, userId)
+	if err != nil {
+		return dtos.CollectionResponse{}, err
+	}
+	//la colección "Saved" conserva su nombre
+	if !current.IsDefault {
+		current.Name = collection.Name
+	}
+	current.Description = collection.Description
+	current.CoverImage = collection.CoverImage
+	current.Visibility = collection.Visibility
+	current.UpdatedAt = time.Now()
+	if err := service.collectionRepo.UpdateCollection(current); err != nil {
+		return dtos.CollectionResponse{}, err
+	}
+	return service.detail(current, userOid), nil
+}
+
+func (service *CollectionService) DeleteCollection(id string, userId string) error {
+	current, _, err := service.owned(id, userId)
+	if err != nil {
+		return err
+	}
+	if current.IsDefault {
+		return errors.New("the default collection cannot be deleted")
+	}
+	deleted, err := service.collectionRepo.DeleteCollection(current.ID)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return errors.New("collection not found")
+	}
+	return nil
+}
+
+// GetCollection devuelve la colección a su dueño o, si es pública, a cualquiera.
+// Las no listadas solo se ven con el link compartido.
+func (service *CollectionService) GetCollection(id string, requesterId string) (dtos.CollectionResponse, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return dtos.CollectionResponse{}, errors.New("invalid id")
+	}
+	collection, err := service.collectionRepo.GetCollectionById(oid)
+	if err != nil {
+		return dtos.CollectionResponse{}, errors.New("collection not found")
+	}
+	requester, _ := primitive.ObjectIDFromHex(requesterId)
+	if collection.UserID != requester && collection.Visibility != "public" {
+		return dtos.CollectionResponse{}, errors.New("collection not found")
+	}
+	return service.detail(collection, requester), nil
+}
+
+func (service *CollectionService) GetSharedCollection(token string, requesterId string) (dtos.CollectionResponse, error) {
+	collection, err := service.collectionRepo.GetCollectionByShareToken(token)
+	if err != nil {
+		return dtos.CollectionResponse{}, errors.New("collection not found")
+	}
+	requester, _ := primitive.ObjectIDFromHex(requesterId)
+	if collection.UserID != requester && collection.Visibility == "private" {
+		return dtos.CollectionResponse{}, errors.New("collection not found")
+	}
+	return service.detail(collection, requester), nil
+}
+
+// GetCollectionsByUser lista todas las colecciones si las pide su dueño y
+// solo las públicas para cualquier otro.
+func (service *CollectionService) GetCollectionsByUser(userId string, requesterId string, page pagination.Request) (pagination.Page[dtos.CollectionResponse], error) {
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return pagination.Page[dtos.CollectionResponse]{}, errors.New("invalid id")
+	}
+	isOwner := userId == requesterId
+	result, err := service.collectionRepo.GetCollectionsByUserPaged(userOid, !isOwner, page)
+	if err != nil {
+		if pagination.IsRequestError(err) {
+			return pagination.Page[dtos.CollectionResponse]{}, err
+		}
+		return pagination.Page[dtos.CollectionResponse]{}, errors.New("collections not found")
+	}
+	userName := service.userName(userOid)
+	return pagination.Map(result, func(collection models.RecipeCollection) dtos.CollectionResponse {
+		response := dtos.CollectionModelToResponse(collection)
+		response.UserName = userName
+		if isOwner {
+			response.ShareToken = collection.ShareToken
+		}
+		return response
+	}), nil
+}
+
+// AddRecipe agrega una receta visible para el usuario. Agregar a "Saved"
+// equivale a guardarla, así que también actualiza SavedRecipes.
+func (service *CollectionService) AddRecipe(id string, recipeId string, userId string) (dtos.CollectionResponse, error) {
+	current, userOid, err := service.owned(id, userId)
+	if err != nil {
+		return dtos.CollectionResponse{}, err
+	}
+	recipeOid, err := primitive.ObjectIDFromHex(recipeId)
+	if err != nil {
+		return dtos.CollectionResponse{}, errors.New("invalid recipe ID")
+	}
+	recipe, err := service.recipeRepo.GetRecipeById(recipeOid)
+	if err != nil || !visibleTo(recipe, userOid) {
+		return dtos.CollectionResponse{}, errors.New("recipe not found")
+	}
+	if len(current.Items) >= maxCollectionItems {
+		return dtos.CollectionResponse{}, errors.New("collection is full")
+	}
+
+	if current.IsDefault {
+		saved, err := service.savedRecipeRepo.GetSavedRecipesSavedByUserAndRecipe(userOid, recipeOid)
+		if err != nil {
+			return dtos.CollectionResponse{}, err
+		}
+		if len(saved) == 0 {
+			if _, err := service.savedRecipeRepo.SavedRecipe(models.SavedRecipe{UserID: userOid, RecipeID: recipeOid, CreatedAt: time.Now()}); err != nil {
+				return dtos.CollectionResponse{}, err
+			}
+		}
+	}
+	added, err := service.collectionRepo.AddItem(current.ID, models.CollectionItem{RecipeID: recipeOid, AddedAt: time.Now()})
+	if err != nil {
+		return dtos.CollectionResponse{}, err
+	}
+	if !added {
+		return dtos.CollectionResponse{}, errors.New("recipe already in collection")
+	}
+	return service.GetCollection(id, userId)
+}
+
+func (service *CollectionService) RemoveRecipe(id string, recipeId string, userId string) error {
+	current, userOid, err := service.owned(id, userId)
+	if err != nil {
+		return err
+	}
+	recipeOid, err := primitive.ObjectIDFromHex(recipeId)
+	if err != nil {
+		return errors.New("invalid recipe ID")
+	}
+	removed, err := service.collectionRepo.RemoveItem(current.ID, recipeOid)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return errors.New("recipe not in collection")
+	}
+	if current.IsDefault {
+		if _, err := service.savedRecipeRepo.UnsavedRecipe(userOid, recipeOid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReorderRecipes recibe todos los ids de la colección en el orden deseado.
+func (service *CollectionService) ReorderRecipes(id string, recipeIds []string, userId string) (dtos.CollectionResponse, error) {
+	current, _, err := service.owned(id, userId)
+	if err != nil {
+		return dtos.CollectionResponse{}, err
+	}
+	if len(recipeIds) != len(current.Items) {
+		return dtos.CollectionResponse{}, errors.New("order must list every recipe in the collection exactly once")
+	}
+	byRecipe := make(map[string]models.CollectionItem, len(current.Items))
+	for _, item := range current.Items {
+		byRecipe[item.RecipeID.Hex()] = item
+	}
+	items := make([]models.CollectionItem, 0, len(recipeIds))
+	for _, recipeId := range recipeIds {
+		item, ok := byRecipe[recipeId]
+		if !ok {
+			return dtos.CollectionResponse{}, errors.New("order must list every recipe in the collection exactly once")
+		}
+		delete(byRecipe, recipeId)
+		items = append(items, item)
+	}
+	if err := service.collectionRepo.SetItems(current.ID, items); err != nil {
+		return dtos.CollectionResponse{}, err
+	}
+	return service.GetCollection(id, userId)
+}
+
+// RotateShareToken genera un link nuevo; el anterior deja de funcionar.
+func (service *CollectionService) RotateShareToken(id string, userId string) (dtos.ShareLinkResponse, error) {
+	current, _, err := service.owned(id, userId)
+	if err != nil {
+		return dtos.ShareLinkResponse{}, err
+	}
+	token, err := service.collectionRepo.RotateShareToken(current.ID)
+	if err != nil {
+		return dtos.ShareLinkResponse{}, err
+	}
+	return dtos.ShareLinkResponse{Token: token}, nil
+}
+
+// owned carga una colección verificando que pertenezca al usuario.
+func (service *CollectionService) owned(id string, userId string) (models.RecipeCollection, primitive.ObjectID, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.RecipeCollection{}, primitive.NilObjectID, errors.New("invalid id")
+	}
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return models.RecipeCollection{}, primitive.NilObjectID, errors.New("invalid id")
+	}
+	collection, err := service.collectionRepo.GetCollectionById(oid)
+	if err != nil || collection.UserID != userOid {
+		return models.RecipeCollection{}, primitive.NilObjectID, errors.New("collection not found")
+	}
+	return collection, userOid, nil
+}
+
+// detail arma la respuesta completa con las recetas en el orden de la
+// colección, omitiendo las que quien consulta no puede ver.
+func (service *CollectionService) detail(collection models.RecipeCollection, requester primitive.ObjectID) dtos.CollectionResponse {
+	response := dtos.CollectionModelToResponse(collection)
+	response.UserName = service.userName(collection.UserID)
+	if collection.UserID == requester {
+		response.ShareToken = collection.ShareToken
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(collection.Items))
+	for _, item := range collection.Items {
+		ids = append(ids, item.RecipeID)
+	}
+	recipes, err := service.recipeRepo.GetRecipesByIds(ids)
+	if err != nil {
+		recipes = nil
+	}
+	byId := make(map[primitive.ObjectID]models.Recipe, len(recipes))
+	for _, recipe := range recipes {
+		byId[recipe.ID] = recipe
+	}
+
+	names := map[primitive.ObjectID]string{}
+	response.Items = []dtos.CollectionItemResponse{}
+	for _, item := range collection.Items {
+		recipe, ok := byId[item.RecipeID]
+		if !ok || !visibleTo(recipe, requester) {
+			continue
+		}
+		recipeResponse := dtos.RecipeModelToResponse(recipe)
+		if _, ok := names[recipe.UserID]; !ok {
+			names[recipe.UserID] = service.userName(recipe.UserID)
+		}
+		recipeResponse.UserName = names[recipe.UserID]
+		response.Items = append(response.Items, dtos.CollectionItemResponse{AddedAt: item.AddedAt, Recipe: recipeResponse})
+	}
+	response.ItemCount = len(response.Items)
+	return response
+}
+
+func (service *CollectionService) userName(userId primitive.ObjectID) string {
+	if user, err := service.userRepo.GetUserById(userId); err == nil {
+		return user.Name
+	}
+	return "Unknown"
+}
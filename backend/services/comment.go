@@ -3,9 +3,12 @@ package services
 import (
 	"burned/backend/dtos"
 	"burned/backend/models"
+	"burned/backend/pagination"
 	"burned/backend/repositories"
+	"burned/backend/screening"
 	"errors"
-	"fmt"
+	"log"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -14,18 +17,23 @@ import (
 type CommentServiceInterface interface {
 	CreateComment(comment dtos.CommentRequest, idUser string) (dtos.CommentResponse, error)
 	DeleteComment(commentId string, requesterId string, requesterRole string) error
-	GetCommentsByRecipe(recipeId string) ([]dtos.CommentResponse, error)
+	GetCommentsByRecipe(recipeId string, page pagination.Request) (pagination.Page[dtos.CommentResponse], error)
 	GetCommentById(Id string) (dtos.CommentResponse, error)
+	GetReplies(commentId string, page pagination.Request) (pagination.Page[dtos.CommentResponse], error)
+	UpdateComment(commentId string, request dtos.CommentUpdateRequest, requesterId string) (dtos.CommentResponse, error)
+	GetCommentHistory(commentId string) (dtos.CommentHistoryResponse, error)
 }
 
 type CommentService struct {
-	commentRepo repositories.CommentRepositoryInterface
-	userRepo    repositories.UserRepositoryInterface
-	recipeRepo  repositories.RecipeRepositoryInterface
+	commentRepo      repositories.CommentRepositoryInterface
+	userRepo         repositories.UserRepositoryInterface
+	recipeRepo       repositories.RecipeRepositoryInterface
+	screeningService ScreeningServiceInterface
+	feedService      FeedServiceInterface
 }
 
-func NewCommentService(repo repositories.CommentRepositoryInterface, userRepo repositories.UserRepositoryInterface, recipeRepo repositories.RecipeRepositoryInterface) *CommentService {
-	return &CommentService{commentRepo: repo, userRepo: userRepo, recipeRepo: recipeRepo}
+func NewCommentService(repo repositories.CommentRepositoryInterface, userRepo repositories.UserRepositoryInterface, recipeRepo repositories.RecipeRepositoryInterface, screeningService ScreeningServiceInterface, feedService FeedServiceInterface) *CommentService {
+	return &CommentService{commentRepo: repo, userRepo: userRepo, recipeRepo: recipeRepo, screeningService: screeningService, feedService: feedService}
 }
 
 func (service *CommentService) CreateComment(comment dtos.CommentRequest, idUser string) (dtos.CommentResponse, error) {
@@ -37,52 +45,93 @@ func (service *CommentService) CreateComment(comment dtos.CommentRequest, idUser
 	if err != nil {
 		return dtos.CommentResponse{}, errors.New("user not found")
 	}
-	recipeOid, err := primitive.ObjectIDFromHex(comment.RecipeID)
-	if err != nil {
-		return dtos.CommentResponse{}, errors.New("invalid id")
-	}
-
 	var model models.Comment
+	if comment.ParentID != "" {
+		//una respuesta va en la receta de su comentario padre, un nivel más abajo
+		parentOid, err := primitive.ObjectIDFromHex(comment.ParentID)
+		if err != nil {
+			return dtos.CommentResponse{}, errors.New("invalid id")
+		}
+		parent, err := service.commentRepo.GetCommentById(parentOid)
+		if err != nil {
+			return dtos.CommentResponse{}, errors.New("parent comment not found")
+		}
+		if comment.RecipeID != "" && comment.RecipeID != parent.RecipeID.Hex() {
+			return dtos.CommentResponse{}, errors.New("invalid parent, it belongs to another recipe")
+		}
+		if parent.Deleted {
+			return dtos.CommentResponse{}, errors.New("invalid parent, the comment was deleted")
+		}
+		if parent.Depth >= models.MaxCommentDepth {
+			return dtos.CommentResponse{}, errors.New("invalid parent, maximum reply depth reached")
+		}
+		model.RecipeID = parent.RecipeID
+		model.ParentID = &parent.ID
+		model.Depth = parent.Depth + 1
+	} else {
+		recipeOid, err := primitive.ObjectIDFromHex(comment.RecipeID)
+		if err != nil {
+			return dtos.CommentResponse{}, errors.New("invalid id")
+		}
+		model.RecipeID = recipeOid
+	}
+	//solo se comenta lo que se puede ver; si no, el comentario llegaría a los
+	//feeds de los seguidores apuntando a una receta privada, oculta o inexistente
+	recipe, err := service.recipeRepo.GetRecipeById(model.RecipeID)
+	if err != nil || !visibleTo(recipe, userOid) {
+		return dtos.CommentResponse{}, errors.New("recipe not found")
+	}
 	model.CreatedAt = time.Now()
-	model.RecipeID = recipeOid
 	model.UserID = userOid
 	model.UserName = user.Name
 	model.Text = comment.Text
 
+	//moderación automática: lo rechazado no se guarda y lo retenido se guarda oculto
+	content := screening.Content{Kind: models.TargetComment, AuthorID: userOid, Body: model.Text}
+	screened, err := service.screeningService.Screen(content)
+	if err != nil {
+		return dtos.CommentResponse{}, err
+	}
+	model.Hidden = screened.Verdict == screening.Hold
+
 	result, err := service.commentRepo.CreateComment(model)
 	if err != nil {
 		return dtos.CommentResponse{}, errors.New("internal server error")
 	}
+	if model.ParentID != nil {
+		if _, err := service.commentRepo.AddReplyCount(*model.ParentID, 1); err != nil {
+			return dtos.CommentResponse{}, errors.New("internal server error")
+		}
+	}
 
 	response := dtos.CommentModelToResponse(model)
 	insertedOid, ok := result.InsertedID.(primitive.ObjectID)
 	if !ok {
 		return dtos.CommentResponse{}, errors.New("invalid id")
 	}
+	content.ID = insertedOid
+	service.screeningService.Record(content, model.RecipeID, screened)
+	model.ID = insertedOid
+	service.feedService.PublishComment(model)
 	response.ID = insertedOid.Hex()
 	return response, nil
 }
 func (service *CommentService) DeleteComment(commentId string, requesterId string, requesterRole string) error {
-	fmt.Printf("DEBUG: Iniciando borrado. ID Comentario: %s, Solicitante: %s, Rol: %s\n", commentId, requesterId, requesterRole)
 	commentOid, err := primitive.ObjectIDFromHex(commentId)
 	if err != nil {
-		fmt.Println("DEBUG ERROR: Fallo al convertir commentId a hex")
 		return errors.New("invalid id")
 	}
 	userOid, err := primitive.ObjectIDFromHex(requesterId)
 	if err != nil {
-		fmt.Println("DEBUG ERROR: Fallo al convertir requesterId a hex")
 		return errors.New("invalid id")
 	}
 	comment, err := service.commentRepo.GetCommentById(commentOid)
-	if err != nil {
-		fmt.Printf("DEBUG ERROR: Comentario no encontrado en DB: %v\n", err)
+	if err != nil || comment.Deleted {
 		return errors.New("comment not found")
 	}
 
 	recipe, err := service.recipeRepo.GetRecipeById(comment.RecipeID)
 	if err != nil {
-		fmt.Printf("DEBUG ERROR: Receta %s no encontrada: %v\n", comment.RecipeID.Hex(), err)
 		return errors.New("associated recipe not found")
 	}
 
@@ -91,33 +140,63 @@ func (service *CommentService) DeleteComment(commentId string, requesterId strin
 	isRecipeOwner := recipe.UserID == userOid
 
 	if !isAdmin && !isOwner && !isRecipeOwner {
-		fmt.Printf("DEBUG: Permisos -> EsDueñoComentario: %t, EsAdmin: %t, EsDueñoReceta: %t\n", isOwner, isAdmin, isRecipeOwner)
 		return errors.New("unauthorized to delete this comment")
 	}
 
+	//con respuestas queda una lápida "[deleted]" para no romper el hilo
+	if comment.ReplyCount > 0 {
+		if err := service.commentRepo.TombstoneComment(commentOid); err != nil {
+			return errors.New("could not delete comment")
+		}
+		service.deleteEdits(commentOid)
+		return nil
+	}
 	result, err := service.commentRepo.DeleteComment(commentOid)
 	if err != nil || result.DeletedCount == 0 {
-		fmt.Println("DEBUG ERROR: Mongo dijo que borró 0 documentos")
 		return errors.New("could not delete comment")
 	}
+	service.deleteEdits(commentOid)
+	service.releaseParent(comment.ParentID)
 	return nil
 }
-func (service *CommentService) GetCommentsByRecipe(recipeId string) ([]dtos.CommentResponse, error) {
+
+// deleteEdits borra las versiones anteriores de un comentario borrado. Si
+// falla solo queda historial huérfano, así que no se corta el borrado.
+func (service *CommentService) deleteEdits(commentId primitive.ObjectID) {
+	if err := service.commentRepo.DeleteCommentEdits(commentId); err != nil {
+		log.Println("⚠️ Aviso: No se pudo borrar el historial del comentario", commentId.Hex(), err)
+	}
+}
+
+// releaseParent descuenta la respuesta borrada del padre. Si el padre era
+// una lápida y se quedó sin respuestas ya no sostiene nada y se borra
+// también, repitiendo hacia arriba.
+func (service *CommentService) releaseParent(parentId *primitive.ObjectID) {
+	for parentId != nil {
+		parent, err := service.commentRepo.AddReplyCount(*parentId, -1)
+		if err != nil || !parent.Deleted || parent.ReplyCount > 0 {
+			return
+		}
+		if _, err := service.commentRepo.DeleteComment(parent.ID); err != nil {
+			return
+		}
+		service.deleteEdits(parent.ID)
+		parentId = parent.ParentID
+	}
+}
+func (service *CommentService) GetCommentsByRecipe(recipeId string, page pagination.Request) (pagination.Page[dtos.CommentResponse], error) {
 	commentOid, err := primitive.ObjectIDFromHex(recipeId)
 	if err != nil {
-		return []dtos.CommentResponse{}, errors.New("invalid id")
+		return pagination.Page[dtos.CommentResponse]{}, errors.New("invalid id")
 	}
-	result, err := service.commentRepo.GetCommentsByRecipe(commentOid)
+	result, err := service.commentRepo.GetCommentsByRecipePaged(commentOid, page)
 	if err != nil {
-		return []dtos.CommentResponse{}, errors.New("internal server error")
+		if pagination.IsRequestError(err) {
+			return pagination.Page[dtos.CommentResponse]{}, err
+		}
+		return pagination.Page[dtos.CommentResponse]{}, errors.New("internal server error")
 	}
-
-	var comments []dtos.CommentResponse
-	for _, comment := range result {
-		comments = append(comments, dtos.CommentModelToResponse(comment))
-	}
-	return comments, nil
-
+	return pagination.Map(result, publicComment), nil
 }
 
 func (service *CommentService) GetCommentById(Id string) (dtos.CommentResponse, error) {
@@ -129,5 +208,126 @@ func (service *CommentService) GetCommentById(Id string) (dtos.CommentResponse,
 	if err != nil {
 		return dtos.CommentResponse{}, errors.New("internal server error")
 	}
-	return dtos.CommentModelToResponse(result), nil
+	return publicComment(result), nil
+}
+
+// GetReplies lista las respuestas directas de un comentario; las de niveles
+// más profundos se piden a su vez con el id de cada respuesta.
+func (service *CommentService) GetReplies(commentId string, page pagination.Request) (pagination.Page[dtos.CommentResponse], error) {
+	commentOid, err := primitive.ObjectIDFromHex(commentId)
+	if err != nil {
+		return pagination.Page[dtos.CommentResponse]{}, errors.New("invalid id")
+	}
+	if _, err := service.commentRepo.GetCommentById(commentOid); err != nil {
+		return pagination.Page[dtos.CommentResponse]{}, errors.New("comment not found")
+	}
+	result, err := service.commentRepo.GetRepliesPaged(commentOid, page)
+	if err != nil {
+		if pagination.IsRequestError(err) {
+			return pagination.Page[dtos.CommentResponse]{}, err
+		}
+		return pagination.Page[dtos.CommentResponse]{}, errors.New("internal server error")
+	}
+	return pagination.Map(result, publicComment), nil
+}
+
+// UpdateComment cambia el texto de un comentario; solo puede hacerlo quien lo
+// escribió. El texto anterior se guarda como versión para los admins.
+func (service *CommentService) UpdateComment(commentId string, request dtos.CommentUpdateRequest, requesterId string) (dtos.CommentResponse, error) {
+	commentOid, err := primitive.ObjectIDFromHex(commentId)
+	if err != nil {
+		return dtos.CommentResponse{}, errors.New("invalid id")
+	}
+	userOid, err := primitive.ObjectIDFromHex(requesterId)
+	if err != nil {
+		return dtos.CommentResponse{}, errors.New("invalid id")
+	}
+	text := strings.TrimSpace(request.Text)
+	if text == "" {
+		return dtos.CommentResponse{}, errors.New("invalid text, it cannot be empty")
+	}
+	comment, err := service.commentRepo.GetCommentById(commentOid)
+	if err != nil || comment.Deleted {
+		return dtos.CommentResponse{}, errors.New("comment not found")
+	}
+	if comment.UserID != userOid {
+		return dtos.CommentResponse{}, errors.New("unauthorized to edit this comment")
+	}
+	if text == comment.Text {
+		return dtos.CommentModelToResponse(comment), nil
+	}
+	content := screening.Content{Kind: models.TargetComment, ID: comment.ID, AuthorID: userOid, Body: text}
+	screened, err := service.screeningService.Screen(content)
+	if err != nil {
+		return dtos.CommentResponse{}, err
+	}
+
+	now := time.Now()
+	writtenAt := comment.CreatedAt
+	if comment.EditedAt != nil {
+		writtenAt = *comment.EditedAt
+	}
+	edit := models.CommentEdit{
+		CommentID:  comment.ID,
+		RecipeID:   comment.RecipeID,
+		Text:       comment.Text,
+		WrittenAt:  writtenAt,
+		ReplacedAt: now,
+	}
+	if err := service.commentRepo.SaveCommentEdit(edit); err != nil {
+		return dtos.CommentResponse{}, errors.New("internal server error")
+	}
+	if err := service.commentRepo.UpdateCommentText(commentOid, text, now); err != nil {
+		return dtos.CommentResponse{}, errors.New("internal server error")
+	}
+	//una edición retenida oculta el comentario; una limpia no lo vuelve a
+	//mostrar, eso lo decide un moderador
+	if screened.Verdict == screening.Hold && !comment.Hidden {
+		if err := service.commentRepo.SetCommentHidden(commentOid, true); err != nil {
+			return dtos.CommentResponse{}, errors.New("internal server error")
+		}
+		comment.Hidden = true
+	}
+	service.screeningService.Record(content, comment.RecipeID, screened)
+	comment.Text = text
+	comment.EditedAt = &now
+	return dtos.CommentModelToResponse(comment), nil
+}
+
+// GetCommentHistory devuelve el comentario con sus versiones anteriores.
+func (service *CommentService) GetCommentHistory(commentId string) (dtos.CommentHistoryResponse, error) {
+	commentOid, err := primitive.ObjectIDFromHex(commentId)
+	if err != nil {
+		return dtos.CommentHistoryResponse{}, errors.New("invalid id")
+	}
+	comment, err := service.commentRepo.GetCommentById(commentOid)
+	if err != nil {
+		return dtos.CommentHistoryResponse{}, errors.New("comment not found")
+	}
+	edits, err := service.commentRepo.GetCommentEdits(commentOid)
+	if err != nil {
+		return dtos.CommentHistoryResponse{}, errors.New("internal server error")
+	}
+	response := dtos.CommentHistoryResponse{
+		Comment:  dtos.CommentModelToResponse(comment),
+		Versions: make([]dtos.CommentVersionResponse, 0, len(edits)),
+	}
+	for _, edit := range edits {
+		response.Versions = append(response.Versions, dtos.CommentVersionResponse{
+			Text:       edit.Text,
+			WrittenAt:  edit.WrittenAt,
+			ReplacedAt: edit.ReplacedAt,
+		})
+	}
+	return response, nil
+}
+
+// publicComment es el comentario como lo ven los demás: si moderación lo
+// ocultó queda en el hilo pero sin su texto.
+func publicComment(model models.Comment) dtos.CommentResponse {
+	response := dtos.CommentModelToResponse(model)
+	if model.Hidden {
+		response.Text = models.HiddenCommentText
+	}
+	return response
 }
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"burned/backend/database"
+	"burned/backend/dtos"
+	"burned/backend/repositories"
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type DeletionServiceInterface interface {
+	DeleteRecipe(recipeId primitive.ObjectID, dryRun bool) (dtos.DeletionReport, error)
+	DeleteUser(userId primitive.ObjectID, dryRun bool) (dtos.DeletionReport, error)
+}
+
+// RecipeCascade es una colección cuyos documentos dependen de una receta y
+// se borran junto con ella.
+type RecipeCascade struct {
+	Name   string
+	Count  func(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error)
+	Delete func(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error)
+}
+
+// UserCascade es una colección con datos del usuario en contenido ajeno.
+// Count recibe las recetas propias del usuario para no contar lo que ya se
+// borra con ellas. Si Anonymize es true los documentos se conservan sin autor.
+type UserCascade struct {
+	Name      string
+	Anonymize bool
+	Count     func(ctx context.Context, userId primitive.ObjectID, ownRecipes []primitive.ObjectID) (int64, error)
+	Apply     func(ctx context.Context, userId primitive.ObjectID) (int64, error)
+}
+
+type DeletionService struct {
+	transactor     database.Transactor
+	userRepo       repositories.UserRepositoryInterface
+	recipeRepo     repositories.RecipeRepositoryInterface
+	recipeCascades []RecipeCascade
+	userCascades   []UserCascade
+}
+
+func NewDeletionService(transactor database.Transactor, userRepo repositories.UserRepositoryInterface, recipeRepo repositories.RecipeRepositoryInterface, commentRepo repositories.CommentRepositoryInterface, ratingRepo repositories.RatingRepositoryInterface, savedRecipeRepo repositories.SavedRecipeRepositoryInterface) *DeletionService {
+	service := &DeletionService{transactor: transactor, userRepo: userRepo, recipeRepo: recipeRepo}
+
+	service.AddRecipeCascade(RecipeCascade{Name: "Comment", Count: commentRepo.CountCommentsByRecipes, Delete: commentRepo.DeleteCommentsByRecipes})
+	service.AddRecipeCascade(RecipeCascade{Name: "Rating", Count: ratingRepo.CountRatingsByRecipes, Delete: ratingRepo.DeleteRatingsByRecipes})
+	service.AddRecipeCascade(RecipeCascade{Name: "SavedRecipes", Count: savedRecipeRepo.CountSavedByRecipes, Delete: savedRecipeRepo.DeleteSavedByRecipes})
+
+	//los comentarios en recetas ajenas se conservan para no romper la conversación
+	service.AddUserCascade(UserCascade{Name: "Comment", Anonymize: true, Count: commentRepo.CountCommentsByUser, Apply: commentRepo.AnonymizeCommentsByUser})
+	service.AddUserCascade(UserCascade{Name: "Rating", Count: ratingRepo.CountRatingsByUser, Apply: ratingRepo.DeleteRatingsByUser})
+	service.AddUserCascade(UserCascade{Name: "SavedRecipes", Count: savedRecipeRepo.CountSavedByUser, Apply: savedRecipeRepo.DeleteSavedByUser})
+	return service
+}
+
+// AddRecipeCascade registra otra colección que depende de las recetas.
+func (service *DeletionService) AddRecipeCascade(cascade RecipeCascade) {
+	service.recipeCascades = append(service.recipeCascades, cascade)
+}
+
+// AddUserCascade registra otra colección que depende de los usuarios.
+func (service *DeletionService) AddUserCascade(cascade UserCascade) {
+	service.userCascades = append(service.userCascades, cascade)
+}
+
+func (service *DeletionService) DeleteRecipe(recipeId primitive.ObjectID, dryRun bool) (dtos.DeletionReport, error) {
+	report := dtos.NewDeletionReport(dryRun)
+	if _, err := service.recipeRepo.GetRecipeById(recipeId); err != nil {
+		return report, errors.New("recipe not found")
+	}
+
+	recipeIds := []primitive.ObjectID{recipeId}
+	if dryRun {
+		report.Removed["Recipe"] = 1
+		err := service.countRecipeCascades(context.Background(), recipeIds, &report)
+		return report, err
+	}
+
+	err := service.transactor.WithTransaction(context.Background(), func(ctx context.Context) error {
+		report = dtos.NewDeletionReport(false)
+		return service.deleteRecipes(ctx, recipeIds, &report)
+	})
+	if err != nil {
+		return dtos.DeletionReport{}, err
+	}
+	if report.Removed["Recipe"] == 0 {
+		return dtos.DeletionReport{}, errors.New("recipe not found")
+	}
+	return report, nil
+}
+
+func (service *DeletionService) DeleteUser(userId primitive.ObjectID, dryRun bool) (dtos.DeletionReport, error) {
+	report := dtos.NewDeletionReport(dryRun)
+	if _, err := service.userRepo.GetUserById(userId); err != nil {
+		return report, errors.New("user not found")
+	}
+
+	if dryRun {
+		ctx := context.Background()
+		ownRecipes, err := service.recipeRepo.GetRecipeIdsByUser(ctx, userId)
+		if err != nil {
+			return report, err
+		}
+		report.Removed["User"] = 1
+		report.Removed["Recipe"] = int64(len(ownRecipes))
+		if err := service.countRecipeCascades(ctx, ownRecipes, &report); err != nil {
+			return report, err
+		}
+		for _, cascade := range service.userCascades {
+			count, err := cascade.Count(ctx, userId, ownRecipes)
+			if err != nil {
+				return report, err
+			}
+			report.Add(cascade.Name, count, cascade.Anonymize)
+		}
+		return report, nil
+	}
+
+	err := service.transactor.WithTransaction(context.Background(), func(ctx context.Context) error {
+		//la transacción puede reintentarse, así que el reporte se arma de cero
+		report = dtos.NewDeletionReport(false)
+
+		ownRecipes, err := service.recipeRepo.GetRecipeIdsByUser(ctx, userId)
+		if err != nil {
+			return err
+		}
+		if err := service.deleteRecipes(ctx, ownRecipes, &report); err != nil {
+			return err
+		}
+		for _, cascade := range service.userCascades {
+			count, err := cascade.Apply(ctx, userId)
+			if err != nil {
+				return err
+			}
+			report.Add(cascade.Name, count, cascade.Anonymize)
+		}
+
+		result, err := service.userRepo.DeleteUserWithContext(ctx, userId)
+		if err != nil {
+			return err
+		}
+		if result.DeletedCount == 0 {
+			return errors.New("user not found")
+		}
+		report.Removed["User"] = result.DeletedCount
+		return nil
+	})
+	if err != nil {
+		return dtos.DeletionReport{}, err
+	}
+	return report, nil
+}
+
+// deleteRecipes borra primero los dependientes y al final las recetas, de
+// modo que si algo falla sin transacción no queden huérfanos sin receta.
+func (service *DeletionService) deleteRecipes(ctx context.Context, recipeIds []primitive.ObjectID, report *dtos.DeletionReport) error {
+	if len(recipeIds) == 0 {
+		return nil
+	}
+	for _, cascade := range service.recipeCascades {
+		count, err := cascade.Delete(ctx, recipeIds)
+		if err != nil {
+			return err
+		}
+		report.Removed[cascade.Name] += count
+	}
+	deleted, err := service.recipeRepo.DeleteRecipesByIds(ctx, recipeIds)
+	if err != nil {
+		return err
+	}
+	report.Removed["Recipe"] += deleted
+	return nil
+}
+
+func (service *DeletionService) countRecipeCascades(ctx context.Context, recipeIds []primitive.ObjectID, report *dtos.DeletionReport) error {
+	for _, cascade := range service.recipeCascades {
+		count, err := cascade.Count(ctx, recipeIds)
+		if err != nil {
+			return err
+		}
+		report.Removed[cascade.Name] += count
+	}
+	return nil
+}
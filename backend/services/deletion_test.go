@@ -0,0 +1,440 @@
+package services
+
+import (
+	"burned/backend/models"
+	"burned/backend/repositories"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// memoryStore es una base en memoria que comparten los repositorios falsos.
+// Cada repositorio embebe su interfaz para no tener que implementar los
+// métodos que el borrado no usa; llamarlos rompe el test con un panic.
+type memoryStore struct {
+	users    map[primitive.ObjectID]models.User
+	recipes  map[primitive.ObjectID]models.Recipe
+	comments map[primitive.ObjectID]models.Comment
+	ratings  map[primitive.ObjectID]models.Rating
+	saved    map[primitive.ObjectID]models.SavedRecipe
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		users:    map[primitive.ObjectID]models.User{},
+		recipes:  map[primitive.ObjectID]models.Recipe{},
+		comments: map[primitive.ObjectID]models.Comment{},
+		ratings:  map[primitive.ObjectID]models.Rating{},
+		saved:    map[primitive.ObjectID]models.SavedRecipe{},
+	}
+}
+
+func inIds(id primitive.ObjectID, ids []primitive.ObjectID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+type fakeTransactor struct{}
+
+func (fakeTransactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeUserRepo struct {
+	repositories.UserRepositoryInterface
+	store *memoryStore
+}
+
+func (repo fakeUserRepo) GetUserById(id primitive.ObjectID) (models.User, error) {
+	user, ok := repo.store.users[id]
+	if !ok {
+		return models.User{}, errors.New("not found")
+	}
+	return user, nil
+}
+
+func (repo fakeUserRepo) DeleteUserWithContext(ctx context.Context, id primitive.ObjectID) (*mongo.DeleteResult, error) {
+	if _, ok := repo.store.users[id]; !ok {
+		return &mongo.DeleteResult{}, nil
+	}
+	delete(repo.store.users, id)
+	return &mongo.DeleteResult{DeletedCount: 1}, nil
+}
+
+type fakeRecipeRepo struct {
+	repositories.RecipeRepositoryInterface
+	store *memoryStore
+}
+
+func (repo fakeRecipeRepo) GetRecipeById(id primitive.ObjectID) (models.Recipe, error) {
+	recipe, ok := repo.store.recipes[id]
+	if !ok {
+		return models.Recipe{}, errors.New("not found")
+	}
+	return recipe, nil
+}
+
+func (repo fakeRecipeRepo) GetRecipeIdsByUser(ctx context.Context, userId primitive.ObjectID) ([]primitive.ObjectID, error) {
+	var ids []primitive.ObjectID
+	for id, recipe := range repo.store.recipes {
+		if recipe.UserID == userId {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (repo fakeRecipeRepo) DeleteRecipesByIds(ctx context.Context, ids []primitive.ObjectID) (int64, error) {
+	var deleted int64
+	for _, id := range ids {
+		if _, ok := repo.store.recipes[id]; ok {
+			delete(repo.store.recipes, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (repo fakeRecipeRepo) CountLineageByUser(ctx context.Context, userId primitive.ObjectID, ownRecipes []primitive.ObjectID) (int64, error) {
+	var count int64
+	for id, recipe := range repo.store.recipes {
+		if inIds(id, ownRecipes) {
+			continue
+		}
+		for _, origin := range recipe.Lineage {
+			if origin.UserID == userId {
+				count++
+				break
+			}
+		}
+	}
+	return count, nil
+}
+
+func (repo fakeRecipeRepo) AnonymizeLineageByUser(ctx context.Context, userId primitive.ObjectID) (int64, error) {
+	var count int64
+	for id, recipe := range repo.store.recipes {
+		changed := false
+		for i, origin := range recipe.Lineage {
+			if origin.UserID == userId {
+				recipe.Lineage[i].UserID = primitive.NilObjectID
+				recipe.Lineage[i].UserName = models.DeletedUserName
+				changed = true
+			}
+		}
+		if changed {
+			repo.store.recipes[id] = recipe
+			count++
+		}
+	}
+	return count, nil
+}
+
+type fakeCommentRepo struct {
+	repositories.CommentRepositoryInterface
+	store *memoryStore
+}
+
+func (repo fakeCommentRepo) CountCommentsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error) {
+	var count int64
+	for _, comment := range repo.store.comments {
+		if inIds(comment.RecipeID, recipeIds) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (repo fakeCommentRepo) DeleteCommentsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error) {
+	var count int64
+	for id, comment := range repo.store.comments {
+		if inIds(comment.RecipeID, recipeIds) {
+			delete(repo.store.comments, id)
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (repo fakeCommentRepo) CountCommentsByUser(ctx context.Context, userId primitive.ObjectID, excludeRecipes []primitive.ObjectID) (int64, error) {
+	var count int64
+	for _, comment := range repo.store.comments {
+		if comment.UserID == userId && !inIds(comment.RecipeID, excludeRecipes) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (repo fakeCommentRepo) AnonymizeCommentsByUser(ctx context.Context, userId primitive.ObjectID) (int64, error) {
+	var count int64
+	for id, comment := range repo.store.comments {
+		if comment.UserID == userId {
+			comment.UserID = primitive.NilObjectID
+			comment.UserName = models.DeletedUserName
+			repo.store.comments[id] = comment
+			count++
+		}
+	}
+	return count, nil
+}
+
+type fakeRatingRepo struct {
+	repositories.RatingRepositoryInterface
+	store *memoryStore
+}
+
+func (repo fakeRatingRepo) CountRatingsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error) {
+	var count int64
+	for _, rating := range repo.store.ratings {
+		if inIds(rating.RecipeID, recipeIds) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (repo fakeRatingRepo) DeleteRatingsByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error) {
+	var count int64
+	for id, rating := range repo.store.ratings {
+		if inIds(rating.RecipeID, recipeIds) {
+			delete(repo.store.ratings, id)
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (repo fakeRatingRepo) CountRatingsByUser(ctx context.Context, userId primitive.ObjectID, excludeRecipes []primitive.ObjectID) (int64, error) {
+	var count int64
+	for _, rating := range repo.store.ratings {
+		if rating.UserID == userId && !inIds(rating.RecipeID, excludeRecipes) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (repo fakeRatingRepo) DeleteRatingsByUser(ctx context.Context, userId primitive.ObjectID) (int64, error) {
+	var count int64
+	for id, rating := range repo.store.ratings {
+		if rating.UserID == userId {
+			delete(repo.store.ratings, id)
+			count++
+		}
+	}
+	return count, nil
+}
+
+type fakeSavedRecipeRepo struct {
+	repositories.SavedRecipeRepositoryInterface
+	store *memoryStore
+}
+
+func (repo fakeSavedRecipeRepo) CountSavedByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error) {
+	var count int64
+	for _, saved := range repo.store.saved {
+		if inIds(saved.RecipeID, recipeIds) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (repo fakeSavedRecipeRepo) DeleteSavedByRecipes(ctx context.Context, recipeIds []primitive.ObjectID) (int64, error) {
+	var count int64
+	for id, saved := range repo.store.saved {
+		if inIds(saved.RecipeID, recipeIds) {
+			delete(repo.store.saved, id)
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (repo fakeSavedRecipeRepo) CountSavedByUser(ctx context.Context, userId primitive.ObjectID, excludeRecipes []primitive.ObjectID) (int64, error) {
+	var count int64
+	for _, saved := range repo.store.saved {
+		if saved.UserID == userId && !inIds(saved.RecipeID, excludeRecipes) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (repo fakeSavedRecipeRepo) DeleteSavedByUser(ctx context.Context, userId primitive.ObjectID) (int64, error) {
+	var count int64
+	for id, saved := range repo.store.saved {
+		if saved.UserID == userId {
+			delete(repo.store.saved, id)
+			count++
+		}
+	}
+	return count, nil
+}
+
+// deletionFixture arma dos usuarios: author tiene una receta con comentario,
+// puntaje y guardado de other, y other tiene una receta derivada de la de
+// author, con comentario, puntaje y guardado de author.
+type deletionFixture struct {
+	store       *memoryStore
+	service     *DeletionService
+	author      primitive.ObjectID
+	other       primitive.ObjectID
+	ownRecipe   primitive.ObjectID
+	otherRecipe primitive.ObjectID
+	ownComment  primitive.ObjectID
+}
+
+func newDeletionFixture() deletionFixture {
+	store := newMemoryStore()
+	fixture := deletionFixture{
+		store:       store,
+		author:      primitive.NewObjectID(),
+		other:       primitive.NewObjectID(),
+		ownRecipe:   primitive.NewObjectID(),
+		otherRecipe: primitive.NewObjectID(),
+		ownComment:  primitive.NewObjectID(),
+	}
+	store.users[fixture.author] = models.User{ID: fixture.author, Name: "author"}
+	store.users[fixture.other] = models.User{ID: fixture.other, Name: "other"}
+
+	store.recipes[fixture.ownRecipe] = models.Recipe{ID: fixture.ownRecipe, UserID: fixture.author}
+	origin := models.ForkOrigin{RecipeID: fixture.ownRecipe, UserID: fixture.author, UserName: "author"}
+	store.recipes[fixture.otherRecipe] = models.Recipe{ID: fixture.otherRecipe, UserID: fixture.other, Lineage: []models.ForkOrigin{origin}}
+
+	//en la receta propia opinan los dos; todo eso se borra con la receta
+	store.comments[primitive.NewObjectID()] = models.Comment{RecipeID: fixture.ownRecipe, UserID: fixture.other}
+	store.comments[primitive.NewObjectID()] = models.Comment{RecipeID: fixture.ownRecipe, UserID: fixture.author}
+	store.ratings[primitive.NewObjectID()] = models.Rating{RecipeID: fixture.ownRecipe, UserID: fixture.other}
+	store.saved[primitive.NewObjectID()] = models.SavedRecipe{RecipeID: fixture.ownRecipe, UserID: fixture.other}
+
+	//lo del autor en la receta ajena
+	store.comments[fixture.ownComment] = models.Comment{ID: fixture.ownComment, RecipeID: fixture.otherRecipe, UserID: fixture.author, UserName: "author"}
+	store.ratings[primitive.NewObjectID()] = models.Rating{RecipeID: fixture.otherRecipe, UserID: fixture.author}
+	store.saved[primitive.NewObjectID()] = models.SavedRecipe{RecipeID: fixture.otherRecipe, UserID: fixture.author}
+
+	recipeRepo := fakeRecipeRepo{store: store}
+	service := NewDeletionService(fakeTransactor{}, fakeUserRepo{store: store}, recipeRepo, fakeCommentRepo{store: store}, fakeRatingRepo{store: store}, fakeSavedRecipeRepo{store: store})
+	service.AddUserCascade(UserCascade{Name: "RecipeLineage", Anonymize: true, Count: recipeRepo.CountLineageByUser, Apply: recipeRepo.AnonymizeLineageByUser})
+	fixture.service = service
+	return fixture
+}
+
+func TestDeleteUserDryRunMatchesRealRun(t *testing.T) {
+	fixture := newDeletionFixture()
+
+	preview, err := fixture.service.DeleteUser(fixture.author, true)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if len(fixture.store.recipes) != 2 || len(fixture.store.comments) != 3 || len(fixture.store.users) != 2 {
+		t.Fatalf("dry run changed the store")
+	}
+
+	report, err := fixture.service.DeleteUser(fixture.author, false)
+	if err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if !preview.DryRun || report.DryRun {
+		t.Errorf("DryRun flags = %v, %v; want true, false", preview.DryRun, report.DryRun)
+	}
+	if !reflect.DeepEqual(preview.Removed, report.Removed) {
+		t.Errorf("removed: dry run %v, real run %v", preview.Removed, report.Removed)
+	}
+	if !reflect.DeepEqual(preview.Anonymized, report.Anonymized) {
+		t.Errorf("anonymized: dry run %v, real run %v", preview.Anonymized, report.Anonymized)
+	}
+}
+
+func TestDeleteRecipeDryRunMatchesRealRun(t *testing.T) {
+	fixture := newDeletionFixture()
+
+	preview, err := fixture.service.DeleteRecipe(fixture.ownRecipe, true)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	report, err := fixture.service.DeleteRecipe(fixture.ownRecipe, false)
+	if err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if !reflect.DeepEqual(preview.Removed, report.Removed) {
+		t.Errorf("removed: dry run %v, real run %v", preview.Removed, report.Removed)
+	}
+	want := map[string]int64{"Recipe": 1, "Comment": 2, "Rating": 1, "SavedRecipes": 1}
+	if !reflect.DeepEqual(report.Removed, want) {
+		t.Errorf("removed = %v, want %v", report.Removed, want)
+	}
+}
+
+func TestDeleteUserRemovesOwnRecipesAndDependents(t *testing.T) {
+	fixture := newDeletionFixture()
+
+	if _, err := fixture.service.DeleteUser(fixture.author, false); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, ok := fixture.store.users[fixture.author]; ok {
+		t.Errorf("user still exists")
+	}
+	if _, ok := fixture.store.recipes[fixture.ownRecipe]; ok {
+		t.Errorf("own recipe still exists")
+	}
+	for _, comment := range fixture.store.comments {
+		if comment.RecipeID == fixture.ownRecipe {
+			t.Errorf("comment on deleted recipe survived: %+v", comment)
+		}
+	}
+	for _, rating := range fixture.store.ratings {
+		if rating.RecipeID == fixture.ownRecipe || rating.UserID == fixture.author {
+			t.Errorf("rating survived: %+v", rating)
+		}
+	}
+	for _, saved := range fixture.store.saved {
+		if saved.RecipeID == fixture.ownRecipe || saved.UserID == fixture.author {
+			t.Errorf("saved recipe survived: %+v", saved)
+		}
+	}
+	if _, ok := fixture.store.recipes[fixture.otherRecipe]; !ok {
+		t.Errorf("another user's recipe was deleted")
+	}
+}
+
+func TestDeleteUserAnonymizesContentOnOtherRecipes(t *testing.T) {
+	fixture := newDeletionFixture()
+
+	report, err := fixture.service.DeleteUser(fixture.author, false)
+	if err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	comment, ok := fixture.store.comments[fixture.ownComment]
+	if !ok {
+		t.Fatalf("comment on another user's recipe was deleted")
+	}
+	if !comment.UserID.IsZero() || comment.UserName != models.DeletedUserName {
+		t.Errorf("comment author = %v %q, want anonymized", comment.UserID, comment.UserName)
+	}
+
+	lineage := fixture.store.recipes[fixture.otherRecipe].Lineage
+	if len(lineage) != 1 {
+		t.Fatalf("lineage = %+v, want one origin", lineage)
+	}
+	if !lineage[0].UserID.IsZero() || lineage[0].UserName != models.DeletedUserName {
+		t.Errorf("lineage origin = %v %q, want anonymized", lineage[0].UserID, lineage[0].UserName)
+	}
+
+	if report.Anonymized["Comment"] != 1 || report.Anonymized["RecipeLineage"] != 1 {
+		t.Errorf("anonymized = %v, want one comment and one lineage", report.Anonymized)
+	}
+	if _, ok := report.Removed["RecipeLineage"]; ok {
+		t.Errorf("lineage reported as removed: %v", report.Removed)
+	}
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"burned/backend/dtos"
+	"burned/backend/export"
+	"burned/backend/models"
+	"burned/backend/repositories"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ExportServiceInterface interface {
+	ExportRecipe(id string, format string, requesterId string) (dtos.ExportFile, error)
+	ExportRecipesByUser(userId string, format string) (dtos.ExportFile, error)
+}
+
+type ExportService struct {
+	recipeRepo repositories.RecipeRepositoryInterface
+	userRepo   repositories.UserRepositoryInterface
+}
+
+func NewExportService(recipeRepo repositories.RecipeRepositoryInterface, userRepo repositories.UserRepositoryInterface) *ExportService {
+	return &ExportService{recipeRepo: recipeRepo, userRepo: userRepo}
+}
+
+// ExportRecipe renderiza una receta en el formato pedido. Las privadas y las
+// ocultas por moderación solo las exporta su dueño; para el resto se responde
+// como si no existiera.
+func (service *ExportService) ExportRecipe(id string, format string, requesterId string) (dtos.ExportFile, error) {
+	exporter, ok := export.Get(format)
+	if !ok {
+		return dtos.ExportFile{}, errors.New("unsupported export format")
+	}
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return dtos.ExportFile{}, errors.New("invalid id")
+	}
+	recipe, err := service.recipeRepo.GetRecipeById(oid)
+	if err != nil {
+		return dtos.ExportFile{}, errors.New("recipe not found")
+	}
+	requesterOid, _ := primitive.ObjectIDFromHex(requesterId)
+	if !visibleTo(recipe, requesterOid) {
+		return dtos.ExportFile{}, errors.New("recipe not found")
+	}
+
+	response := service.toResponse(recipe)
+	content, err := exporter.Export(response)
+	if err != nil {
+		return dtos.ExportFile{}, err
+	}
+	return dtos.ExportFile{
+		Name:        export.FileName(response, exporter),
+		ContentType: exporter.ContentType(),
+		Content:     content,
+	}, nil
+}
+
+// ExportRecipesByUser arma un zip con todas las recetas del usuario,
+// incluidas las privadas porque solo se llama con su propia identidad.
+func (service *ExportService) ExportRecipesByUser(userId string, format string) (dtos.ExportFile, error) {
+	exporter, ok := export.Get(format)
+	if !ok {
+		return dtos.ExportFile{}, errors.New("unsupported export format")
+	}
+	oid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return dtos.ExportFile{}, errors.New("invalid id")
+	}
+	recipes, err := service.recipeRepo.GetRecipesByUser(oid)
+	if err != nil {
+		return dtos.ExportFile{}, errors.New("recipes not found")
+	}
+
+	responses := make([]dtos.RecipeResponse, 0, len(recipes))
+	for _, recipe := range recipes {
+		responses = append(responses, service.toResponse(recipe))
+	}
+	content, err := export.Zip(responses, exporter)
+	if err != nil {
+		return dtos.ExportFile{}, err
+	}
+	return dtos.ExportFile{
+		Name:        "recetas-" + exporter.Extension() + ".zip",
+		ContentType: "application/zip",
+		Content:     content,
+	}, nil
+}
+
+func (service *ExportService) toResponse(recipe models.Recipe) dtos.RecipeResponse {
+	response := dtos.RecipeModelToResponse(recipe)
+	if user, err := service.userRepo.GetUserById(recipe.UserID); err == nil {
+		response.UserName = user.Name
+	} else {
+		response.UserName = "Unknown"
+	}
+	return response
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"burned/backend/dtos"
+	"burned/backend/models"
+	"burned/backend/pagination"
+	"burned/backend/repositories"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// feedBatchSize es cuántos seguidores se leen y escriben por tanda al
+// repartir una publicación.
+const feedBatchSize = 500
+
+// feedBackfillLimit es cuántas recetas y cuántos comentarios recientes de un
+// usuario entran al feed de quien lo empieza a seguir.
+const feedBackfillLimit = 50
+
+type FeedServiceInterface interface {
+	GetFeed(requesterId string, page pagination.Request) (pagination.Page[dtos.FeedItemResponse], error)
+	PublishRecipe(recipe models.Recipe)
+	PublishComment(comment models.Comment)
+	AddFollow(followerId primitive.ObjectID, followeeId primitive.ObjectID) error
+	RemoveFollow(followerId primitive.ObjectID, followeeId primitive.ObjectID) error
+}
+
+// FeedService arma el feed con fan-out en la escritura: cada publicación se
+// copia, como referencia, al feed de cada seguidor del autor. Leer un feed es
+// entonces una consulta por índice sobre el dueño, que no depende de a cuántos
+// sigue; el costo queda en publicar, que se hace por tandas y en segundo plano.
+// La visibilidad se revisa al leer, así una receta que pasa a privada, se
+// oculta por moderación o se borra deja de aparecer sin tocar los feeds.
+type FeedService struct {
+	feedRepo    repositories.FeedRepositoryInterface
+	followRepo  repositories.FollowRepositoryInterface
+	recipeRepo  repositories.RecipeRepositoryInterface
+	commentRepo repositories.CommentRepositoryInterface
+	userRepo    repositories.UserRepositoryInterface
+}
+
+func NewFeedService(feedRepo repositories.FeedRepositoryInterface, followRepo repositories.FollowRepositoryInterface, recipeRepo repositories.RecipeRepositoryInterface, commentRepo repositories.CommentRepositoryInterface, userRepo repositories.UserRepositoryInterface) *FeedService {
+	return &FeedService{feedRepo: feedRepo, followRepo: followRepo, recipeRepo: recipeRepo, commentRepo: commentRepo, userRepo: userRepo}
+}
+
+// PublishRecipe reparte una receta pública en los feeds de los seguidores.
+func (service *FeedService) PublishRecipe(recipe models.Recipe) {
+	go service.fanOut(models.FeedItem{
+		ActorID:   recipe.UserID,
+		Type:      models.FeedRecipe,
+		TargetID:  recipe.ID,
+		RecipeID:  recipe.ID,
+		CreatedAt: time.Now(),
+	})
+}
+
+// PublishComment reparte un comentario en los feeds de los seguidores.
+func (service *FeedService) PublishComment(comment models.Comment) {
+	go service.fanOut(models.FeedItem{
+		ActorID:   comment.UserID,
+		Type:      models.FeedComment,
+		TargetID:  comment.ID,
+		RecipeID:  comment.RecipeID,
+		CreatedAt: comment.CreatedAt,
+	})
+}
+
+func (service *FeedService) fanOut(item models.FeedItem) {
+	var after primitive.ObjectID
+	for {
+		follows, err := service.followRepo.GetFollowerBatch(item.ActorID, after, feedBatchSize)
+		if err != nil {
+			log.Println("⚠️ Aviso: No se pudo repartir la publicación en los feeds:", err)
+			return
+		}
+		items := make([]models.FeedItem, 0, len(follows))
+		for _, follow := range follows {
+			entry := item
+			entry.OwnerID = follow.FollowerID
+			items = append(items, entry)
+		}
+		if err := service.feedRepo.AddItems(items); err != nil {
+			log.Println("⚠️ Aviso: No se pudo repartir la publicación en los feeds:", err)
+			return
+		}
+		if len(follows) < feedBatchSize {
+			return
+		}
+		after = follows[len(follows)-1].ID
+	}
+}
+
+// AddFollow llena el feed de quien empieza a seguir con lo último del otro,
+// para que no arranque vacío.
+func (service *FeedService) AddFollow(followerId primitive.ObjectID, followeeId primitive.ObjectID) error {
+	items, err := service.feedRepo.GetRecentActivity(followeeId, time.Now().Add(-repositories.FeedRetention), feedBackfillLimit)
+	if err != nil {
+		return err
+	}
+	for i := range items {
+		items[i].OwnerID = followerId
+	}
+	return service.feedRepo.AddItems(items)
+}
+
+// RemoveFollow saca del feed lo de quien se dejó de seguir.
+func (service *FeedService) RemoveFollow(followerId primitive.ObjectID, followeeId primitive.ObjectID) error {
+	return service.feedRepo.DeleteItemsByActor(followerId, followeeId)
+}
+
+// GetFeed devuelve la actividad de los usuarios seguidos, de lo más nuevo a lo
+// más viejo. Lo que ya no es visible se saltea, así que una página puede
+// traer menos elementos que el límite aunque haya más; el cursor sigue valiendo.
+func (service *FeedService) GetFeed(requesterId string, page pagination.Request) (pagination.Page[dtos.FeedItemResponse], error) {
+	userOid, err := primitive.ObjectIDFromHex(requesterId)
+	if err != nil {
+		return pagination.Page[dtos.FeedItemResponse]{}, errors.New("invalid id")
+	}
+	result, err := service.feedRepo.GetFeedPaged(userOid, page)
+	if err != nil {
+		if pagination.IsRequestError(err) {
+			return pagination.Page[dtos.FeedItemResponse]{}, err
+		}
+		return pagination.Page[dtos.FeedItemResponse]{}, errors.New("feed not found")
+	}
+
+	var recipeIds, commentIds []primitive.ObjectID
+	for _, item := range result.Items {
+		recipeIds = append(recipeIds, item.RecipeID)
+		if item.Type == models.FeedComment {
+			commentIds = append(commentIds, item.TargetID)
+		}
+	}
+	recipeList, err := service.recipeRepo.GetRecipesByIds(recipeIds)
+	if err != nil {
+		return pagination.Page[dtos.FeedItemResponse]{}, err
+	}
+	commentList, err := service.commentRepo.GetCommentsByIds(commentIds)
+	if err != nil {
+		return pagination.Page[dtos.FeedItemResponse]{}, err
+	}
+
+	recipes := map[primitive.ObjectID]models.Recipe{}
+	var userIds []primitive.ObjectID
+	for _, recipe := range recipeList {
+		if recipe.Visibility == "public" && !recipe.Hidden {
+			recipes[recipe.ID] = recipe
+			userIds = append(userIds, recipe.UserID)
+		}
+	}
+	comments := map[primitive.ObjectID]models.Comment{}
+	for _, comment := range commentList {
+		if !comment.Deleted && !comment.Hidden {
+			comments[comment.ID] = comment
+		}
+	}
+	userList, err := service.userRepo.GetUsersByIds(userIds)
+	if err != nil {
+		return pagination.Page[dtos.FeedItemResponse]{}, err
+	}
+	names := map[primitive.ObjectID]string{}
+	for _, user := range userList {
+		names[user.ID] = user.Name
+	}
+
+	feed := pagination.Page[dtos.FeedItemResponse]{Items: []dtos.FeedItemResponse{}, NextCursor: result.NextCursor}
+	for _, item := range result.Items {
+		recipe, ok := recipes[item.RecipeID]
+		if !ok {
+			continue
+		}
+		recipeResponse := dtos.RecipeModelToResponse(recipe)
+		recipeResponse.UserName = names[recipe.UserID]
+		response := dtos.FeedItemResponse{
+			ID:        item.ID.Hex(),
+			Type:      item.Type,
+			UserID:    item.ActorID.Hex(),
+			CreatedAt: item.CreatedAt,
+			Recipe:    &recipeResponse,
+		}
+		if item.Type == models.FeedComment {
+			comment, ok := comments[item.TargetID]
+			if !ok {
+				continue
+			}
+			commentResponse := publicComment(comment)
+			response.Comment = &commentResponse
+			response.UserName = comment.UserName
+		} else {
+			response.UserName = recipeResponse.UserName
+		}
+		feed.Items = append(feed.Items, response)
+	}
+	return feed, nil
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"burned/backend/dtos"
+	"burned/backend/models"
+	"burned/backend/pagination"
+	"burned/backend/repositories"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type FollowServiceInterface interface {
+	Follow(userId string, requesterId string) (dtos.FollowResponse, error)
+	Unfollow(userId string, requesterId string) (dtos.FollowResponse, error)
+	GetProfile(userId string, requesterId string) (dtos.UserProfileResponse, error)
+	GetFollowers(userId string, page pagination.Request) (pagination.Page[dtos.FollowUserResponse], error)
+	GetFollowing(userId string, page pagination.Request) (pagination.Page[dtos.FollowUserResponse], error)
+}
+
+type FollowService struct {
+	followRepo  repositories.FollowRepositoryInterface
+	userRepo    repositories.UserRepositoryInterface
+	feedService FeedServiceInterface
+}
+
+func NewFollowService(followRepo repositories.FollowRepositoryInterface, userRepo repositories.UserRepositoryInterface, feedService FeedServiceInterface) *FollowService {
+	return &FollowService{followRepo: followRepo, userRepo: userRepo, feedService: feedService}
+}
+
+// Follow hace que requesterId siga a userId. Seguir a alguien que ya se
+// sigue no cambia nada.
+func (service *FollowService) Follow(userId string, requesterId string) (dtos.FollowResponse, error) {
+	followeeOid, followerOid, err := service.followIds(userId, requesterId)
+	if err != nil {
+		return dtos.FollowResponse{}, err
+	}
+	created, err := service.followRepo.Follow(models.Follow{FollowerID: followerOid, FolloweeID: followeeOid, CreatedAt: time.Now()})
+	if err != nil {
+		return dtos.FollowResponse{}, errors.New("internal server error")
+	}
+	if created {
+		//si falla, el feed se completa igual con lo que publique de acá en más
+		if err := service.feedService.AddFollow(followerOid, followeeOid); err != nil {
+			log.Println("⚠️ Aviso: No se pudo completar el feed del nuevo seguidor:", err)
+		}
+	}
+	return service.followResponse(followeeOid, true)
+}
+
+// Unfollow deja de seguir a userId y saca su actividad del feed.
+func (service *FollowService) Unfollow(userId string, requesterId string) (dtos.FollowResponse, error) {
+	followeeOid, followerOid, err := service.followIds(userId, requesterId)
+	if err != nil {
+		return dtos.FollowResponse{}, err
+	}
+	removed, err := service.followRepo.Unfollow(followerOid, followeeOid)
+	if err != nil {
+		return dtos.FollowResponse{}, errors.New("internal server error")
+	}
+	if removed {
+		if err := service.feedService.RemoveFollow(followerOid, followeeOid); err != nil {
+			return dtos.FollowResponse{}, errors.New("internal server error")
+		}
+	}
+	return service.followResponse(followeeOid, false)
+}
+
+func (service *FollowService) followIds(userId string, requesterId string) (primitive.ObjectID, primitive.ObjectID, error) {
+	followeeOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return primitive.NilObjectID, primitive.NilObjectID, errors.New("invalid id")
+	}
+	followerOid, err := primitive.ObjectIDFromHex(requesterId)
+	if err != nil {
+		return primitive.NilObjectID, primitive.NilObjectID, errors.New("invalid id")
+	}
+	if followeeOid == followerOid {
+		return primitive.NilObjectID, primitive.NilObjectID, errors.New("invalid follow, you cannot follow yourself")
+	}
+	if _, err := service.userRepo.GetUserById(followeeOid); err != nil {
+		return primitive.NilObjectID, primitive.NilObjectID, errors.New("user not found")
+	}
+	return followeeOid, followerOid, nil
+}
+
+func (service *FollowService) followResponse(userOid primitive.ObjectID, following bool) (dtos.FollowResponse, error) {
+	user, err := service.userRepo.GetUserById(userOid)
+	if err != nil {
+		return dtos.FollowResponse{}, errors.New("user not found")
+	}
+	return dtos.FollowResponse{
+		UserID:         user.ID.Hex(),
+		Following:      following,
+		FollowerCount:  user.FollowerCount,
+		FollowingCount: user.FollowingCount,
+	}, nil
+}
+
+// GetProfile devuelve el perfil público con sus contadores; con sesión indica
+// además si quien consulta lo sigue.
+func (service *FollowService) GetProfile(userId string, requesterId string) (dtos.UserProfileResponse, error) {
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return dtos.UserProfileResponse{}, errors.New("invalid id")
+	}
+	user, err := service.userRepo.GetUserById(userOid)
+	if err != nil {
+		return dtos.UserProfileResponse{}, errors.New("user not found")
+	}
+	response := dtos.UserModelToProfileResponse(user)
+	if requesterOid, err := primitive.ObjectIDFromHex(requesterId); err == nil && requesterOid != userOid {
+		following, err := service.followRepo.IsFollowing(requesterOid, userOid)
+		if err != nil {
+			return dtos.UserProfileResponse{}, errors.New("internal server error")
+		}
+		response.Following = following
+	}
+	return response, nil
+}
+
+func (service *FollowService) GetFollowers(userId string, page pagination.Request) (pagination.Page[dtos.FollowUserResponse], error) {
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return pagination.Page[dtos.FollowUserResponse]{}, errors.New("invalid id")
+	}
+	result, err := service.followRepo.GetFollowersPaged(userOid, page)
+	if err != nil {
+		return pagination.Page[dtos.FollowUserResponse]{}, followListError(err)
+	}
+	return service.followUsers(result, func(follow models.Follow) primitive.ObjectID { return follow.FollowerID })
+}
+
+func (service *FollowService) GetFollowing(userId string, page pagination.Request) (pagination.Page[dtos.FollowUserResponse], error) {
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return pagination.Page[dtos.FollowUserResponse]{}, errors.New("invalid id")
+	}
+	result, err := service.followRepo.GetFollowingPaged(userOid, page)
+	if err != nil {
+		return pagination.Page[dtos.FollowUserResponse]{}, followListError(err)
+	}
+	return service.followUsers(result, func(follow models.Follow) primitive.ObjectID { return follow.FolloweeID })
+}
+
+func followListError(err error) error {
+	if pagination.IsRequestError(err) {
+		return err
+	}
+	return errors.New("follows not found")
+}
+
+// followUsers completa una página de relaciones con los datos del otro
+// usuario, que other elige de cada relación.
+func (service *FollowService) followUsers(result pagination.Page[models.Follow], other func(models.Follow) primitive.ObjectID) (pagination.Page[dtos.FollowUserResponse], error) {
+	ids := make([]primitive.ObjectID, 0, len(result.Items))
+	for _, follow := range result.Items {
+		ids = append(ids, other(follow))
+	}
+	users, err := service.userRepo.GetUsersByIds(ids)
+	if err != nil {
+		return pagination.Page[dtos.FollowUserResponse]{}, errors.New("internal server error")
+	}
+	byId := map[primitive.ObjectID]models.User{}
+	for _, user := range users {
+		byId[user.ID] = user
+	}
+
+	page := pagination.Page[dtos.FollowUserResponse]{Items: []dtos.FollowUserResponse{}, NextCursor: result.NextCursor}
+	for _, follow := range result.Items {
+		user, ok := byId[other(follow)]
+		if !ok {
+			continue
+		}
+		page.Items = append(page.Items, dtos.FollowUserResponse{
+			ID:            user.ID.Hex(),
+			Name:          user.Name,
+			FollowerCount: user.FollowerCount,
+			Since:         follow.CreatedAt,
+		})
+	}
+	return page, nil
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"burned/backend/dtos"
+	"burned/backend/models"
+	"burned/backend/pagination"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ForkRecipe copia una receta pública (o propia) a la cuenta de quien la pide.
+// La copia arranca privada y sin puntaje ni guardados, y lleva el linaje
+// completo de la original.
+func (service *RecipeService) ForkRecipe(id string, requesterId string) (dtos.RecipeResponse, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return dtos.RecipeResponse{}, errors.New("invalid id")
+	}
+	userOid, err := primitive.ObjectIDFromHex(requesterId)
+	if err != nil {
+		return dtos.RecipeResponse{}, errors.New("invalid id")
+	}
+	original, err := service.recipeRepo.GetRecipeById(oid)
+	if err != nil {
+		return dtos.RecipeResponse{}, errors.New("recipe not found")
+	}
+	if !visibleTo(original, userOid) {
+		return dtos.RecipeResponse{}, errors.New("recipe not found")
+	}
+	user, err := service.userRepo.GetUserById(userOid)
+	if err != nil {
+		return dtos.RecipeResponse{}, errors.New("user not found")
+	}
+
+	authorName := models.DeletedUserName
+	if author, err := service.userRepo.GetUserById(original.UserID); err == nil {
+		authorName = author.Name
+	}
+	now := time.Now()
+	origin := models.ForkOrigin{
+		RecipeID: original.ID,
+		UserID:   original.UserID,
+		UserName: authorName,
+		Title:    original.Title,
+		ForkedAt: now,
+	}
+
+	fork := original
+	fork.ID = primitive.NilObjectID
+	fork.UserID = userOid
+	fork.Visibility = "private"
+	fork.CreatedAt = now
+	fork.UpdatedAt = time.Time{}
+	fork.AverageRating = 0
+	fork.SavedCount = 0
+	fork.Score = 0
+	fork.ForkedFrom = &origin
+	fork.Lineage = append(append([]models.ForkOrigin{}, original.Lineage...), origin)
+	fork.Ingredients = append([]models.Ingredient{}, original.Ingredients...)
+	deriveRecipeFields(&fork)
+
+	inserted, err := service.recipeRepo.CreateRecipe(fork)
+	if err != nil {
+		return dtos.RecipeResponse{}, err
+	}
+	insertedOid, ok := inserted.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return dtos.RecipeResponse{}, errors.New("invalid id")
+	}
+	fork.ID = insertedOid
+	if err := recordRevision(service.revisionRepo, nil, fork, userOid, 0); err != nil {
+		return dtos.RecipeResponse{}, err
+	}
+
+	response := dtos.RecipeModelToResponse(fork)
+	response.UserName = user.Name
+	return response, nil
+}
+
+// GetForks lista las versiones públicas de una receta. Funciona aunque la
+// original ya no exista, porque las copias guardan su id en el linaje.
+func (service *RecipeService) GetForks(id string, descendants bool, page pagination.Request) (pagination.Page[dtos.RecipeResponse], error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return pagination.Page[dtos.RecipeResponse]{}, errors.New("invalid id")
+	}
+	result, err := service.recipeRepo.GetForksPaged(oid, descendants, page)
+	if err != nil {
+		if pagination.IsRequestError(err) {
+			return pagination.Page[dtos.RecipeResponse]{}, err
+		}
+		return pagination.Page[dtos.RecipeResponse]{}, errors.New("recipes not found")
+	}
+	names := map[primitive.ObjectID]string{}
+	return pagination.Map(result, func(recipe models.Recipe) dtos.RecipeResponse {
+		response := dtos.RecipeModelToResponse(recipe)
+		if _, ok := names[recipe.UserID]; !ok {
+			names[recipe.UserID] = "Unknown"
+			if user, err := service.userRepo.GetUserById(recipe.UserID); err == nil {
+				names[recipe.UserID] = user.Name
+			}
+		}
+		response.UserName = names[recipe.UserID]
+		return response
+	}), nil
+}
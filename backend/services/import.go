@@ -0,0 +1,33 @@
+package services
+
+import (
+	"burned/backend/dtos"
+	"burned/backend/recipeimport"
+)
+
+type RecipeImportServiceInterface interface {
+	PreviewImport(content []byte) (dtos.RecipeImportResponse, error)
+}
+
+type RecipeImportService struct{}
+
+func NewRecipeImportService() *RecipeImportService {
+	return &RecipeImportService{}
+}
+
+// PreviewImport convierte el documento en un borrador sin guardarlo. Todo el
+// trabajo se hace sobre el contenido recibido, nunca se descarga nada.
+func (service *RecipeImportService) PreviewImport(content []byte) (dtos.RecipeImportResponse, error) {
+	draft, err := recipeimport.Import(content)
+	if err != nil {
+		return dtos.RecipeImportResponse{}, err
+	}
+	response := dtos.RecipeImportResponse{
+		Draft:    dtos.RecipeModelToRequest(draft.Recipe),
+		Warnings: draft.Warnings,
+	}
+	if response.Warnings == nil {
+		response.Warnings = []string{}
+	}
+	return response, nil
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"burned/backend/dtos"
+	"burned/backend/ingredients"
+	"strings"
+)
+
+type IngredientServiceInterface interface {
+	ParseIngredients(request dtos.ParseIngredientsRequest) []dtos.ParsedIngredientResponse
+}
+
+type IngredientService struct{}
+
+func NewIngredientService() *IngredientService {
+	return &IngredientService{}
+}
+
+// ParseIngredients interpreta cada línea por separado; una línea inválida no
+// impide devolver el resto, así el cliente puede marcar solo la que falla.
+func (service *IngredientService) ParseIngredients(request dtos.ParseIngredientsRequest) []dtos.ParsedIngredientResponse {
+	responses := []dtos.ParsedIngredientResponse{}
+	for _, line := range request.Lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		response := dtos.ParsedIngredientResponse{Input: line}
+		parsed, err := ingredients.ParseLine(line)
+		if err != nil {
+			response.Error = err.Error()
+		} else {
+			response.Ingredient = parsed.Ingredient()
+			response.QuantityMax = parsed.QuantityMax
+		}
+		responses = append(responses, response)
+	}
+	return responses
+}
@@ -0,0 +1,300 @@
+package services
+
+import (
+	"burned/backend/dtos"
+	"burned/backend/mealplan"
+	"burned/backend/models"
+	"burned/backend/repositories"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MealPlanServiceInterface interface {
+	CreateEntry(entry dtos.MealPlanEntryRequest, userId string) (dtos.MealPlanEntryResponse, error)
+	UpdateEntry(id string, entry dtos.MealPlanEntryRequest, userId string) (dtos.MealPlanEntryResponse, error)
+	DeleteEntry(id string, userId string) error
+	GetPlan(userId string, from string, to string) (dtos.MealPlanResponse, error)
+	CopyWeek(request dtos.CopyWeekRequest, userId string) (dtos.MealPlanResponse, error)
+	RepeatWeek(request dtos.RepeatWeekRequest, userId string) (dtos.MealPlanResponse, error)
+}
+
+type MealPlanService struct {
+	mealPlanRepo repositories.MealPlanRepositoryInterface
+	recipeRepo   repositories.RecipeRepositoryInterface
+}
+
+func NewMealPlanService(mealPlanRepo repositories.MealPlanRepositoryInterface, recipeRepo repositories.RecipeRepositoryInterface) *MealPlanService {
+	return &MealPlanService{mealPlanRepo: mealPlanRepo, recipeRepo: recipeRepo}
+}
+
+func (service *MealPlanService) CreateEntry(entry dtos.MealPlanEntryRequest, userId string) (dtos.MealPlanEntryResponse, error) {
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return dtos.MealPlanEntryResponse{}, errors.New("invalid id")
+	}
+	model, recipe, err := service.entryFromRequest(entry, userOid)
+	if err != nil {
+		return dtos.MealPlanEntryResponse{}, err
+	}
+	model.CreatedAt = time.Now()
+	model.UpdatedAt = model.CreatedAt
+	created, err := service.mealPlanRepo.CreateEntries([]models.MealPlanEntry{model})
+	if err != nil {
+		return dtos.MealPlanEntryResponse{}, err
+	}
+	return entryResponse(created[0], &recipe), nil
+}
+
+func (service *MealPlanService) UpdateEntry(id string, entry dtos.MealPlanEntryRequest, userId string) (dtos.MealPlanEntryResponse, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return dtos.MealPlanEntryResponse{}, errors.New("invalid id")
+	}
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return dtos.MealPlanEntryResponse{}, errors.New("invalid id")
+	}
+	current, err := service.mealPlanRepo.GetEntryById(oid)
+	if err != nil || current.UserID != userOid {
+		return dtos.MealPlanEntryResponse{}, errors.New("meal plan entry not found")
+	}
+	model, recipe, err := service.entryFromRequest(entry, userOid)
+	if err != nil {
+		return dtos.MealPlanEntryResponse{}, err
+	}
+	model.ID = current.ID
+	model.CreatedAt = current.CreatedAt
+	model.UpdatedAt = time.Now()
+	if err := service.mealPlanRepo.UpdateEntry(model); err != nil {
+		return dtos.MealPlanEntryResponse{}, err
+	}
+	return entryResponse(model, &recipe), nil
+}
+
+func (service *MealPlanService) DeleteEntry(id string, userId string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.New("invalid id")
+	}
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return errors.New("invalid id")
+	}
+	deleted, err := service.mealPlanRepo.DeleteEntry(oid, userOid)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return errors.New("meal plan entry not found")
+	}
+	return nil
+}
+
+// GetPlan devuelve el plan entre dos fechas inclusive. Sin fechas devuelve la
+// semana actual; con una sola, la semana que la contiene.
+func (service *MealPlanService) GetPlan(userId string, from string, to string) (dtos.MealPlanResponse, error) {
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return dtos.MealPlanResponse{}, errors.New("invalid id")
+	}
+	from, to, err = planRange(from, to)
+	if err != nil {
+		return dtos.MealPlanResponse{}, err
+	}
+	entries, err := service.mealPlanRepo.GetEntriesByRange(userOid, from, to)
+	if err != nil {
+		return dtos.MealPlanResponse{}, err
+	}
+	return service.planResponse(from, to, entries, userOid)
+}
+
+// CopyWeek copia las comidas de una semana a otra conservando día y comida.
+func (service *MealPlanService) CopyWeek(request dtos.CopyWeekRequest, userId string) (dtos.MealPlanResponse, error) {
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return dtos.MealPlanResponse{}, errors.New("invalid id")
+	}
+	source, err := mealplan.ParseDate(request.From)
+	if err != nil {
+		return dtos.MealPlanResponse{}, err
+	}
+	target, err := mealplan.ParseDate(request.To)
+	if err != nil {
+		return dtos.MealPlanResponse{}, err
+	}
+	offset := int(mealplan.WeekStart(target).Sub(mealplan.WeekStart(source)).Hours() / 24)
+	if offset == 0 {
+		return dtos.MealPlanResponse{}, errors.New("source and target are the same week")
+	}
+	created, err := service.copyWeek(userOid, source, []int{offset}, request.Replace)
+	if err != nil {
+		return dtos.MealPlanResponse{}, err
+	}
+	from, to := mealplan.Week(target)
+	return service.planResponse(from, to, created, userOid)
+}
+
+// RepeatWeek copia una semana en las siguientes Times semanas.
+func (service *MealPlanService) RepeatWeek(request dtos.RepeatWeekRequest, userId string) (dtos.MealPlanResponse, error) {
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return dtos.MealPlanResponse{}, errors.New("invalid id")
+	}
+	source, err := mealplan.ParseDate(request.Week)
+	if err != nil {
+		return dtos.MealPlanResponse{}, err
+	}
+	offsets := make([]int, 0, request.Times)
+	for week := 1; week <= request.Times; week++ {
+		offsets = append(offsets, week*7)
+	}
+	created, err := service.copyWeek(userOid, source, offsets, request.Replace)
+	if err != nil {
+		return dtos.MealPlanResponse{}, err
+	}
+	start := mealplan.WeekStart(source)
+	return service.planResponse(mealplan.FormatDate(start.AddDate(0, 0, 7)), mealplan.FormatDate(start.AddDate(0, 0, 7*request.Times+6)), created, userOid)
+}
+
+// copyWeek duplica la semana de source desplazada por cada offset en días.
+// Las recetas que el usuario ya no puede ver no se copian.
+func (service *MealPlanService) copyWeek(userId primitive.ObjectID, source time.Time, offsets []int, replace bool) ([]models.MealPlanEntry, error) {
+	from, to := mealplan.Week(source)
+	entries, err := service.mealPlanRepo.GetEntriesByRange(userId, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("source week has no meals planned")
+	}
+	visible, err := service.visibleRecipes(entries, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	copies := []models.MealPlanEntry{}
+	for _, offset := range offsets {
+		if replace {
+			targetFrom, _ := mealplan.ShiftDate(from, offset)
+			targetTo, _ := mealplan.ShiftDate(to, offset)
+			if _, err := service.mealPlanRepo.DeleteEntriesByRange(userId, targetFrom, targetTo); err != nil {
+				return nil, err
+			}
+		}
+		for _, entry := range entries {
+			if _, ok := visible[entry.RecipeID]; !ok {
+				continue
+			}
+			date, _ := mealplan.ShiftDate(entry.Date, offset)
+			copies = append(copies, models.MealPlanEntry{
+				UserID:    userId,
+				RecipeID:  entry.RecipeID,
+				Date:      date,
+				Slot:      entry.Slot,
+				Servings:  entry.Servings,
+				CreatedAt: now,
+				UpdatedAt: now,
+			})
+		}
+	}
+	return service.mealPlanRepo.CreateEntries(copies)
+}
+
+// entryFromRequest valida fecha, comida y que la receta sea visible para el usuario.
+func (service *MealPlanService) entryFromRequest(entry dtos.MealPlanEntryRequest, userId primitive.ObjectID) (models.MealPlanEntry, models.Recipe, error) {
+	if _, err := mealplan.ParseDate(entry.Date); err != nil {
+		return models.MealPlanEntry{}, models.Recipe{}, err
+	}
+	if !mealplan.ValidSlot(entry.Slot) {
+		return models.MealPlanEntry{}, models.Recipe{}, errors.New("invalid slot")
+	}
+	recipeOid, err := primitive.ObjectIDFromHex(entry.RecipeID)
+	if err != nil {
+		return models.MealPlanEntry{}, models.Recipe{}, errors.New("invalid recipe ID")
+	}
+	recipe, err := service.recipeRepo.GetRecipeById(recipeOid)
+	if err != nil || !visibleTo(recipe, userId) {
+		return models.MealPlanEntry{}, models.Recipe{}, errors.New("recipe not found")
+	}
+	return models.MealPlanEntry{
+		UserID:   userId,
+		RecipeID: recipeOid,
+		Date:     entry.Date,
+		Slot:     entry.Slot,
+		Servings: entry.Servings,
+	}, recipe, nil
+}
+
+func (service *MealPlanService) visibleRecipes(entries []models.MealPlanEntry, userId primitive.ObjectID) (map[primitive.ObjectID]models.Recipe, error) {
+	ids := make([]primitive.ObjectID, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.RecipeID)
+	}
+	recipes, err := service.recipeRepo.GetRecipesByIds(ids)
+	if err != nil {
+		return nil, err
+	}
+	visible := make(map[primitive.ObjectID]models.Recipe, len(recipes))
+	for _, recipe := range recipes {
+		if visibleTo(recipe, userId) {
+			visible[recipe.ID] = recipe
+		}
+	}
+	return visible, nil
+}
+
+func (service *MealPlanService) planResponse(from string, to string, entries []models.MealPlanEntry, userId primitive.ObjectID) (dtos.MealPlanResponse, error) {
+	visible, err := service.visibleRecipes(entries, userId)
+	if err != nil {
+		return dtos.MealPlanResponse{}, err
+	}
+	response := dtos.MealPlanResponse{From: from, To: to, Entries: make([]dtos.MealPlanEntryResponse, 0, len(entries))}
+	for _, entry := range entries {
+		var recipe *models.Recipe
+		if found, ok := visible[entry.RecipeID]; ok {
+			recipe = &found
+		}
+		response.Entries = append(response.Entries, entryResponse(entry, recipe))
+	}
+	return response, nil
+}
+
+func entryResponse(entry models.MealPlanEntry, recipe *models.Recipe) dtos.MealPlanEntryResponse {
+	response := dtos.MealPlanEntryModelToResponse(entry)
+	if recipe != nil {
+		recipeResponse := dtos.RecipeModelToResponse(*recipe)
+		response.Recipe = &recipeResponse
+	}
+	return response
+}
+
+// planRange completa y valida el rango pedido.
+func planRange(from string, to string) (string, string, error) {
+	switch {
+	case from == "" && to == "":
+		weekFrom, weekTo := mealplan.Week(time.Now())
+		return weekFrom, weekTo, nil
+	case to == "":
+		date, err := mealplan.ParseDate(from)
+		if err != nil {
+			return "", "", err
+		}
+		weekFrom, weekTo := mealplan.Week(date)
+		return weekFrom, weekTo, nil
+	}
+	start, err := mealplan.ParseDate(from)
+	if err != nil {
+		return "", "", err
+	}
+	end, err := mealplan.ParseDate(to)
+	if err != nil {
+		return "", "", err
+	}
+	if end.Before(start) || end.Sub(start).Hours()/24 >= mealplan.MaxRange {
+		return "", "", errors.New("invalid range, max 62 days")
+	}
+	return from, to, nil
+}
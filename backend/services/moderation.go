@@ -0,0 +1,325 @@
+package services
+
+import (
+	"burned/backend/dtos"
+	"burned/backend/models"
+	"burned/backend/pagination"
+	"burned/backend/repositories"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DefaultAutoHideReports es cuántas denuncias abiertas ocultan un contenido
+// hasta que un moderador lo revise; se cambia con MODERATION_AUTO_HIDE_REPORTS.
+const DefaultAutoHideReports = 3
+
+type ModerationServiceInterface interface {
+	ReportComment(commentId string, report dtos.ReportRequest, reporterId string) (dtos.ReportResponse, error)
+	ReportRecipe(recipeId string, report dtos.ReportRequest, reporterId string) (dtos.ReportResponse, error)
+	GetCases(status string, page pagination.Request) (pagination.Page[dtos.ModerationCaseResponse], error)
+	GetCase(id string) (dtos.ModerationCaseResponse, error)
+	Decide(caseId string, decision dtos.ModerationDecisionRequest, moderatorId string) (dtos.ModerationCaseResponse, error)
+	GetDecisions(page pagination.Request) (pagination.Page[dtos.ModerationDecisionResponse], error)
+	GetWarnings(userId string) ([]dtos.UserWarningResponse, error)
+}
+
+type ModerationService struct {
+	moderationRepo repositories.ModerationRepositoryInterface
+	commentRepo    repositories.CommentRepositoryInterface
+	recipeRepo     repositories.RecipeRepositoryInterface
+	userRepo       repositories.UserRepositoryInterface
+	commentService CommentServiceInterface
+	recipeService  RecipeServiceInterface
+	autoHideAt     int // 0 desactiva el ocultamiento automático
+}
+
+func NewModerationService(moderationRepo repositories.ModerationRepositoryInterface, commentRepo repositories.CommentRepositoryInterface, recipeRepo repositories.RecipeRepositoryInterface, userRepo repositories.UserRepositoryInterface, commentService CommentServiceInterface, recipeService RecipeServiceInterface, autoHideAt int) *ModerationService {
+	return &ModerationService{
+		moderationRepo: moderationRepo,
+		commentRepo:    commentRepo,
+		recipeRepo:     recipeRepo,
+		userRepo:       userRepo,
+		commentService: commentService,
+		recipeService:  recipeService,
+		autoHideAt:     autoHideAt,
+	}
+}
+
+// reportTarget es lo que hace falta saber del contenido denunciado.
+type reportTarget struct {
+	kind     string
+	id       primitive.ObjectID
+	recipeId primitive.ObjectID
+	authorId primitive.ObjectID
+	hidden   bool
+}
+
+func (service *ModerationService) ReportComment(commentId string, report dtos.ReportRequest, reporterId string) (dtos.ReportResponse, error) {
+	commentOid, err := primitive.ObjectIDFromHex(commentId)
+	if err != nil {
+		return dtos.ReportResponse{}, errors.New("invalid id")
+	}
+	reporterOid, err := primitive.ObjectIDFromHex(reporterId)
+	if err != nil {
+		return dtos.ReportResponse{}, errors.New("invalid id")
+	}
+	comment, err := service.commentRepo.GetCommentById(commentOid)
+	if err != nil || comment.Deleted {
+		return dtos.ReportResponse{}, errors.New("comment not found")
+	}
+	recipe, err := service.recipeRepo.GetRecipeById(comment.RecipeID)
+	if err != nil || !visibleTo(recipe, reporterOid) {
+		return dtos.ReportResponse{}, errors.New("comment not found")
+	}
+	target := reportTarget{kind: models.TargetComment, id: comment.ID, recipeId: comment.RecipeID, authorId: comment.UserID, hidden: comment.Hidden}
+	return service.report(target, report, reporterOid)
+}
+
+func (service *ModerationService) ReportRecipe(recipeId string, report dtos.ReportRequest, reporterId string) (dtos.ReportResponse, error) {
+	recipeOid, err := primitive.ObjectIDFromHex(recipeId)
+	if err != nil {
+		return dtos.ReportResponse{}, errors.New("invalid id")
+	}
+	reporterOid, err := primitive.ObjectIDFromHex(reporterId)
+	if err != nil {
+		return dtos.ReportResponse{}, errors.New("invalid id")
+	}
+	recipe, err := service.recipeRepo.GetRecipeById(recipeOid)
+	if err != nil || !visibleTo(recipe, reporterOid) {
+		return dtos.ReportResponse{}, errors.New("recipe not found")
+	}
+	target := reportTarget{kind: models.TargetRecipe, id: recipe.ID, recipeId: recipe.ID, authorId: recipe.UserID, hidden: recipe.Hidden}
+	return service.report(target, report, reporterOid)
+}
+
+// report guarda la denuncia, la suma al caso abierto del contenido y lo
+// oculta si el caso llegó al umbral.
+func (service *ModerationService) report(target reportTarget, request dtos.ReportRequest, reporterId primitive.ObjectID) (dtos.ReportResponse, error) {
+	if err := request.Validate(); err != nil {
+		return dtos.ReportResponse{}, err
+	}
+	if target.authorId == reporterId {
+		return dtos.ReportResponse{}, errors.New("invalid report, you cannot report your own content")
+	}
+	report, err := service.moderationRepo.CreateReport(models.Report{
+		TargetType: target.kind,
+		TargetID:   target.id,
+		RecipeID:   target.recipeId,
+		ReporterID: reporterId,
+		Reason:     request.Reason,
+		Details:    request.Details,
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		return dtos.ReportResponse{}, err
+	}
+	moderationCase, err := service.moderationRepo.AddCaseReport(report, target.authorId)
+	if err != nil {
+		return dtos.ReportResponse{}, err
+	}
+	report.CaseID = moderationCase.ID
+	if err := service.moderationRepo.SetReportCase(report.ID, moderationCase.ID); err != nil {
+		return dtos.ReportResponse{}, err
+	}
+
+	if service.autoHideAt > 0 && moderationCase.ReportCount >= service.autoHideAt && !moderationCase.AutoHidden && !target.hidden {
+		if err := service.setHidden(target.kind, target.id, true); err != nil {
+			log.Println("⚠️ Aviso: No se pudo ocultar el contenido denunciado", target.id.Hex(), err)
+		} else if err := service.moderationRepo.SetCaseAutoHidden(moderationCase.ID); err != nil {
+			log.Println("⚠️ Aviso: No se pudo marcar el caso como ocultado", moderationCase.ID.Hex(), err)
+		}
+	}
+	return dtos.ReportModelToResponse(report), nil
+}
+
+// GetCases lista la cola; status es "open" (por defecto) o "resolved".
+func (service *ModerationService) GetCases(status string, page pagination.Request) (pagination.Page[dtos.ModerationCaseResponse], error) {
+	if status == "" {
+		status = models.CaseOpen
+	}
+	if status != models.CaseOpen && status != models.CaseResolved {
+		return pagination.Page[dtos.ModerationCaseResponse]{}, errors.New("invalid status, expected open or resolved")
+	}
+	result, err := service.moderationRepo.GetCasesPaged(status, page)
+	if err != nil {
+		return pagination.Page[dtos.ModerationCaseResponse]{}, err
+	}
+	return pagination.Map(result, dtos.ModerationCaseModelToResponse), nil
+}
+
+// GetCase devuelve el caso con el contenido denunciado, las denuncias y las
+// decisiones tomadas.
+func (service *ModerationService) GetCase(id string) (dtos.ModerationCaseResponse, error) {
+	caseOid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return dtos.ModerationCaseResponse{}, errors.New("invalid id")
+	}
+	moderationCase, err := service.moderationRepo.GetCaseById(caseOid)
+	if err != nil {
+		return dtos.ModerationCaseResponse{}, errors.New("case not found")
+	}
+	reports, err := service.moderationRepo.GetReportsByCase(caseOid)
+	if err != nil {
+		return dtos.ModerationCaseResponse{}, err
+	}
+	decisions, err := service.moderationRepo.GetDecisionsByCase(caseOid)
+	if err != nil {
+		return dtos.ModerationCaseResponse{}, err
+	}
+
+	response := dtos.ModerationCaseModelToResponse(moderationCase)
+	target := service.target(moderationCase)
+	response.Target = &target
+	response.Reports = make([]dtos.ReportResponse, 0, len(reports))
+	for _, report := range reports {
+		response.Reports = append(response.Reports, dtos.ReportModelToResponse(report))
+	}
+	response.Decisions = make([]dtos.ModerationDecisionResponse, 0, len(decisions))
+	for _, decision := range decisions {
+		response.Decisions = append(response.Decisions, dtos.ModerationDecisionModelToResponse(decision))
+	}
+	return response, nil
+}
+
+// Decide resuelve un caso abierto con una acción y la deja registrada:
+//   - hide oculta el contenido
+//   - delete lo borra como lo haría un admin (un comentario con respuestas queda como "[deleted]")
+//   - dismiss descarta las denuncias y vuelve a mostrar lo que se había ocultado solo
+//   - warn avisa al autor sin tocar el contenido
+func (service *ModerationService) Decide(caseId string, request dtos.ModerationDecisionRequest, moderatorId string) (dtos.ModerationCaseResponse, error) {
+	caseOid, err := primitive.ObjectIDFromHex(caseId)
+	if err != nil {
+		return dtos.ModerationCaseResponse{}, errors.New("invalid id")
+	}
+	moderatorOid, err := primitive.ObjectIDFromHex(moderatorId)
+	if err != nil {
+		return dtos.ModerationCaseResponse{}, errors.New("invalid id")
+	}
+	moderationCase, err := service.moderationRepo.GetCaseById(caseOid)
+	if err != nil {
+		return dtos.ModerationCaseResponse{}, errors.New("case not found")
+	}
+	now := time.Now()
+	//se cierra primero para que dos moderadores no actúen sobre el mismo caso
+	if err := service.moderationRepo.ResolveCase(caseOid, request.Action, moderatorOid, now); err != nil {
+		return dtos.ModerationCaseResponse{}, err
+	}
+	if err := service.apply(moderationCase, request, moderatorId, now); err != nil {
+		if reopenErr := service.moderationRepo.ReopenCase(caseOid); reopenErr != nil {
+			log.Println("⚠️ Aviso: No se pudo reabrir el caso", caseId, reopenErr)
+		}
+		return dtos.ModerationCaseResponse{}, err
+	}
+	decision := models.ModerationDecision{
+		CaseID:      caseOid,
+		TargetType:  moderationCase.TargetType,
+		TargetID:    moderationCase.TargetID,
+		AuthorID:    moderationCase.AuthorID,
+		ModeratorID: moderatorOid,
+		Action:      request.Action,
+		Note:        request.Note,
+		CreatedAt:   now,
+	}
+	if err := service.moderationRepo.CreateDecision(decision); err != nil {
+		return dtos.ModerationCaseResponse{}, err
+	}
+	return service.GetCase(caseId)
+}
+
+func (service *ModerationService) apply(moderationCase models.ModerationCase, request dtos.ModerationDecisionRequest, moderatorId string, now time.Time) error {
+	switch request.Action {
+	case models.ActionHide:
+		return service.setHidden(moderationCase.TargetType, moderationCase.TargetID, true)
+	case models.ActionDismiss:
+		if moderationCase.AutoHidden {
+			return service.setHidden(moderationCase.TargetType, moderationCase.TargetID, false)
+		}
+		return nil
+	case models.ActionWarn:
+		return service.moderationRepo.CreateWarning(models.UserWarning{
+			UserID:     moderationCase.AuthorID,
+			CaseID:     moderationCase.ID,
+			TargetType: moderationCase.TargetType,
+			TargetID:   moderationCase.TargetID,
+			Note:       request.Note,
+			CreatedAt:  now,
+		})
+	case models.ActionDelete:
+		var err error
+		if moderationCase.TargetType == models.TargetComment {
+			err = service.commentService.DeleteComment(moderationCase.TargetID.Hex(), moderatorId, "admin")
+		} else {
+			_, err = service.recipeService.DeleteRecipe(moderationCase.TargetID.Hex(), moderatorId, "admin", false)
+		}
+		//si ya lo había borrado su autor no queda nada por hacer
+		if err != nil && (err.Error() == "comment not found" || err.Error() == "recipe not found") {
+			return nil
+		}
+		return err
+	}
+	return errors.New("invalid action: " + request.Action)
+}
+
+func (service *ModerationService) setHidden(kind string, id primitive.ObjectID, hidden bool) error {
+	if kind == models.TargetComment {
+		return service.commentRepo.SetCommentHidden(id, hidden)
+	}
+	return service.recipeRepo.SetHidden(id, hidden)
+}
+
+// target arma la vista del contenido denunciado para el moderador, con el
+// texto real aunque esté oculto.
+func (service *ModerationService) target(moderationCase models.ModerationCase) dtos.ModerationTargetResponse {
+	var target dtos.ModerationTargetResponse
+	if moderationCase.TargetType == models.TargetComment {
+		comment, err := service.commentRepo.GetCommentById(moderationCase.TargetID)
+		if err != nil || comment.Deleted {
+			return dtos.ModerationTargetResponse{Missing: true}
+		}
+		target.Text = comment.Text
+		target.Hidden = comment.Hidden
+		target.AuthorName = comment.UserName
+		return target
+	}
+	recipe, err := service.recipeRepo.GetRecipeById(moderationCase.TargetID)
+	if err != nil {
+		return dtos.ModerationTargetResponse{Missing: true}
+	}
+	target.Title = recipe.Title
+	target.Text = recipe.Description
+	target.Hidden = recipe.Hidden
+	target.AuthorName = models.DeletedUserName
+	if user, err := service.userRepo.GetUserById(recipe.UserID); err == nil {
+		target.AuthorName = user.Name
+	}
+	return target
+}
+
+// GetDecisions es el registro de todas las decisiones de moderación.
+func (service *ModerationService) GetDecisions(page pagination.Request) (pagination.Page[dtos.ModerationDecisionResponse], error) {
+	result, err := service.moderationRepo.GetDecisionsPaged(page)
+	if err != nil {
+		return pagination.Page[dtos.ModerationDecisionResponse]{}, err
+	}
+	return pagination.Map(result, dtos.ModerationDecisionModelToResponse), nil
+}
+
+// GetWarnings devuelve los avisos de moderación que recibió el usuario.
+func (service *ModerationService) GetWarnings(userId string) ([]dtos.UserWarningResponse, error) {
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, errors.New("invalid id")
+	}
+	warnings, err := service.moderationRepo.GetWarningsByUser(userOid)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]dtos.UserWarningResponse, 0, len(warnings))
+	for _, warning := range warnings {
+		responses = append(responses, dtos.UserWarningModelToResponse(warning))
+	}
+	return responses, nil
+}
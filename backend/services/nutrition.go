@@ -0,0 +1,174 @@
+package services
+
+import (
+	"burned/backend/dtos"
+	"burned/backend/models"
+	"burned/backend/nutrition"
+	"burned/backend/pagination"
+	"burned/backend/repositories"
+	"burned/backend/shopping"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type NutritionServiceInterface interface {
+	LoadAliases() error
+	Annotate(recipe *models.Recipe) []nutrition.Miss
+	RecordUnmatched(recipeId primitive.ObjectID, misses []nutrition.Miss)
+	GetUnmatched(page pagination.Request) (pagination.Page[models.UnmatchedIngredient], error)
+	DismissUnmatched(id string) error
+	GetFoods() []string
+	AddAlias(alias dtos.NutritionAliasRequest, adminId string) (dtos.NutritionAliasResponse, error)
+}
+
+type NutritionService struct {
+	nutritionRepo repositories.NutritionRepositoryInterface
+	recipeRepo    repositories.RecipeRepositoryInterface
+	foods         *nutrition.Database
+}
+
+func NewNutritionService(nutritionRepo repositories.NutritionRepositoryInterface, recipeRepo repositories.RecipeRepositoryInterface, foods *nutrition.Database) *NutritionService {
+	return &NutritionService{nutritionRepo: nutritionRepo, recipeRepo: recipeRepo, foods: foods}
+}
+
+// LoadAliases suma a la base los alias cargados por los admins. Un alias
+// cuyo alimento ya no está en la base se ignora.
+func (service *NutritionService) LoadAliases() error {
+	aliases, err := service.nutritionRepo.GetAliases()
+	if err != nil {
+		return err
+	}
+	for _, alias := range aliases {
+		if err := service.foods.AddAlias(alias.Name, alias.Food); err != nil {
+			log.Printf("⚠️ Aviso: alias nutricional %q ignorado: %v", alias.Name, err)
+		}
+	}
+	return nil
+}
+
+// Annotate calcula la información nutricional de la receta antes de
+// guardarla y devuelve los ingredientes que no se pudieron calcular.
+func (service *NutritionService) Annotate(recipe *models.Recipe) []nutrition.Miss {
+	result, misses := nutrition.Calculate(service.foods, recipe.Ingredients, recipe.Servings)
+	recipe.Nutrition = &result
+	return misses
+}
+
+// RecordUnmatched registra para revisión los ingredientes que no se pudieron
+// calcular. No corta el guardado de la receta: los errores solo se loguean.
+func (service *NutritionService) RecordUnmatched(recipeId primitive.ObjectID, misses []nutrition.Miss) {
+	now := time.Now()
+	for _, miss := range misses {
+		if miss.Key == "" {
+			continue
+		}
+		unmatched := models.UnmatchedIngredient{
+			Key:          miss.Key,
+			Name:         miss.Name,
+			Reason:       miss.Reason,
+			Unit:         miss.Unit,
+			LastRecipeID: recipeId,
+			LastSeen:     now,
+		}
+		if err := service.nutritionRepo.RecordUnmatched(unmatched); err != nil {
+			log.Println("⚠️ Aviso: No se pudo registrar el ingrediente sin datos nutricionales:", err)
+		}
+	}
+}
+
+func (service *NutritionService) GetUnmatched(page pagination.Request) (pagination.Page[models.UnmatchedIngredient], error) {
+	return service.nutritionRepo.GetUnmatchedPaged(page)
+}
+
+// DismissUnmatched quita un nombre de la revisión sin crear un alias (por
+// ejemplo, "agua" o un error de tipeo). Vuelve a aparecer si se repite.
+func (service *NutritionService) DismissUnmatched(id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.New("invalid id")
+	}
+	deleted, err := service.nutritionRepo.DeleteUnmatched(oid)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return errors.New("unmatched ingredient not found")
+	}
+	return nil
+}
+
+func (service *NutritionService) GetFoods() []string {
+	return service.foods.Foods()
+}
+
+// AddAlias resuelve un nombre como un alimento de la base, lo saca de la
+// revisión y recalcula las recetas que lo usan.
+func (service *NutritionService) AddAlias(request dtos.NutritionAliasRequest, adminId string) (dtos.NutritionAliasResponse, error) {
+	if err := request.Validate(); err != nil {
+		return dtos.NutritionAliasResponse{}, err
+	}
+	adminOid, err := primitive.ObjectIDFromHex(adminId)
+	if err != nil {
+		return dtos.NutritionAliasResponse{}, errors.New("invalid id")
+	}
+	key := shopping.Key(request.Name)
+	if key == "" {
+		return dtos.NutritionAliasResponse{}, errors.New("invalid alias")
+	}
+	food, ok := service.foods.Lookup(request.Food)
+	if !ok {
+		return dtos.NutritionAliasResponse{}, errors.New("food not found")
+	}
+
+	alias := models.NutritionAlias{
+		Key:       key,
+		Name:      request.Name,
+		Food:      food.Name,
+		CreatedBy: adminOid,
+		CreatedAt: time.Now(),
+	}
+	if err := service.nutritionRepo.SaveAlias(alias); err != nil {
+		return dtos.NutritionAliasResponse{}, err
+	}
+	if err := service.foods.AddAlias(alias.Name, alias.Food); err != nil {
+		return dtos.NutritionAliasResponse{}, err
+	}
+
+	if _, err := service.nutritionRepo.DeleteUnmatchedByKey(key); err != nil {
+		return dtos.NutritionAliasResponse{}, err
+	}
+	if request.UnmatchedID != "" {
+		if unmatchedOid, err := primitive.ObjectIDFromHex(request.UnmatchedID); err == nil {
+			if _, err := service.nutritionRepo.DeleteUnmatched(unmatchedOid); err != nil {
+				return dtos.NutritionAliasResponse{}, err
+			}
+		}
+	}
+
+	response := dtos.NutritionAliasModelToResponse(alias)
+	response.Updated = service.recalculate(key)
+	return response, nil
+}
+
+// recalculate vuelve a calcular las recetas que tienen un ingrediente con la
+// frase dada. Devuelve cuántas se actualizaron.
+func (service *NutritionService) recalculate(term string) int {
+	recipes, err := service.recipeRepo.GetRecipesByIngredientTerm(term)
+	if err != nil {
+		log.Println("⚠️ Aviso: No se pudieron recalcular las recetas del alias:", err)
+		return 0
+	}
+	updated := 0
+	for _, recipe := range recipes {
+		service.Annotate(&recipe)
+		if err := service.recipeRepo.SetNutrition(recipe.ID, *recipe.Nutrition); err != nil {
+			log.Println("⚠️ Aviso: No se pudo recalcular la receta", recipe.ID.Hex(), err)
+			continue
+		}
+		updated++
+	}
+	return updated
+}
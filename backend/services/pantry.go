@@ -0,0 +1,249 @@
+package services
+
+import (
+	"burned/backend/dtos"
+	"burned/backend/mealplan"
+	"burned/backend/models"
+	"burned/backend/pagination"
+	"burned/backend/repositories"
+	"burned/backend/shopping"
+	"burned/backend/units"
+	"errors"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// defaultExpiringDays es el aviso de vencimiento si no se pide otro.
+const defaultExpiringDays = 3
+
+type PantryServiceInterface interface {
+	GetItems(userId string) ([]dtos.PantryItemResponse, error)
+	CreateItem(item dtos.PantryItemRequest, userId string) (dtos.PantryItemResponse, error)
+	UpdateItem(id string, item dtos.PantryItemRequest, userId string) (dtos.PantryItemResponse, error)
+	DeleteItem(id string, userId string) error
+	SearchRecipes(userId string, filters dtos.RecipeSearchRequest, options dtos.PantrySearchRequest, page pagination.Request) (pagination.Page[dtos.PantryRecipeResponse], error)
+}
+
+type PantryService struct {
+	pantryRepo repositories.PantryRepositoryInterface
+	recipeRepo repositories.RecipeRepositoryInterface
+}
+
+func NewPantryService(pantryRepo repositories.PantryRepositoryInterface, recipeRepo repositories.RecipeRepositoryInterface) *PantryService {
+	return &PantryService{pantryRepo: pantryRepo, recipeRepo: recipeRepo}
+}
+
+// GetItems devuelve la despensa; lo que vence antes aparece primero.
+func (service *PantryService) GetItems(userId string) ([]dtos.PantryItemResponse, error) {
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, errors.New("invalid id")
+	}
+	items, err := service.pantryRepo.GetItemsByUser(userOid)
+	if err != nil {
+		return nil, err
+	}
+	today, until := expiryWindow(defaultExpiringDays)
+	response := make([]dtos.PantryItemResponse, 0, len(items))
+	// primero los que vencen, por fecha; el repositorio ya los trae por nombre
+	for _, item := range items {
+		if item.ExpiresAt != "" {
+			response = append(response, dtos.PantryItemModelToResponse(item, today, until))
+		}
+	}
+	sort.SliceStable(response, func(i, j int) bool { return response[i].ExpiresAt < response[j].ExpiresAt })
+	for _, item := range items {
+		if item.ExpiresAt == "" {
+			response = append(response, dtos.PantryItemModelToResponse(item, today, until))
+		}
+	}
+	return response, nil
+}
+
+func (service *PantryService) CreateItem(item dtos.PantryItemRequest, userId string) (dtos.PantryItemResponse, error) {
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return dtos.PantryItemResponse{}, errors.New("invalid id")
+	}
+	if err := item.Validate(); err != nil {
+		return dtos.PantryItemResponse{}, err
+	}
+	now := time.Now()
+	created, err := service.pantryRepo.CreateItem(models.PantryItem{
+		UserID:    userOid,
+		Name:      item.Name,
+		Key:       shopping.Key(item.Name),
+		Quantity:  item.Quantity,
+		Unit:      item.Unit,
+		ExpiresAt: item.ExpiresAt,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return dtos.PantryItemResponse{}, err
+	}
+	today, until := expiryWindow(defaultExpiringDays)
+	return dtos.PantryItemModelToResponse(created, today, until), nil
+}
+
+func (service *PantryService) UpdateItem(id string, item dtos.PantryItemRequest, userId string) (dtos.PantryItemResponse, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return dtos.PantryItemResponse{}, errors.New("invalid id")
+	}
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return dtos.PantryItemResponse{}, errors.New("invalid id")
+	}
+	if err := item.Validate(); err != nil {
+		return dtos.PantryItemResponse{}, err
+	}
+	current, err := service.pantryRepo.GetItemById(oid)
+	if err != nil || current.UserID != userOid {
+		return dtos.PantryItemResponse{}, errors.New("pantry item not found")
+	}
+	current.Name = item.Name
+	current.Key = shopping.Key(item.Name)
+	current.Quantity = item.Quantity
+	current.Unit = item.Unit
+	current.ExpiresAt = item.ExpiresAt
+	current.UpdatedAt = time.Now()
+	matched, err := service.pantryRepo.UpdateItem(current)
+	if err != nil {
+		return dtos.PantryItemResponse{}, err
+	}
+	if matched == 0 {
+		return dtos.PantryItemResponse{}, errors.New("pantry item not found")
+	}
+	today, until := expiryWindow(defaultExpiringDays)
+	return dtos.PantryItemModelToResponse(current, today, until), nil
+}
+
+func (service *PantryService) DeleteItem(id string, userId string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.New("invalid id")
+	}
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return errors.New("invalid id")
+	}
+	deleted, err := service.pantryRepo.DeleteItem(oid, userOid)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return errors.New("pantry item not found")
+	}
+	return nil
+}
+
+// SearchRecipes busca recetas públicas que se pueden cocinar con la
+// despensa del usuario, las que más ingredientes tienen primero. Lo vencido
+// no cuenta como disponible y lo que vence dentro de ExpiringDays (3 por
+// defecto) sube la receta en el orden.
+func (service *PantryService) SearchRecipes(userId string, filters dtos.RecipeSearchRequest, options dtos.PantrySearchRequest, page pagination.Request) (pagination.Page[dtos.PantryRecipeResponse], error) {
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return pagination.Page[dtos.PantryRecipeResponse]{}, errors.New("invalid id")
+	}
+	if err := filters.Validate(); err != nil {
+		return pagination.Page[dtos.PantryRecipeResponse]{}, err
+	}
+	items, err := service.pantryRepo.GetItemsByUser(userOid)
+	if err != nil {
+		return pagination.Page[dtos.PantryRecipeResponse]{}, err
+	}
+
+	days := defaultExpiringDays
+	if options.ExpiringDays != nil {
+		days = *options.ExpiringDays
+	}
+	today, until := expiryWindow(days)
+	have := map[string]string{}
+	expiring := map[string]string{}
+	for _, item := range items {
+		if item.ExpiresAt != "" && item.ExpiresAt < today {
+			continue
+		}
+		have[item.Key] = item.Name
+		if item.ExpiresAt != "" && item.ExpiresAt <= until {
+			expiring[item.Key] = item.Name
+		}
+	}
+	if len(have) == 0 {
+		return pagination.Page[dtos.PantryRecipeResponse]{Items: []dtos.PantryRecipeResponse{}}, nil
+	}
+
+	maxMissing := -1
+	if options.MaxMissing != nil {
+		maxMissing = *options.MaxMissing
+	}
+	result, err := service.recipeRepo.GetRecipesByPantry(filters, mapKeys(have), mapKeys(expiring), maxMissing, page)
+	if err != nil {
+		return pagination.Page[dtos.PantryRecipeResponse]{}, err
+	}
+	return pagination.Map(result, func(recipe models.Recipe) dtos.PantryRecipeResponse {
+		return pantryMatch(recipe, have, expiring)
+	}), nil
+}
+
+// pantryMatch repite en Go la cuenta que hace GetRecipesByPantry para
+// informar qué falta y qué ingredientes por vencer se usan.
+func pantryMatch(recipe models.Recipe, have map[string]string, expiring map[string]string) dtos.PantryRecipeResponse {
+	response := dtos.PantryRecipeResponse{
+		Recipe:   dtos.RecipeModelToResponse(recipe),
+		Missing:  []string{},
+		Expiring: []string{},
+	}
+	for _, ingredient := range recipe.Ingredients {
+		terms := ingredient.Terms
+		if terms == nil {
+			terms = shopping.Terms(ingredient.Name)
+		}
+		found := false
+		for _, term := range terms {
+			if _, ok := have[term]; ok {
+				found = true
+			}
+			if name, ok := expiring[term]; ok {
+				response.Expiring = appendUnique(response.Expiring, name)
+			}
+		}
+		if ingredient.Optional || ingredient.Unit == units.CodeToTaste {
+			continue
+		}
+		response.Needed++
+		if found {
+			response.Matched++
+		} else {
+			response.Missing = append(response.Missing, ingredient.Name)
+		}
+	}
+	return response
+}
+
+// expiryWindow devuelve hoy y el último día del aviso de vencimiento, como YYYY-MM-DD.
+func expiryWindow(days int) (string, string) {
+	now := time.Now()
+	return mealplan.FormatDate(now), mealplan.FormatDate(now.AddDate(0, 0, days))
+}
+
+func mapKeys(set map[string]string) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func appendUnique(list []string, value string) []string {
+	for _, existing := range list {
+		if existing == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
@@ -1,8 +1,16 @@
 package services
 
 import (
+	"burned/backend/dietary"
 	"burned/backend/dtos"
+	"burned/backend/models"
+	"burned/backend/nutrition"
+	"burned/backend/pagination"
 	"burned/backend/repositories"
+	"burned/backend/scaling"
+	"burned/backend/screening"
+	"burned/backend/shopping"
+	"burned/backend/timeline"
 	"errors"
 	"time"
 
@@ -12,26 +20,38 @@ import (
 type RecipeServiceInterface interface {
 	CreateRecipe(recipe dtos.RecipeRequest, idUser string) (dtos.RecipeResponse, error)
 	UpdateRecipe(recipe dtos.RecipeRequest, id string, requesterId string, requesterRole string) (dtos.RecipeResponse, error)
-	DeleteRecipe(id string, requesterId string, requesterRole string) error
-	GetRecipes(filters dtos.RecipeSearchRequest) ([]dtos.RecipeResponse, error)
-	GetRecipeById(id string) (dtos.RecipeResponse, error)
-	GetRecipesByUser(id string) ([]dtos.RecipeResponse, error)
-	GetAll() ([]dtos.RecipeResponse, error)
+	DeleteRecipe(id string, requesterId string, requesterRole string, dryRun bool) (dtos.DeletionReport, error)
+	GetRecipes(filters dtos.RecipeSearchRequest, page pagination.Request) (pagination.Page[dtos.RecipeResponse], error)
+	GetRecipeById(id string, requesterId string) (dtos.RecipeResponse, error)
+	GetRecipesByUser(id string, page pagination.Request) (pagination.Page[dtos.RecipeResponse], error)
+	GetAll(page pagination.Request) (pagination.Page[dtos.RecipeResponse], error)
 	GetTopRecipes() ([]dtos.RecipeResponse, error)
+	GetScaledRecipe(id string, servings int, system string, requesterId string) (dtos.RecipeResponse, error)
+	ForkRecipe(id string, requesterId string) (dtos.RecipeResponse, error)
+	GetForks(id string, descendants bool, page pagination.Request) (pagination.Page[dtos.RecipeResponse], error)
 }
 
 type RecipeService struct {
-	recipeRepo repositories.RecipeRepositoryInterface
-	userRepo   repositories.UserRepositoryInterface
+	recipeRepo       repositories.RecipeRepositoryInterface
+	userRepo         repositories.UserRepositoryInterface
+	revisionRepo     repositories.RecipeRevisionRepositoryInterface
+	savedRecipeRepo  repositories.SavedRecipeRepositoryInterface
+	deletionService  DeletionServiceInterface
+	nutritionService NutritionServiceInterface
+	screeningService ScreeningServiceInterface
+	feedService      FeedServiceInterface
 }
 
-func NewRecipeService(repo repositories.RecipeRepositoryInterface, userRepo repositories.UserRepositoryInterface) *RecipeService {
-	return &RecipeService{recipeRepo: repo, userRepo: userRepo}
+func NewRecipeService(repo repositories.RecipeRepositoryInterface, userRepo repositories.UserRepositoryInterface, revisionRepo repositories.RecipeRevisionRepositoryInterface, savedRecipeRepo repositories.SavedRecipeRepositoryInterface, deletionService DeletionServiceInterface, nutritionService NutritionServiceInterface, screeningService ScreeningServiceInterface, feedService FeedServiceInterface) *RecipeService {
+	return &RecipeService{recipeRepo: repo, userRepo: userRepo, revisionRepo: revisionRepo, savedRecipeRepo: savedRecipeRepo, deletionService: deletionService, nutritionService: nutritionService, screeningService: screeningService, feedService: feedService}
 }
 
 func (service *RecipeService) CreateRecipe(recipe dtos.RecipeRequest, idUser string) (dtos.RecipeResponse, error) {
-	//Verifica validez de parametros
-	if recipe.Description == "" || recipe.DificultyLevel == "" || recipe.Ingredients == nil || recipe.Step == nil || recipe.Title == "" || recipe.TotalTime <= 0 || recipe.Visibility == "" {
+	//Verifica validez de parametros (interpreta las líneas de ingredientes y normaliza unidades)
+	if err := recipe.Validate(); err != nil {
+		return dtos.RecipeResponse{}, err
+	}
+	if recipe.Description == "" || recipe.DificultyLevel == "" || recipe.Ingredients == nil || recipe.Step == nil || recipe.Title == "" || recipe.Visibility == "" {
 		return dtos.RecipeResponse{}, errors.New("data entered incorrectly")
 	}
 	oid, err := primitive.ObjectIDFromHex(idUser)
@@ -45,6 +65,17 @@ func (service *RecipeService) CreateRecipe(recipe dtos.RecipeRequest, idUser str
 	recipeModel := dtos.RecipeRequestToModel(recipe)
 	recipeModel.CreatedAt = time.Now()
 	recipeModel.UserID = oid
+	deriveRecipeFields(&recipeModel)
+	if recipeModel.TotalTime <= 0 {
+		return dtos.RecipeResponse{}, errors.New("data entered incorrectly")
+	}
+	//moderación automática: lo rechazado no se guarda y lo retenido se guarda oculto
+	screened, err := service.screeningService.Screen(recipeContent(recipeModel))
+	if err != nil {
+		return dtos.RecipeResponse{}, err
+	}
+	recipeModel.Hidden = screened.Verdict == screening.Hold
+	misses := service.nutritionService.Annotate(&recipeModel)
 	//añade el id en la bdd al objeto para devolverlo al usuario
 	insertedRecipe, err := service.recipeRepo.CreateRecipe(recipeModel)
 	insertedOid, ok := insertedRecipe.InsertedID.(primitive.ObjectID)
@@ -52,12 +83,25 @@ func (service *RecipeService) CreateRecipe(recipe dtos.RecipeRequest, idUser str
 		return dtos.RecipeResponse{}, errors.New("invalid id")
 	}
 	recipeModel.ID = insertedOid
+	service.nutritionService.RecordUnmatched(insertedOid, misses)
+	service.screeningService.Record(recipeContent(recipeModel), insertedOid, screened)
+	if recipeModel.Visibility == "public" {
+		service.feedService.PublishRecipe(recipeModel)
+	}
+	//la versión original queda como revisión 1 del historial
+	if err := recordRevision(service.revisionRepo, nil, recipeModel, oid, 0); err != nil {
+		return dtos.RecipeResponse{}, err
+	}
 	recipeResponse := dtos.RecipeModelToResponse(recipeModel)
 	recipeResponse.UserName = user.Name
 	return recipeResponse, nil
 }
 func (service *RecipeService) UpdateRecipe(recipe dtos.RecipeRequest, id string, requesterId string, requesterRole string) (dtos.RecipeResponse, error) {
-	if recipe.Description == "" || recipe.DificultyLevel == "" || recipe.Ingredients == nil || recipe.Step == nil || recipe.Title == "" || recipe.TotalTime <= 0 || recipe.Visibility == "" {
+	//interpreta las líneas de ingredientes y normaliza unidades antes de verificar
+	if err := recipe.Validate(); err != nil {
+		return dtos.RecipeResponse{}, err
+	}
+	if recipe.Description == "" || recipe.DificultyLevel == "" || recipe.Ingredients == nil || recipe.Step == nil || recipe.Title == "" || recipe.Visibility == "" {
 		return dtos.RecipeResponse{}, errors.New("data entered incorrectly")
 	}
 	oid, err := primitive.ObjectIDFromHex(id)
@@ -83,53 +127,87 @@ func (service *RecipeService) UpdateRecipe(recipe dtos.RecipeRequest, id string,
 	recipeModel.UserID = currentRecipe.UserID
 	recipeModel.CreatedAt = currentRecipe.CreatedAt
 	recipeModel.AverageRating = currentRecipe.AverageRating
+	recipeModel.SavedCount = currentRecipe.SavedCount
 	recipeModel.Visibility = recipe.Visibility
+	recipeModel.Hidden = currentRecipe.Hidden
+	deriveRecipeFields(&recipeModel)
+	if recipeModel.TotalTime <= 0 {
+		return dtos.RecipeResponse{}, errors.New("data entered incorrectly")
+	}
+	//una edición retenida oculta la receta; una limpia no la vuelve a mostrar,
+	//eso lo decide un moderador
+	screened, err := service.screeningService.Screen(recipeContent(recipeModel))
+	if err != nil {
+		return dtos.RecipeResponse{}, err
+	}
+	if screened.Verdict == screening.Hold {
+		recipeModel.Hidden = true
+	}
+	misses := service.nutritionService.Annotate(&recipeModel)
 	_, err = service.recipeRepo.UpdateRecipe(recipeModel)
 	if err != nil {
 		return dtos.RecipeResponse{}, err
 	}
+	service.nutritionService.RecordUnmatched(oid, misses)
+	if screened.Verdict == screening.Hold && !currentRecipe.Hidden {
+		if err := service.recipeRepo.SetHidden(oid, true); err != nil {
+			return dtos.RecipeResponse{}, err
+		}
+	}
+	service.screeningService.Record(recipeContent(recipeModel), oid, screened)
+	//una receta que se hace pública le llega a los seguidores como nueva
+	if recipeModel.Visibility == "public" && currentRecipe.Visibility != "public" {
+		service.feedService.PublishRecipe(recipeModel)
+	}
+	editorId, err := primitive.ObjectIDFromHex(requesterId)
+	if err != nil {
+		return dtos.RecipeResponse{}, errors.New("invalid id")
+	}
+	if err := recordRevision(service.revisionRepo, &currentRecipe, recipeModel, editorId, 0); err != nil {
+		return dtos.RecipeResponse{}, err
+	}
 
 	recipeResponse := dtos.RecipeModelToResponse(recipeModel)
 	return recipeResponse, nil
 }
 
-func (service *RecipeService) DeleteRecipe(id string, requesterId string, requesterRole string) error {
+func (service *RecipeService) DeleteRecipe(id string, requesterId string, requesterRole string, dryRun bool) (dtos.DeletionReport, error) {
 	oid, ok := primitive.ObjectIDFromHex(id)
 	if ok != nil {
-		return errors.New("invalid id")
+		return dtos.DeletionReport{}, errors.New("invalid id")
 	}
 	currentRecipe, err := service.recipeRepo.GetRecipeById(oid)
 	if err != nil {
-		return errors.New("recipe not found")
+		return dtos.DeletionReport{}, errors.New("recipe not found")
 	}
 
 	isOwner := currentRecipe.UserID.Hex() == requesterId
 	isAdmin := requesterRole == "admin"
 
 	if !isOwner && !isAdmin {
-		return errors.New("unauthorized: you cannot edit this recipe")
+		return dtos.DeletionReport{}, errors.New("unauthorized: you cannot edit this recipe")
 	}
-	//verificamos la cantidad de documentos eliminados, si es 0 ha habido error
-	result, err := service.recipeRepo.DeleteRecipe(oid)
-	if result.DeletedCount == 0 {
-		return errors.New("recipe not found")
-	}
-	return err
+	//borramos la receta junto con sus comentarios, votos y guardados
+	return service.deletionService.DeleteRecipe(oid, dryRun)
 }
 
-func (service *RecipeService) GetRecipes(filters dtos.RecipeSearchRequest) ([]dtos.RecipeResponse, error) {
-	result, err := service.recipeRepo.GetRecipes(filters)
-	if err != nil {
-		return []dtos.RecipeResponse{}, errors.New("recipes not found")
+func (service *RecipeService) GetRecipes(filters dtos.RecipeSearchRequest, page pagination.Request) (pagination.Page[dtos.RecipeResponse], error) {
+	if err := filters.Validate(); err != nil {
+		return pagination.Page[dtos.RecipeResponse]{}, err
 	}
-	var recipes []dtos.RecipeResponse
-	for _, recipe := range result {
-		recipes = append(recipes, dtos.RecipeModelToResponse(recipe))
+	result, err := service.recipeRepo.GetRecipesPaged(filters, page)
+	if err != nil {
+		if pagination.IsRequestError(err) {
+			return pagination.Page[dtos.RecipeResponse]{}, err
+		}
+		return pagination.Page[dtos.RecipeResponse]{}, errors.New("recipes not found")
 	}
-	return recipes, nil
+	return pagination.Map(result, dtos.RecipeModelToResponse), nil
 }
 
-func (service *RecipeService) GetRecipeById(id string) (dtos.RecipeResponse, error) {
+// GetRecipeById devuelve el detalle de la receta. Si quien consulta la tiene
+// guardada con notas, se agregan en PersonalNotes (solo para esa persona).
+func (service *RecipeService) GetRecipeById(id string, requesterId string) (dtos.RecipeResponse, error) {
 	oid, ok := primitive.ObjectIDFromHex(id)
 	if ok != nil {
 		return dtos.RecipeResponse{}, errors.New("invalid id")
@@ -138,6 +216,10 @@ func (service *RecipeService) GetRecipeById(id string) (dtos.RecipeResponse, err
 	if err != nil {
 		return dtos.RecipeResponse{}, err
 	}
+	//una receta ocultada por moderación solo la sigue viendo su autor
+	if result.Hidden && result.UserID.Hex() != requesterId {
+		return dtos.RecipeResponse{}, errors.New("recipe not found")
+	}
 	response := dtos.RecipeModelToResponse(result)
 	user, err := service.userRepo.GetUserById(result.UserID)
 	if err == nil {
@@ -145,36 +227,47 @@ func (service *RecipeService) GetRecipeById(id string) (dtos.RecipeResponse, err
 	} else {
 		response.UserName = "Unknown"
 	}
+	if forks, err := service.recipeRepo.CountForks(oid); err == nil {
+		response.ForkCount = forks
+	}
+	//la atribución se conserva aunque la original se haya borrado
+	if response.ForkedFrom != nil {
+		if _, err := service.recipeRepo.GetRecipeById(response.ForkedFrom.RecipeID); err != nil {
+			response.ForkedFrom.Deleted = true
+		}
+	}
+	if requester, err := primitive.ObjectIDFromHex(requesterId); err == nil {
+		if notes, err := service.savedRecipeRepo.GetNotes(requester, oid); err == nil {
+			response.PersonalNotes = overlayNotes(notes, result)
+		}
+	}
 	return response, nil
 
 }
-func (service *RecipeService) GetRecipesByUser(id string) ([]dtos.RecipeResponse, error) {
+func (service *RecipeService) GetRecipesByUser(id string, page pagination.Request) (pagination.Page[dtos.RecipeResponse], error) {
 	oid, ok := primitive.ObjectIDFromHex(id)
 	if ok != nil {
-		return []dtos.RecipeResponse{}, errors.New("invalid id")
+		return pagination.Page[dtos.RecipeResponse]{}, errors.New("invalid id")
 	}
-	result, err := service.recipeRepo.GetRecipesByUser(oid)
+	result, err := service.recipeRepo.GetRecipesByUserPaged(oid, page)
 	if err != nil {
-		return []dtos.RecipeResponse{}, errors.New("recipes not found")
+		if pagination.IsRequestError(err) {
+			return pagination.Page[dtos.RecipeResponse]{}, err
+		}
+		return pagination.Page[dtos.RecipeResponse]{}, errors.New("recipes not found")
 	}
-	var recipes []dtos.RecipeResponse
-	for _, recipe := range result {
-		recipes = append(recipes, dtos.RecipeModelToResponse(recipe))
-	}
-	return recipes, nil
+	return pagination.Map(result, dtos.RecipeModelToResponse), nil
 }
 
-func (service *RecipeService) GetAll() ([]dtos.RecipeResponse, error) {
-	// Aquí podrías agregar lógica extra si fuera necesario antes de llamar a la DB
-	result, err := service.recipeRepo.GetAll()
+func (service *RecipeService) GetAll(page pagination.Request) (pagination.Page[dtos.RecipeResponse], error) {
+	result, err := service.recipeRepo.GetAllPaged(page)
 	if err != nil {
-		return []dtos.RecipeResponse{}, errors.New("recipes not found")
+		if pagination.IsRequestError(err) {
+			return pagination.Page[dtos.RecipeResponse]{}, err
+		}
+		return pagination.Page[dtos.RecipeResponse]{}, errors.New("recipes not found")
 	}
-	var recipes []dtos.RecipeResponse
-	for _, recipe := range result {
-		recipes = append(recipes, dtos.RecipeModelToResponse(recipe))
-	}
-	return recipes, nil
+	return pagination.Map(result, dtos.RecipeModelToResponse), nil
 }
 
 func (service *RecipeService) GetTopRecipes() ([]dtos.RecipeResponse, error) {
@@ -190,3 +283,62 @@ func (service *RecipeService) GetTopRecipes() ([]dtos.RecipeResponse, error) {
 	}
 	return recipes, nil
 }
+
+// GetScaledRecipe devuelve la receta con las cantidades recalculadas para
+// otra cantidad de porciones y, si se pide, expresadas en otro sistema.
+func (service *RecipeService) GetScaledRecipe(id string, servings int, system string, requesterId string) (dtos.RecipeResponse, error) {
+	targetSystem, err := scaling.ParseSystem(system)
+	if err != nil {
+		return dtos.RecipeResponse{}, err
+	}
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return dtos.RecipeResponse{}, errors.New("invalid id")
+	}
+	recipe, err := service.recipeRepo.GetRecipeById(oid)
+	if err != nil {
+		return dtos.RecipeResponse{}, errors.New("recipe not found")
+	}
+	//las privadas y las ocultas por moderación solo las escala su autor
+	requesterOid, _ := primitive.ObjectIDFromHex(requesterId)
+	if !visibleTo(recipe, requesterOid) {
+		return dtos.RecipeResponse{}, errors.New("recipe not found")
+	}
+	response, err := service.GetRecipeById(id, requesterId)
+	if err != nil {
+		return dtos.RecipeResponse{}, err
+	}
+	if response.Servings <= 0 {
+		return dtos.RecipeResponse{}, errors.New("recipe has no servings defined")
+	}
+	if servings <= 0 {
+		servings = response.Servings
+	}
+	factor, err := scaling.Factor(response.Servings, servings)
+	if err != nil {
+		return dtos.RecipeResponse{}, err
+	}
+
+	response.Ingredients = scaling.ScaleIngredients(response.Ingredients, factor, targetSystem)
+	response.Servings = servings
+	//por porción no cambia; el total acompaña a las cantidades
+	if response.Nutrition != nil {
+		scaled := *response.Nutrition
+		scaled.Total = nutrition.Round(nutrition.Scale(scaled.Total, factor))
+		response.Nutrition = &scaled
+	}
+	return response, nil
+}
+
+// deriveRecipeFields calcula lo que se guarda derivado de los ingredientes y
+// los pasos antes de persistir la receta. Se llama en todo alta o edición.
+func deriveRecipeFields(recipe *models.Recipe) {
+	for i := range recipe.Ingredients {
+		recipe.Ingredients[i].Terms = shopping.Terms(recipe.Ingredients[i].Name)
+	}
+	recipe.Allergens, recipe.Diets, recipe.Unclassified = dietary.Classify(recipe.Ingredients)
+	//con todos los pasos cronometrados el tiempo total sale del cronograma y no de lo que se cargó a mano
+	if timeline.Timed(recipe.Step) {
+		recipe.TotalTime = timeline.Schedule(recipe.Step).Total
+	}
+}
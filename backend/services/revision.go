@@ -0,0 +1,194 @@
+package services
+
+import (
+	"burned/backend/dtos"
+	"burned/backend/models"
+	"burned/backend/pagination"
+	"burned/backend/repositories"
+	"burned/backend/revisions"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type RevisionServiceInterface interface {
+	GetRevisions(recipeId string, requesterId string, requesterRole string, page pagination.Request) (pagination.Page[dtos.RevisionSummaryResponse], error)
+	GetRevision(recipeId string, number int, requesterId string, requesterRole string) (dtos.RevisionResponse, error)
+	DiffRevisions(recipeId string, from int, to int, requesterId string, requesterRole string) (dtos.RevisionDiffResponse, error)
+	RestoreRevision(recipeId string, number int, requesterId string, requesterRole string) (dtos.RecipeResponse, error)
+}
+
+type RevisionService struct {
+	revisionRepo     repositories.RecipeRevisionRepositoryInterface
+	recipeRepo       repositories.RecipeRepositoryInterface
+	userRepo         repositories.UserRepositoryInterface
+	nutritionService NutritionServiceInterface
+}
+
+func NewRevisionService(revisionRepo repositories.RecipeRevisionRepositoryInterface, recipeRepo repositories.RecipeRepositoryInterface, userRepo repositories.UserRepositoryInterface, nutritionService NutritionServiceInterface) *RevisionService {
+	return &RevisionService{revisionRepo: revisionRepo, recipeRepo: recipeRepo, userRepo: userRepo, nutritionService: nutritionService}
+}
+
+// recordRevision guarda la receta editada como nueva revisión. Las recetas
+// anteriores al historial no tienen revisiones: antes de la primera edición
+// se guarda su estado previo como revisión 1 para no perderlo.
+func recordRevision(revisionRepo repositories.RecipeRevisionRepositoryInterface, previous *models.Recipe, updated models.Recipe, editorId primitive.ObjectID, restoredFrom int) error {
+	if previous != nil {
+		count, err := revisionRepo.CountRevisionsByRecipe(updated.ID)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			createdAt := previous.UpdatedAt
+			if createdAt.IsZero() {
+				createdAt = previous.CreatedAt
+			}
+			baseline := models.RecipeRevision{RecipeID: previous.ID, EditorID: previous.UserID, CreatedAt: createdAt, Snapshot: snapshot(*previous)}
+			if _, err := revisionRepo.CreateRevision(baseline); err != nil {
+				return err
+			}
+		}
+	}
+	revision := models.RecipeRevision{
+		RecipeID:     updated.ID,
+		EditorID:     editorId,
+		CreatedAt:    time.Now(),
+		RestoredFrom: restoredFrom,
+		Snapshot:     snapshot(updated),
+	}
+	_, err := revisionRepo.CreateRevision(revision)
+	return err
+}
+
+// snapshot deja solo lo que escribe el autor; puntaje y guardados cambian
+// sin ediciones y no forman parte del historial.
+func snapshot(recipe models.Recipe) models.Recipe {
+	recipe.AverageRating = 0
+	recipe.SavedCount = 0
+	recipe.Score = 0
+	return recipe
+}
+
+// authorize carga la receta y verifica que quien consulta sea el dueño o admin.
+func (service *RevisionService) authorize(recipeId string, requesterId string, requesterRole string) (models.Recipe, error) {
+	oid, err := primitive.ObjectIDFromHex(recipeId)
+	if err != nil {
+		return models.Recipe{}, errors.New("invalid id")
+	}
+	recipe, err := service.recipeRepo.GetRecipeById(oid)
+	if err != nil {
+		return models.Recipe{}, errors.New("recipe not found")
+	}
+	if recipe.UserID.Hex() != requesterId && requesterRole != "admin" {
+		return models.Recipe{}, errors.New("unauthorized: you cannot view this recipe history")
+	}
+	return recipe, nil
+}
+
+func (service *RevisionService) GetRevisions(recipeId string, requesterId string, requesterRole string, page pagination.Request) (pagination.Page[dtos.RevisionSummaryResponse], error) {
+	recipe, err := service.authorize(recipeId, requesterId, requesterRole)
+	if err != nil {
+		return pagination.Page[dtos.RevisionSummaryResponse]{}, err
+	}
+	result, err := service.revisionRepo.GetRevisionsByRecipePaged(recipe.ID, page)
+	if err != nil {
+		if pagination.IsRequestError(err) {
+			return pagination.Page[dtos.RevisionSummaryResponse]{}, err
+		}
+		return pagination.Page[dtos.RevisionSummaryResponse]{}, errors.New("revisions not found")
+	}
+	names := map[primitive.ObjectID]string{}
+	return pagination.Map(result, func(revision models.RecipeRevision) dtos.RevisionSummaryResponse {
+		summary := dtos.RevisionModelToSummary(revision)
+		summary.EditorName = service.editorName(names, revision.EditorID)
+		return summary
+	}), nil
+}
+
+func (service *RevisionService) GetRevision(recipeId string, number int, requesterId string, requesterRole string) (dtos.RevisionResponse, error) {
+	recipe, err := service.authorize(recipeId, requesterId, requesterRole)
+	if err != nil {
+		return dtos.RevisionResponse{}, err
+	}
+	revision, err := service.revisionRepo.GetRevision(recipe.ID, number)
+	if err != nil {
+		return dtos.RevisionResponse{}, errors.New("revision not found")
+	}
+	response := dtos.RevisionModelToResponse(revision)
+	names := map[primitive.ObjectID]string{}
+	response.EditorName = service.editorName(names, revision.EditorID)
+	response.Recipe.UserName = service.editorName(names, recipe.UserID)
+	return response, nil
+}
+
+func (service *RevisionService) DiffRevisions(recipeId string, from int, to int, requesterId string, requesterRole string) (dtos.RevisionDiffResponse, error) {
+	recipe, err := service.authorize(recipeId, requesterId, requesterRole)
+	if err != nil {
+		return dtos.RevisionDiffResponse{}, err
+	}
+	fromRevision, err := service.revisionRepo.GetRevision(recipe.ID, from)
+	if err != nil {
+		return dtos.RevisionDiffResponse{}, errors.New("revision not found")
+	}
+	toRevision, err := service.revisionRepo.GetRevision(recipe.ID, to)
+	if err != nil {
+		return dtos.RevisionDiffResponse{}, errors.New("revision not found")
+	}
+	return dtos.RevisionDiffResponse{
+		From:    from,
+		To:      to,
+		Changes: revisions.Diff(fromRevision.Snapshot, toRevision.Snapshot),
+	}, nil
+}
+
+// RestoreRevision vuelve la receta al contenido de una revisión anterior. El
+// historial no se reescribe: la restauración queda como una revisión nueva.
+func (service *RevisionService) RestoreRevision(recipeId string, number int, requesterId string, requesterRole string) (dtos.RecipeResponse, error) {
+	current, err := service.authorize(recipeId, requesterId, requesterRole)
+	if err != nil {
+		return dtos.RecipeResponse{}, err
+	}
+	editorId, err := primitive.ObjectIDFromHex(requesterId)
+	if err != nil {
+		return dtos.RecipeResponse{}, errors.New("invalid id")
+	}
+	revision, err := service.revisionRepo.GetRevision(current.ID, number)
+	if err != nil {
+		return dtos.RecipeResponse{}, errors.New("revision not found")
+	}
+
+	restored := revision.Snapshot
+	restored.ID = current.ID
+	restored.UserID = current.UserID
+	restored.CreatedAt = current.CreatedAt
+	restored.UpdatedAt = time.Now()
+	restored.AverageRating = current.AverageRating
+	restored.SavedCount = current.SavedCount
+	deriveRecipeFields(&restored)
+	misses := service.nutritionService.Annotate(&restored)
+	if _, err := service.recipeRepo.UpdateRecipe(restored); err != nil {
+		return dtos.RecipeResponse{}, err
+	}
+	service.nutritionService.RecordUnmatched(restored.ID, misses)
+	if err := recordRevision(service.revisionRepo, &current, restored, editorId, number); err != nil {
+		return dtos.RecipeResponse{}, err
+	}
+
+	response := dtos.RecipeModelToResponse(restored)
+	response.UserName = service.editorName(map[primitive.ObjectID]string{}, current.UserID)
+	return response, nil
+}
+
+// editorName resuelve nombres de usuario cacheando los ya consultados.
+func (service *RevisionService) editorName(names map[primitive.ObjectID]string, userId primitive.ObjectID) string {
+	if name, ok := names[userId]; ok {
+		return name
+	}
+	name := models.DeletedUserName
+	if user, err := service.userRepo.GetUserById(userId); err == nil {
+		name = user.Name
+	}
+	names[userId] = name
+	return name
+}
@@ -3,7 +3,9 @@ package services
 import (
 	"burned/backend/dtos"
 	"burned/backend/models"
+	"burned/backend/pagination"
 	"burned/backend/repositories"
+	"burned/backend/units"
 	"errors"
 	"time"
 
@@ -13,18 +15,22 @@ import (
 type SavedRecipeServiceInterface interface {
 	SavedRecipe(saved dtos.SavedRecipeRequest, userId string) (dtos.SavedRecipeResponse, error)
 	UnsavedRecipe(userId string, recipeId string) error
-	GetRecipesSavedByUser(idUser string) ([]dtos.RecipeResponse, error)
+	GetRecipesSavedByUser(idUser string, page pagination.Request) (pagination.Page[dtos.RecipeResponse], error)
 	GetSavedCountByRecipe(idRecipe string) (int64, error)
 	GetTop10MostSaved() ([]models.TopSavedRecipe, error)
+	UpdateNotes(userId string, recipeId string, notes dtos.RecipeNotesRequest) (models.RecipeNotes, error)
+	GetNotes(userId string, recipeId string) (models.RecipeNotes, error)
+	DeleteNotes(userId string, recipeId string) error
 }
 
 type SavedRecipeService struct {
-	repo       repositories.SavedRecipeRepositoryInterface
-	recipeRepo repositories.RecipeRepositoryInterface
+	repo           repositories.SavedRecipeRepositoryInterface
+	recipeRepo     repositories.RecipeRepositoryInterface
+	collectionRepo repositories.RecipeCollectionRepositoryInterface
 }
 
-func NewSavedRecipeService(r repositories.SavedRecipeRepositoryInterface, recipeRepo repositories.RecipeRepositoryInterface) *SavedRecipeService {
-	return &SavedRecipeService{repo: r, recipeRepo: recipeRepo}
+func NewSavedRecipeService(r repositories.SavedRecipeRepositoryInterface, recipeRepo repositories.RecipeRepositoryInterface, collectionRepo repositories.RecipeCollectionRepositoryInterface) *SavedRecipeService {
+	return &SavedRecipeService{repo: r, recipeRepo: recipeRepo, collectionRepo: collectionRepo}
 }
 
 func (service *SavedRecipeService) SavedRecipe(saved dtos.SavedRecipeRequest, userId string) (dtos.SavedRecipeResponse, error) {
@@ -52,6 +58,10 @@ func (service *SavedRecipeService) SavedRecipe(saved dtos.SavedRecipeRequest, us
 	if err != nil {
 		return dtos.SavedRecipeResponse{}, err
 	}
+	//la colección "Saved" refleja las recetas guardadas
+	if err := service.collectionRepo.AddToDefault(userOid, recipeOid); err != nil {
+		return dtos.SavedRecipeResponse{}, err
+	}
 	var response dtos.SavedRecipeResponse
 	//obtenemos el id del objeto en la bdd para devolverselo al usuario
 	insertedOid, ok := result.InsertedID.(primitive.ObjectID)
@@ -72,33 +82,27 @@ func (service *SavedRecipeService) UnsavedRecipe(userId string, recipeId string)
 		return errors.New("invalid recipe ID")
 	}
 	result, err := service.repo.UnsavedRecipe(userOid, recipeOid)
+	if err != nil {
+		return err
+	}
 	//verificamos la cantidad de elementos eliminados, si es 0 ha habido un error
 	if result.DeletedCount == 0 {
 		return errors.New("saved recipe not found")
 	}
-	return nil
+	return service.collectionRepo.RemoveFromDefault(userOid, recipeOid)
 }
 
-func (service *SavedRecipeService) GetRecipesSavedByUser(idUser string) ([]dtos.RecipeResponse, error) {
+func (service *SavedRecipeService) GetRecipesSavedByUser(idUser string, page pagination.Request) (pagination.Page[dtos.RecipeResponse], error) {
 	oid, err := primitive.ObjectIDFromHex(idUser)
 	if err != nil {
-		return []dtos.RecipeResponse{}, err
+		return pagination.Page[dtos.RecipeResponse]{}, err
 	}
-	//convertimos el string en oid y obtenemos sus recetas guardadas
-	models, err := service.repo.GetRecipesSavedByUser(oid)
+	//convertimos el string en oid y obtenemos la página de recetas guardadas
+	result, err := service.repo.GetRecipesSavedByUserPaged(oid, page)
 	if err != nil {
-		return []dtos.RecipeResponse{}, err
-	}
-	var savedRecipesResponses []dtos.RecipeResponse
-	//convertimos los models obtenidos a objetos del tipo response para devolverselo al cliente
-	for _, recipe := range models {
-		savedRecipesResponse, err := service.recipeRepo.GetRecipeById(recipe.RecipeID)
-		if err != nil {
-			continue
-		}
-		savedRecipesResponses = append(savedRecipesResponses, dtos.RecipeModelToResponse(savedRecipesResponse))
+		return pagination.Page[dtos.RecipeResponse]{}, err
 	}
-	return savedRecipesResponses, nil
+	return pagination.Map(result, dtos.RecipeModelToResponse), nil
 }
 
 func (service *SavedRecipeService) GetSavedCountByRecipe(idRecipe string) (int64, error) {
@@ -121,3 +125,112 @@ func (service *SavedRecipeService) GetTop10MostSaved() ([]models.TopSavedRecipe,
 	}
 	return result, nil
 }
+
+// UpdateNotes reemplaza las notas privadas del usuario sobre una receta guardada.
+func (service *SavedRecipeService) UpdateNotes(userId string, recipeId string, notes dtos.RecipeNotesRequest) (models.RecipeNotes, error) {
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return models.RecipeNotes{}, errors.New("invalid user ID")
+	}
+	recipeOid, err := primitive.ObjectIDFromHex(recipeId)
+	if err != nil {
+		return models.RecipeNotes{}, errors.New("invalid recipe ID")
+	}
+	recipe, err := service.recipeRepo.GetRecipeById(recipeOid)
+	if err != nil {
+		return models.RecipeNotes{}, errors.New("recipe not found")
+	}
+	if err := notes.Validate(len(recipe.Step)); err != nil {
+		return models.RecipeNotes{}, err
+	}
+
+	model := models.RecipeNotes{
+		Note:          notes.Note,
+		Steps:         notes.Steps,
+		Substitutions: notes.Substitutions,
+		UpdatedAt:     time.Now(),
+	}
+	if model.Steps == nil {
+		model.Steps = []models.StepNote{}
+	}
+	if model.Substitutions == nil {
+		model.Substitutions = []models.IngredientSubstitution{}
+	}
+	matched, err := service.repo.SetNotes(userOid, recipeOid, &model)
+	if err != nil {
+		return models.RecipeNotes{}, err
+	}
+	if matched == 0 {
+		return models.RecipeNotes{}, errors.New("saved recipe not found")
+	}
+	return *overlayNotes(&model, recipe), nil
+}
+
+func (service *SavedRecipeService) GetNotes(userId string, recipeId string) (models.RecipeNotes, error) {
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return models.RecipeNotes{}, errors.New("invalid user ID")
+	}
+	recipeOid, err := primitive.ObjectIDFromHex(recipeId)
+	if err != nil {
+		return models.RecipeNotes{}, errors.New("invalid recipe ID")
+	}
+	notes, err := service.repo.GetNotes(userOid, recipeOid)
+	if err != nil {
+		return models.RecipeNotes{}, err
+	}
+	if notes == nil {
+		return models.RecipeNotes{}, errors.New("notes not found")
+	}
+	recipe, err := service.recipeRepo.GetRecipeById(recipeOid)
+	if err != nil {
+		return models.RecipeNotes{}, errors.New("recipe not found")
+	}
+	return *overlayNotes(notes, recipe), nil
+}
+
+func (service *SavedRecipeService) DeleteNotes(userId string, recipeId string) error {
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return errors.New("invalid user ID")
+	}
+	recipeOid, err := primitive.ObjectIDFromHex(recipeId)
+	if err != nil {
+		return errors.New("invalid recipe ID")
+	}
+	matched, err := service.repo.SetNotes(userOid, recipeOid, nil)
+	if err != nil {
+		return err
+	}
+	if matched == 0 {
+		return errors.New("saved recipe not found")
+	}
+	return nil
+}
+
+// overlayNotes ubica cada sustitución en los ingredientes actuales de la
+// receta y descarta las notas de pasos que el autor ya quitó.
+func overlayNotes(notes *models.RecipeNotes, recipe models.Recipe) *models.RecipeNotes {
+	if notes == nil {
+		return nil
+	}
+	overlaid := *notes
+	overlaid.Steps = []models.StepNote{}
+	for _, step := range notes.Steps {
+		if step.Step >= 1 && step.Step <= len(recipe.Step) {
+			overlaid.Steps = append(overlaid.Steps, step)
+		}
+	}
+	overlaid.Substitutions = make([]models.IngredientSubstitution, 0, len(notes.Substitutions))
+	for _, substitution := range notes.Substitutions {
+		substitution.IngredientIndex = -1
+		for i, ingredient := range recipe.Ingredients {
+			if units.Normalize(ingredient.Name) == units.Normalize(substitution.Ingredient) {
+				substitution.IngredientIndex = i
+				break
+			}
+		}
+		overlaid.Substitutions = append(overlaid.Substitutions, substitution)
+	}
+	return &overlaid
+}
@@ -0,0 +1,358 @@
+package services
+
+import (
+	"burned/backend/dtos"
+	"burned/backend/export"
+	"burned/backend/ical"
+	"burned/backend/models"
+	"burned/backend/repositories"
+	"burned/backend/scaling"
+	"burned/backend/timeline"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const calendarProdID = "-//Burned//Recetas//ES"
+
+// feedHistory es cuánto hacia atrás muestra el feed las sesiones ya pasadas
+const feedHistory = 30 * 24 * time.Hour
+
+type ScheduleServiceInterface interface {
+	RecipeSchedule(recipeId string, serveAt string, servings int, requesterId string) (dtos.ExportFile, error)
+	RecipeTimeline(recipeId string, serveAt string, servings int, requesterId string) (dtos.RecipeTimelineResponse, error)
+	CreateSession(session dtos.CookSessionRequest, userId string) (dtos.CookSessionResponse, error)
+	GetSessions(userId string) ([]dtos.CookSessionResponse, error)
+	DeleteSession(id string, userId string) error
+	CreateFeed(userId string) (dtos.CalendarFeedResponse, error)
+	RevokeFeed(userId string) error
+	Feed(token string) (dtos.ExportFile, error)
+}
+
+type ScheduleService struct {
+	sessionRepo repositories.CookSessionRepositoryInterface
+	recipeRepo  repositories.RecipeRepositoryInterface
+}
+
+func NewScheduleService(sessionRepo repositories.CookSessionRepositoryInterface, recipeRepo repositories.RecipeRepositoryInterface) *ScheduleService {
+	return &ScheduleService{sessionRepo: sessionRepo, recipeRepo: recipeRepo}
+}
+
+// RecipeSchedule arma un calendario con un evento por paso para que la
+// receta esté lista a serveAt (RFC 3339).
+func (service *ScheduleService) RecipeSchedule(recipeId string, serveAt string, servings int, requesterId string) (dtos.ExportFile, error) {
+	serveTime, err := time.Parse(time.RFC3339, serveAt)
+	if err != nil {
+		return dtos.ExportFile{}, errors.New("invalid serveAt, expected RFC 3339 time")
+	}
+	recipe, err := service.visibleRecipe(recipeId, requesterId)
+	if err != nil {
+		return dtos.ExportFile{}, err
+	}
+	uid := fmt.Sprintf("%s-%d", recipe.ID.Hex(), serveTime.Unix())
+	calendar := ical.Calendar{
+		ProdID: calendarProdID,
+		Name:   recipe.Title,
+		Events: recipeEvents(recipe, serveTime, servings, uid),
+	}
+	return dtos.ExportFile{
+		Name:        export.Slug(recipe.Title) + "-" + recipe.ID.Hex() + ".ics",
+		ContentType: ical.ContentType,
+		Content:     calendar.Bytes(),
+	}, nil
+}
+
+// RecipeTimeline devuelve el cronograma de la receta; serveAt (RFC 3339) es
+// opcional y agrega las horas de inicio y fin de cada paso.
+func (service *ScheduleService) RecipeTimeline(recipeId string, serveAt string, servings int, requesterId string) (dtos.RecipeTimelineResponse, error) {
+	var serveTime *time.Time
+	if serveAt != "" {
+		parsed, err := time.Parse(time.RFC3339, serveAt)
+		if err != nil {
+			return dtos.RecipeTimelineResponse{}, errors.New("invalid serveAt, expected RFC 3339 time")
+		}
+		serveTime = &parsed
+	}
+	recipe, err := service.visibleRecipe(recipeId, requesterId)
+	if err != nil {
+		return dtos.RecipeTimelineResponse{}, err
+	}
+	ingredients := scaledIngredients(recipe, servings)
+	plan := timeline.Schedule(recipe.Step)
+	dependencies := timeline.Dependencies(recipe.Step)
+
+	response := dtos.RecipeTimelineResponse{
+		RecipeID:  recipe.ID.Hex(),
+		Title:     recipe.Title,
+		TotalTime: plan.Total,
+		Critical:  plan.Critical,
+		Steps:     make([]dtos.TimelineStepResponse, len(plan.Tasks)),
+	}
+	var begin time.Time
+	if serveTime != nil {
+		begin = serveTime.Add(-time.Duration(plan.Total) * time.Minute)
+		response.ServeAt = serveTime
+		response.StartAt = &begin
+	}
+	for i, task := range plan.Tasks {
+		step := dtos.TimelineStepResponse{
+			Index:       task.Step,
+			Title:       recipe.Step[task.Step].Title,
+			Start:       task.Start,
+			End:         task.End,
+			Passive:     task.Passive,
+			Critical:    task.Critical,
+			DependsOn:   append([]int{}, dependencies[task.Step]...),
+			Ingredients: ingredientLines(stepIngredients(recipe.Step[task.Step], len(ingredients)), ingredients),
+		}
+		if serveTime != nil {
+			startAt := begin.Add(time.Duration(task.Start) * time.Minute)
+			endAt := begin.Add(time.Duration(task.End) * time.Minute)
+			step.StartAt, step.EndAt = &startAt, &endAt
+		}
+		response.Steps[i] = step
+	}
+	return response, nil
+}
+
+func (service *ScheduleService) CreateSession(session dtos.CookSessionRequest, userId string) (dtos.CookSessionResponse, error) {
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return dtos.CookSessionResponse{}, errors.New("invalid id")
+	}
+	recipe, err := service.visibleRecipe(session.RecipeID, userId)
+	if err != nil {
+		return dtos.CookSessionResponse{}, err
+	}
+	created, err := service.sessionRepo.CreateSession(models.CookSession{
+		UserID:    userOid,
+		RecipeID:  recipe.ID,
+		ServeAt:   session.ServeAt,
+		Servings:  session.Servings,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return dtos.CookSessionResponse{}, err
+	}
+	return sessionResponse(created, recipe), nil
+}
+
+// GetSessions devuelve las sesiones próximas del usuario.
+func (service *ScheduleService) GetSessions(userId string) ([]dtos.CookSessionResponse, error) {
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, errors.New("invalid id")
+	}
+	sessions, recipes, err := service.sessionsWithRecipes(userOid, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]dtos.CookSessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		if recipe, ok := recipes[session.RecipeID]; ok {
+			responses = append(responses, sessionResponse(session, recipe))
+		}
+	}
+	return responses, nil
+}
+
+func (service *ScheduleService) DeleteSession(id string, userId string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.New("invalid id")
+	}
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return errors.New("invalid id")
+	}
+	deleted, err := service.sessionRepo.DeleteSession(oid, userOid)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return errors.New("cook session not found")
+	}
+	return nil
+}
+
+// CreateFeed genera (o regenera) el token del feed; el anterior deja de valer.
+func (service *ScheduleService) CreateFeed(userId string) (dtos.CalendarFeedResponse, error) {
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return dtos.CalendarFeedResponse{}, errors.New("invalid id")
+	}
+	token, err := repositories.NewShareToken()
+	if err != nil {
+		return dtos.CalendarFeedResponse{}, err
+	}
+	if err := service.sessionRepo.SetFeed(models.CalendarFeed{UserID: userOid, TokenHash: hashFeedToken(token), CreatedAt: time.Now()}); err != nil {
+		return dtos.CalendarFeedResponse{}, err
+	}
+	return dtos.CalendarFeedResponse{Token: token, Path: "/calendar/" + token + ".ics"}, nil
+}
+
+func (service *ScheduleService) RevokeFeed(userId string) error {
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return errors.New("invalid id")
+	}
+	deleted, err := service.sessionRepo.DeleteFeed(userOid)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return errors.New("calendar feed not found")
+	}
+	return nil
+}
+
+// Feed arma el calendario del usuario dueño del token con sus sesiones
+// recientes y próximas. Las recetas que ya no puede ver se omiten.
+func (service *ScheduleService) Feed(token string) (dtos.ExportFile, error) {
+	feed, err := service.sessionRepo.GetFeedByTokenHash(hashFeedToken(token))
+	if err != nil {
+		return dtos.ExportFile{}, errors.New("calendar feed not found")
+	}
+	sessions, recipes, err := service.sessionsWithRecipes(feed.UserID, time.Now().Add(-feedHistory))
+	if err != nil {
+		return dtos.ExportFile{}, err
+	}
+	calendar := ical.Calendar{ProdID: calendarProdID, Name: "Burned"}
+	for _, session := range sessions {
+		recipe, ok := recipes[session.RecipeID]
+		if !ok {
+			continue
+		}
+		calendar.Events = append(calendar.Events, recipeEvents(recipe, session.ServeAt, session.Servings, session.ID.Hex())...)
+	}
+	return dtos.ExportFile{Name: "burned.ics", ContentType: ical.ContentType, Content: calendar.Bytes()}, nil
+}
+
+func (service *ScheduleService) visibleRecipe(recipeId string, requesterId string) (models.Recipe, error) {
+	oid, err := primitive.ObjectIDFromHex(recipeId)
+	if err != nil {
+		return models.Recipe{}, errors.New("invalid id")
+	}
+	recipe, err := service.recipeRepo.GetRecipeById(oid)
+	if err != nil {
+		return models.Recipe{}, errors.New("recipe not found")
+	}
+	requester, _ := primitive.ObjectIDFromHex(requesterId)
+	if !visibleTo(recipe, requester) {
+		return models.Recipe{}, errors.New("recipe not found")
+	}
+	return recipe, nil
+}
+
+func (service *ScheduleService) sessionsWithRecipes(userId primitive.ObjectID, since time.Time) ([]models.CookSession, map[primitive.ObjectID]models.Recipe, error) {
+	sessions, err := service.sessionRepo.GetSessionsByUser(userId, since)
+	if err != nil {
+		return nil, nil, err
+	}
+	ids := make([]primitive.ObjectID, 0, len(sessions))
+	for _, session := range sessions {
+		ids = append(ids, session.RecipeID)
+	}
+	found, err := service.recipeRepo.GetRecipesByIds(ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	recipes := make(map[primitive.ObjectID]models.Recipe, len(found))
+	for _, recipe := range found {
+		if visibleTo(recipe, userId) {
+			recipes[recipe.ID] = recipe
+		}
+	}
+	return sessions, recipes, nil
+}
+
+// recipeEvents devuelve un evento por paso, terminando la receta a serveAt.
+// Cada evento lista los ingredientes que usa el paso (todos si el paso no
+// los indica), escalados si se piden otras porciones.
+func recipeEvents(recipe models.Recipe, serveAt time.Time, servings int, uid string) []ical.Event {
+	ingredients := scaledIngredients(recipe, servings)
+	slots := timeline.Backwards(recipe.Step, serveAt)
+	events := make([]ical.Event, 0, len(slots))
+	for _, slot := range slots {
+		step := recipe.Step[slot.Step]
+		lines := ingredientLines(stepIngredients(step, len(ingredients)), ingredients)
+		for i := range lines {
+			lines[i] = "- " + lines[i]
+		}
+		description := step.Descripcion
+		if len(lines) > 0 {
+			description += "\n\nIngredientes:\n" + strings.Join(lines, "\n")
+		}
+		events = append(events, ical.Event{
+			UID:         fmt.Sprintf("%s-%d@burned", uid, slot.Step+1),
+			Start:       slot.Start,
+			End:         slot.End,
+			Summary:     fmt.Sprintf("%s: %d. %s", recipe.Title, slot.Step+1, step.Title),
+			Description: description,
+		})
+	}
+	return events
+}
+
+// stepIngredients devuelve los ingredientes que usa el paso; si no indica
+// ninguno, todos los de la receta.
+func stepIngredients(step models.Step, count int) []int {
+	if len(step.Ingredients) > 0 {
+		return step.Ingredients
+	}
+	return allIndexes(count)
+}
+
+func allIndexes(count int) []int {
+	indexes := make([]int, count)
+	for i := range indexes {
+		indexes[i] = i
+	}
+	return indexes
+}
+
+func scaledIngredients(recipe models.Recipe, servings int) []models.Ingredient {
+	if servings > 0 && recipe.Servings > 0 && servings != recipe.Servings {
+		if factor, err := scaling.Factor(recipe.Servings, servings); err == nil {
+			return scaling.ScaleIngredients(recipe.Ingredients, factor, "")
+		}
+	}
+	return recipe.Ingredients
+}
+
+// ingredientLines devuelve las líneas de los ingredientes en esas
+// posiciones; las referencias que ya no existen se ignoran.
+func ingredientLines(indexes []int, ingredients []models.Ingredient) []string {
+	lines := []string{}
+	for _, index := range indexes {
+		if index >= 0 && index < len(ingredients) {
+			lines = append(lines, export.IngredientLine(ingredients[index]))
+		}
+	}
+	return lines
+}
+
+func sessionResponse(session models.CookSession, recipe models.Recipe) dtos.CookSessionResponse {
+	startAt := session.ServeAt.Add(-time.Duration(timeline.Schedule(recipe.Step).Total) * time.Minute)
+	return dtos.CookSessionResponse{
+		ID:          session.ID.Hex(),
+		RecipeID:    session.RecipeID.Hex(),
+		RecipeTitle: recipe.Title,
+		ServeAt:     session.ServeAt,
+		StartAt:     startAt,
+		Servings:    session.Servings,
+		CreatedAt:   session.CreatedAt,
+	}
+}
+
+// hashFeedToken: los tokens se guardan hasheados como las contraseñas, pero
+// con SHA-256 porque se buscan por igualdad y ya son aleatorios.
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
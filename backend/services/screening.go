@@ -0,0 +1,85 @@
+package services
+
+import (
+	"burned/backend/models"
+	"burned/backend/repositories"
+	"burned/backend/screening"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ScreeningServiceInterface interface {
+	Screen(content screening.Content) (screening.Result, error)
+	Record(content screening.Content, recipeId primitive.ObjectID, result screening.Result)
+}
+
+// ScreeningService pasa comentarios y recetas por el pipeline de moderación
+// automática antes de guardarlos, y manda a la cola de moderación lo que
+// quedó marcado u oculto.
+type ScreeningService struct {
+	pipeline       *screening.Pipeline
+	moderationRepo repositories.ModerationRepositoryInterface
+}
+
+func NewScreeningService(pipeline *screening.Pipeline, moderationRepo repositories.ModerationRepositoryInterface) *ScreeningService {
+	return &ScreeningService{pipeline: pipeline, moderationRepo: moderationRepo}
+}
+
+// Screen devuelve error solo si el contenido se rechaza. Si una regla falla
+// se registra y se sigue con las demás, para no bloquear publicaciones.
+func (service *ScreeningService) Screen(content screening.Content) (screening.Result, error) {
+	result, err := service.pipeline.Check(content)
+	if err != nil {
+		log.Println("⚠️ Aviso: falló la moderación automática:", err)
+	}
+	if result.Verdict == screening.Reject {
+		return result, errors.New("invalid content, " + result.Reason())
+	}
+	return result, nil
+}
+
+// Record abre (o completa) el caso de moderación de un contenido ya guardado
+// que quedó marcado u oculto. content.ID tiene que ser el id guardado.
+func (service *ScreeningService) Record(content screening.Content, recipeId primitive.ObjectID, result screening.Result) {
+	if result.Verdict != screening.Flag && result.Verdict != screening.Hold {
+		return
+	}
+	findings := make([]string, 0, len(result.Findings))
+	for _, finding := range result.Findings {
+		findings = append(findings, finding.String())
+	}
+	moderationCase := models.ModerationCase{
+		TargetType:   content.Kind,
+		TargetID:     content.ID,
+		RecipeID:     recipeId,
+		AuthorID:     content.AuthorID,
+		Findings:     findings,
+		AutoHidden:   result.Verdict == screening.Hold,
+		LastReportAt: time.Now(),
+	}
+	if err := service.moderationRepo.AddCaseFindings(moderationCase); err != nil {
+		log.Println("⚠️ Aviso: no se pudo abrir el caso de moderación:", err)
+	}
+}
+
+// recipeContent arma el texto de una receta para revisarlo: título y
+// descripción como cuerpo, y etiquetas, pasos e ingredientes como extra.
+func recipeContent(recipe models.Recipe) screening.Content {
+	content := screening.Content{
+		Kind:     models.TargetRecipe,
+		ID:       recipe.ID,
+		AuthorID: recipe.UserID,
+		Body:     recipe.Title + "\n" + recipe.Description,
+	}
+	content.Extra = append(content.Extra, recipe.Tags...)
+	for _, step := range recipe.Step {
+		content.Extra = append(content.Extra, step.Title, step.Descripcion)
+	}
+	for _, ingredient := range recipe.Ingredients {
+		content.Extra = append(content.Extra, ingredient.Name, ingredient.Note)
+	}
+	return content
+}
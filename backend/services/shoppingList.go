@@ -0,0 +1,370 @@
+package services
+
+import (
+	"burned/backend/dtos"
+	"burned/backend/models"
+	"burned/backend/repositories"
+	"burned/backend/scaling"
+	"burned/backend/shopping"
+	"burned/backend/units"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxShoppingItems acota el tamaño del documento de la lista
+const maxShoppingItems = 300
+
+type ShoppingListServiceInterface interface {
+	GetList(userId string) (dtos.ShoppingListResponse, error)
+	Generate(request dtos.ShoppingListGenerateRequest, userId string) (dtos.ShoppingListResponse, error)
+	AddItem(item dtos.ShoppingItemRequest, userId string) (dtos.ShoppingItemResponse, error)
+	UpdateItem(id string, update dtos.ShoppingItemUpdateRequest, userId string) (dtos.ShoppingItemResponse, error)
+	DeleteItem(id string, userId string) error
+	ClearChecked(userId string) (dtos.ShoppingListResponse, error)
+	ClearList(userId string) error
+	Export(userId string, format string) (dtos.ExportFile, error)
+}
+
+type ShoppingListService struct {
+	listRepo     repositories.ShoppingListRepositoryInterface
+	recipeRepo   repositories.RecipeRepositoryInterface
+	mealPlanRepo repositories.MealPlanRepositoryInterface
+}
+
+func NewShoppingListService(listRepo repositories.ShoppingListRepositoryInterface, recipeRepo repositories.RecipeRepositoryInterface, mealPlanRepo repositories.MealPlanRepositoryInterface) *ShoppingListService {
+	return &ShoppingListService{listRepo: listRepo, recipeRepo: recipeRepo, mealPlanRepo: mealPlanRepo}
+}
+
+func (service *ShoppingListService) GetList(userId string) (dtos.ShoppingListResponse, error) {
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return dtos.ShoppingListResponse{}, errors.New("invalid id")
+	}
+	list, err := service.listRepo.GetList(userOid)
+	if err != nil {
+		return dtos.ShoppingListResponse{}, err
+	}
+	return dtos.ShoppingListModelToResponse(list), nil
+}
+
+// shoppingSource es una receta a sumar a la lista con sus porciones.
+// FromPlan marca las que vienen del plan de comidas: si la receta ya no es
+// visible se saltea en vez de fallar.
+type shoppingSource struct {
+	RecipeID primitive.ObjectID
+	Servings int
+	FromPlan bool
+}
+
+// Generate suma a la lista los ingredientes de las recetas pedidas y del
+// plan de comidas. Los ingredientes con la misma Key se juntan en un ítem,
+// también con los ítems generados que ya estaban en la lista; un ítem que
+// recibe cantidades nuevas se destacha.
+func (service *ShoppingListService) Generate(request dtos.ShoppingListGenerateRequest, userId string) (dtos.ShoppingListResponse, error) {
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return dtos.ShoppingListResponse{}, errors.New("invalid id")
+	}
+	system, err := scaling.ParseSystem(request.System)
+	if err != nil {
+		return dtos.ShoppingListResponse{}, err
+	}
+	sources, err := service.sources(request, userOid)
+	if err != nil {
+		return dtos.ShoppingListResponse{}, err
+	}
+	recipes, err := service.shoppingRecipes(sources, userOid)
+	if err != nil {
+		return dtos.ShoppingListResponse{}, err
+	}
+
+	list, err := service.listRepo.GetList(userOid)
+	if err != nil {
+		return dtos.ShoppingListResponse{}, err
+	}
+	if request.Replace {
+		manual := []models.ShoppingItem{}
+		for _, item := range list.Items {
+			if item.Manual {
+				manual = append(manual, item)
+			}
+		}
+		list.Items = manual
+		list.Recipes = []models.ShoppingSource{}
+	}
+
+	// índice de los ítems generados por Key para sumarles lo nuevo
+	existing := map[string]int{}
+	for i, item := range list.Items {
+		if !item.Manual {
+			existing[item.Key] = i
+		}
+	}
+
+	now := time.Now()
+	for _, source := range sources {
+		recipe, ok := recipes[source.RecipeID]
+		if !ok {
+			continue
+		}
+		factor := 1.0
+		servings := recipe.Servings
+		if source.Servings > 0 && recipe.Servings > 0 && source.Servings != recipe.Servings {
+			factor, _ = scaling.Factor(recipe.Servings, source.Servings)
+			servings = source.Servings
+		}
+		for _, ingredient := range recipe.Ingredients {
+			key := shopping.Key(ingredient.Name)
+			if key == "" {
+				continue
+			}
+			amount := models.ShoppingAmount{Quantity: ingredient.Quantity * factor, Unit: ingredient.Unit}
+			index, ok := existing[key]
+			if !ok {
+				list.Items = append(list.Items, models.ShoppingItem{
+					ID:      primitive.NewObjectID(),
+					Name:    ingredient.Name,
+					Key:     key,
+					Aisle:   shopping.AisleOf(key),
+					AddedAt: now,
+				})
+				index = len(list.Items) - 1
+				existing[key] = index
+			}
+			item := &list.Items[index]
+			item.Amounts = append(item.Amounts, amount)
+			item.Checked = false
+			item.RecipeIDs = appendRecipeId(item.RecipeIDs, recipe.ID)
+		}
+		list.Recipes = append(list.Recipes, models.ShoppingSource{RecipeID: recipe.ID, Title: recipe.Title, Servings: servings, AddedAt: now})
+	}
+	if len(list.Items) > maxShoppingItems {
+		return dtos.ShoppingListResponse{}, errors.New("invalid request, shopping list is limited to 300 items")
+	}
+
+	// se vuelven a sumar todas las cantidades de cada ítem tocado
+	for _, index := range existing {
+		list.Items[index].Amounts = mergeShoppingAmounts(list.Items[index].Amounts, system)
+	}
+	list.UpdatedAt = now
+	if err := service.listRepo.SaveList(list); err != nil {
+		return dtos.ShoppingListResponse{}, err
+	}
+	return dtos.ShoppingListModelToResponse(list), nil
+}
+
+// sources junta las recetas pedidas explícitamente y las del plan de comidas.
+func (service *ShoppingListService) sources(request dtos.ShoppingListGenerateRequest, userId primitive.ObjectID) ([]shoppingSource, error) {
+	sources := []shoppingSource{}
+	for _, recipe := range request.Recipes {
+		recipeOid, err := primitive.ObjectIDFromHex(recipe.RecipeID)
+		if err != nil {
+			return nil, errors.New("invalid recipe ID")
+		}
+		sources = append(sources, shoppingSource{RecipeID: recipeOid, Servings: recipe.Servings})
+	}
+	if request.From != "" || request.To != "" {
+		from, to, err := planRange(request.From, request.To)
+		if err != nil {
+			return nil, err
+		}
+		entries, err := service.mealPlanRepo.GetEntriesByRange(userId, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			sources = append(sources, shoppingSource{RecipeID: entry.RecipeID, Servings: entry.Servings, FromPlan: true})
+		}
+	}
+	if len(sources) == 0 {
+		return nil, errors.New("invalid request, no recipes to add")
+	}
+	return sources, nil
+}
+
+// shoppingRecipes busca las recetas de una vez y verifica que el usuario pueda verlas.
+func (service *ShoppingListService) shoppingRecipes(sources []shoppingSource, userId primitive.ObjectID) (map[primitive.ObjectID]models.Recipe, error) {
+	ids := make([]primitive.ObjectID, 0, len(sources))
+	for _, source := range sources {
+		ids = append(ids, source.RecipeID)
+	}
+	found, err := service.recipeRepo.GetRecipesByIds(ids)
+	if err != nil {
+		return nil, err
+	}
+	recipes := make(map[primitive.ObjectID]models.Recipe, len(found))
+	for _, recipe := range found {
+		if visibleTo(recipe, userId) {
+			recipes[recipe.ID] = recipe
+		}
+	}
+	for _, source := range sources {
+		if _, ok := recipes[source.RecipeID]; !ok && !source.FromPlan {
+			return nil, errors.New("recipe not found")
+		}
+	}
+	return recipes, nil
+}
+
+func (service *ShoppingListService) AddItem(item dtos.ShoppingItemRequest, userId string) (dtos.ShoppingItemResponse, error) {
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return dtos.ShoppingItemResponse{}, errors.New("invalid id")
+	}
+	if err := item.Validate(); err != nil {
+		return dtos.ShoppingItemResponse{}, err
+	}
+	list, err := service.listRepo.GetList(userOid)
+	if err != nil {
+		return dtos.ShoppingItemResponse{}, err
+	}
+	if len(list.Items) >= maxShoppingItems {
+		return dtos.ShoppingItemResponse{}, errors.New("invalid request, shopping list is limited to 300 items")
+	}
+
+	key := shopping.Key(item.Name)
+	model := models.ShoppingItem{
+		ID:      primitive.NewObjectID(),
+		Name:    item.Name,
+		Key:     key,
+		Aisle:   item.Aisle,
+		Amounts: []models.ShoppingAmount{},
+		Manual:  true,
+		AddedAt: time.Now(),
+	}
+	if model.Aisle == "" {
+		model.Aisle = shopping.AisleOf(key)
+	}
+	if item.Unit != "" {
+		model.Amounts = mergeShoppingAmounts([]models.ShoppingAmount{{Quantity: item.Quantity, Unit: item.Unit}}, "")
+	}
+	if err := service.listRepo.AddItem(userOid, model); err != nil {
+		return dtos.ShoppingItemResponse{}, err
+	}
+	return dtos.ShoppingItemModelToResponse(model), nil
+}
+
+func (service *ShoppingListService) UpdateItem(id string, update dtos.ShoppingItemUpdateRequest, userId string) (dtos.ShoppingItemResponse, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return dtos.ShoppingItemResponse{}, errors.New("invalid id")
+	}
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return dtos.ShoppingItemResponse{}, errors.New("invalid id")
+	}
+	list, err := service.listRepo.GetList(userOid)
+	if err != nil {
+		return dtos.ShoppingItemResponse{}, err
+	}
+	for _, item := range list.Items {
+		if item.ID != oid {
+			continue
+		}
+		if update.Checked != nil {
+			item.Checked = *update.Checked
+		}
+		if update.Aisle != nil && shopping.ValidAisle(*update.Aisle) {
+			item.Aisle = *update.Aisle
+		}
+		matched, err := service.listRepo.UpdateItem(userOid, item)
+		if err != nil {
+			return dtos.ShoppingItemResponse{}, err
+		}
+		if matched == 0 {
+			break
+		}
+		return dtos.ShoppingItemModelToResponse(item), nil
+	}
+	return dtos.ShoppingItemResponse{}, errors.New("shopping item not found")
+}
+
+func (service *ShoppingListService) DeleteItem(id string, userId string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.New("invalid id")
+	}
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return errors.New("invalid id")
+	}
+	deleted, err := service.listRepo.DeleteItem(userOid, oid)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return errors.New("shopping item not found")
+	}
+	return nil
+}
+
+// ClearChecked saca de la lista lo que ya se compró.
+func (service *ShoppingListService) ClearChecked(userId string) (dtos.ShoppingListResponse, error) {
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return dtos.ShoppingListResponse{}, errors.New("invalid id")
+	}
+	if err := service.listRepo.DeleteCheckedItems(userOid); err != nil {
+		return dtos.ShoppingListResponse{}, err
+	}
+	list, err := service.listRepo.GetList(userOid)
+	if err != nil {
+		return dtos.ShoppingListResponse{}, err
+	}
+	return dtos.ShoppingListModelToResponse(list), nil
+}
+
+func (service *ShoppingListService) ClearList(userId string) error {
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return errors.New("invalid id")
+	}
+	return service.listRepo.DeleteList(userOid)
+}
+
+// Export devuelve la lista como texto plano (format=txt) o CSV (format=csv).
+func (service *ShoppingListService) Export(userId string, format string) (dtos.ExportFile, error) {
+	userOid, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return dtos.ExportFile{}, errors.New("invalid id")
+	}
+	list, err := service.listRepo.GetList(userOid)
+	if err != nil {
+		return dtos.ExportFile{}, err
+	}
+	switch format {
+	case "txt":
+		return dtos.ExportFile{Name: "shopping-list.txt", ContentType: "text/plain; charset=utf-8", Content: shopping.Text(list.Items)}, nil
+	case "csv":
+		content, err := shopping.CSV(list.Items)
+		if err != nil {
+			return dtos.ExportFile{}, err
+		}
+		return dtos.ExportFile{Name: "shopping-list.csv", ContentType: "text/csv; charset=utf-8", Content: content}, nil
+	}
+	return dtos.ExportFile{}, errors.New("unsupported export format")
+}
+
+func mergeShoppingAmounts(amounts []models.ShoppingAmount, system units.System) []models.ShoppingAmount {
+	raw := make([]scaling.Amount, 0, len(amounts))
+	for _, amount := range amounts {
+		raw = append(raw, scaling.Amount{Quantity: amount.Quantity, Unit: amount.Unit})
+	}
+	merged := shopping.Merge(raw, system)
+	result := make([]models.ShoppingAmount, 0, len(merged))
+	for _, amount := range merged {
+		result = append(result, models.ShoppingAmount{Quantity: amount.Quantity, Unit: amount.Unit})
+	}
+	return result
+}
+
+func appendRecipeId(ids []primitive.ObjectID, id primitive.ObjectID) []primitive.ObjectID {
+	for _, existing := range ids {
+		if existing == id {
+			return ids
+		}
+	}
+	return append(ids, id)
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"burned/backend/dietary"
+	"burned/backend/dtos"
+	"burned/backend/models"
+	"burned/backend/nutrition"
+	"burned/backend/pagination"
+	"burned/backend/repositories"
+	"burned/backend/scaling"
+	"burned/backend/shopping"
+	"burned/backend/substitutions"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SubstitutionServiceInterface interface {
+	GetRecipeSubstitutions(recipeId string, requesterId string, filter dtos.SubstitutionFilter, system string) (dtos.RecipeSubstitutionsResponse, error)
+	GetRules(page pagination.Request) (pagination.Page[models.SubstitutionRule], error)
+	CreateRule(rule dtos.SubstitutionRuleRequest, adminId string) (models.SubstitutionRule, error)
+	UpdateRule(id string, rule dtos.SubstitutionRuleRequest) (models.SubstitutionRule, error)
+	DeleteRule(id string) error
+}
+
+type SubstitutionService struct {
+	substitutionRepo repositories.SubstitutionRepositoryInterface
+	recipeRepo       repositories.RecipeRepositoryInterface
+	foods            *nutrition.Database
+}
+
+// foods se usa para la densidad de los ingredientes cuando la receta mide en
+// gramos y la regla en tazas (o al revés).
+func NewSubstitutionService(substitutionRepo repositories.SubstitutionRepositoryInterface, recipeRepo repositories.RecipeRepositoryInterface, foods *nutrition.Database) *SubstitutionService {
+	return &SubstitutionService{substitutionRepo: substitutionRepo, recipeRepo: recipeRepo, foods: foods}
+}
+
+// GetRecipeSubstitutions sugiere reemplazos para los ingredientes de la
+// receta, con las cantidades calculadas. Con restricciones solo se sugieren
+// reemplazos que las cumplan y se marcan los ingredientes que no las cumplen.
+func (service *SubstitutionService) GetRecipeSubstitutions(recipeId string, requesterId string, filter dtos.SubstitutionFilter, system string) (dtos.RecipeSubstitutionsResponse, error) {
+	if err := filter.Validate(); err != nil {
+		return dtos.RecipeSubstitutionsResponse{}, err
+	}
+	targetSystem, err := scaling.ParseSystem(system)
+	if err != nil {
+		return dtos.RecipeSubstitutionsResponse{}, err
+	}
+	oid, err := primitive.ObjectIDFromHex(recipeId)
+	if err != nil {
+		return dtos.RecipeSubstitutionsResponse{}, errors.New("invalid id")
+	}
+	recipe, err := service.recipeRepo.GetRecipeById(oid)
+	if err != nil {
+		return dtos.RecipeSubstitutionsResponse{}, errors.New("recipe not found")
+	}
+	requester, _ := primitive.ObjectIDFromHex(requesterId)
+	if !visibleTo(recipe, requester) {
+		return dtos.RecipeSubstitutionsResponse{}, errors.New("recipe not found")
+	}
+
+	keys := []string{}
+	for _, ingredient := range recipe.Ingredients {
+		keys = append(keys, shopping.Terms(ingredient.Name)...)
+	}
+	rules, err := service.substitutionRepo.GetRulesByKeys(keys)
+	if err != nil {
+		return dtos.RecipeSubstitutionsResponse{}, err
+	}
+
+	response := dtos.RecipeSubstitutionsResponse{RecipeID: recipe.ID.Hex(), Title: recipe.Title, Ingredients: []dtos.IngredientSubstitutionsResponse{}}
+	for _, ingredient := range recipe.Ingredients {
+		ingredient.Terms = nil
+		item := dtos.IngredientSubstitutionsResponse{
+			Ingredient:    ingredient,
+			Conflicts:     conflicts(ingredient.Name, filter),
+			Substitutions: []dtos.SubstitutionResponse{},
+		}
+		var density float64
+		if food, ok := service.foods.Match(ingredient.Name); ok {
+			density = food.Density
+		}
+		for _, rule := range substitutions.Match(rules, ingredient.Name) {
+			if !substitutions.Suits(rule, filter.ExcludeAllergens, filter.Diets) {
+				continue
+			}
+			suggestion := dtos.SubstitutionResponse{
+				RuleID:     rule.ID.Hex(),
+				Ratio:      substitutions.Ratio(rule),
+				Substitute: []models.Ingredient{},
+				Note:       rule.Note,
+				Allergens:  rule.Allergens,
+				Diets:      rule.Diets,
+			}
+			if parts, ok := substitutions.Apply(rule, ingredient, density, targetSystem); ok {
+				suggestion.Substitute = parts
+			}
+			item.Substitutions = append(item.Substitutions, suggestion)
+		}
+		if len(item.Substitutions) > 0 || item.Conflicts {
+			response.Ingredients = append(response.Ingredients, item)
+		}
+	}
+	return response, nil
+}
+
+// conflicts indica si el ingrediente tiene un alérgeno excluido o no entra
+// en alguna de las dietas pedidas.
+func conflicts(name string, filter dtos.SubstitutionFilter) bool {
+	categories := dietary.Categories(name)
+	for _, allergen := range filter.ExcludeAllergens {
+		if containsString(categories, allergen) {
+			return true
+		}
+	}
+	diets := dietary.DietsOf(name)
+	for _, diet := range filter.Diets {
+		if !containsString(diets, diet) {
+			return true
+		}
+	}
+	return false
+}
+
+func (service *SubstitutionService) GetRules(page pagination.Request) (pagination.Page[models.SubstitutionRule], error) {
+	return service.substitutionRepo.GetRulesPaged(page)
+}
+
+func (service *SubstitutionService) CreateRule(request dtos.SubstitutionRuleRequest, adminId string) (models.SubstitutionRule, error) {
+	if err := request.Validate(); err != nil {
+		return models.SubstitutionRule{}, err
+	}
+	adminOid, err := primitive.ObjectIDFromHex(adminId)
+	if err != nil {
+		return models.SubstitutionRule{}, errors.New("invalid id")
+	}
+	rule := dtos.SubstitutionRuleRequestToModel(request)
+	substitutions.Derive(&rule)
+	if rule.Key == "" {
+		return models.SubstitutionRule{}, errors.New("invalid rule, ingredient is required")
+	}
+	rule.CreatedBy = adminOid
+	rule.CreatedAt = time.Now()
+	rule.UpdatedAt = rule.CreatedAt
+	return service.substitutionRepo.CreateRule(rule)
+}
+
+func (service *SubstitutionService) UpdateRule(id string, request dtos.SubstitutionRuleRequest) (models.SubstitutionRule, error) {
+	if err := request.Validate(); err != nil {
+		return models.SubstitutionRule{}, err
+	}
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.SubstitutionRule{}, errors.New("invalid id")
+	}
+	current, err := service.substitutionRepo.GetRuleById(oid)
+	if err != nil {
+		return models.SubstitutionRule{}, errors.New("rule not found")
+	}
+	rule := dtos.SubstitutionRuleRequestToModel(request)
+	substitutions.Derive(&rule)
+	if rule.Key == "" {
+		return models.SubstitutionRule{}, errors.New("invalid rule, ingredient is required")
+	}
+	rule.ID = current.ID
+	rule.CreatedBy = current.CreatedBy
+	rule.CreatedAt = current.CreatedAt
+	rule.UpdatedAt = time.Now()
+	matched, err := service.substitutionRepo.UpdateRule(rule)
+	if err != nil {
+		return models.SubstitutionRule{}, err
+	}
+	if matched == 0 {
+		return models.SubstitutionRule{}, errors.New("rule not found")
+	}
+	return rule, nil
+}
+
+func (service *SubstitutionService) DeleteRule(id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.New("invalid id")
+	}
+	deleted, err := service.substitutionRepo.DeleteRule(oid)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return errors.New("rule not found")
+	}
+	return nil
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -15,7 +15,7 @@ type UserServiceInterface interface {
 	CreateUser(user dtos.RegisterRequest) (dtos.UserResponse, error)
 	UpdateUser(user dtos.RegisterRequest, id string) (dtos.UserResponse, error)
 	UpdatePassword(id string, password dtos.UpdatePasswordRequest) (dtos.UserResponse, error)
-	DeleteUser(id string) error
+	DeleteUser(id string, dryRun bool) (dtos.DeletionReport, error)
 	GetUserById(id string) (dtos.UserResponse, error)
 	GetUserByEmail(email string) (dtos.UserResponse, error)
 	GetUserByName(name string) (dtos.UserResponse, error)
@@ -23,11 +23,12 @@ type UserServiceInterface interface {
 }
 
 type UserService struct {
-	repo repositories.UserRepositoryInterface
+	repo            repositories.UserRepositoryInterface
+	deletionService DeletionServiceInterface
 }
 
-func NewUserService(r repositories.UserRepositoryInterface) *UserService {
-	return &UserService{repo: r}
+func NewUserService(r repositories.UserRepositoryInterface, deletionService DeletionServiceInterface) *UserService {
+	return &UserService{repo: r, deletionService: deletionService}
 }
 
 func (service *UserService) CreateUser(user dtos.RegisterRequest) (dtos.UserResponse, error) {
@@ -154,17 +155,14 @@ func (service *UserService) GetUserByName(name string) (dtos.UserResponse, error
 	return dtos.UserModelToResponse(result), nil
 }
 
-func (service *UserService) DeleteUser(id string) error {
+func (service *UserService) DeleteUser(id string, dryRun bool) (dtos.DeletionReport, error) {
 	oid, ok := primitive.ObjectIDFromHex(id)
 	if ok != nil {
-		return errors.New("invalid id")
+		return dtos.DeletionReport{}, errors.New("invalid id")
 	}
-	//verificamos la cantidad de documentos eliminados, si es 0 ha habido error
-	result, err := service.repo.DeleteUser(oid)
-	if result.DeletedCount == 0 {
-		return errors.New("user not found")
-	}
-	return err
+	//borramos sus recetas (con todo lo que dependa de ellas) y sus votos y guardados;
+	//sus comentarios en recetas ajenas quedan anonimizados
+	return service.deletionService.DeleteUser(oid, dryRun)
 }
 
 func (service *UserService) LoginOrRegisterGoogle(dto dtos.GoogleUserDTO) (dtos.UserResponse, error) {
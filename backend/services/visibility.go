@@ -0,0 +1,13 @@
+package services
+
+import (
+	"burned/backend/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// visibleTo indica si el usuario puede ver la receta: pública y no ocultada
+// por moderación, o propia.
+func visibleTo(recipe models.Recipe, userId primitive.ObjectID) bool {
+	return (recipe.Visibility == "public" && !recipe.Hidden) || recipe.UserID == userId
+}
@@ -0,0 +1,128 @@
+package shopping
+
+import "strings"
+
+// Pasillos del supermercado en el orden en que se recorre la lista.
+const (
+	Produce   = "produce"
+	Meat      = "meat"
+	Seafood   = "seafood"
+	Dairy     = "dairy"
+	Bakery    = "bakery"
+	Pantry    = "pantry"
+	Spices    = "spices"
+	Frozen    = "frozen"
+	Beverages = "beverages"
+	Other     = "other"
+)
+
+var Aisles = []string{Produce, Meat, Seafood, Dairy, Bakery, Pantry, Spices, Frozen, Beverages, Other}
+
+var aisleKeywords = map[string][]string{
+	Produce: {
+		"tomate", "tomato", "cebolla", "onion", "ajo", "garlic", "papa", "patata", "potato", "zanahoria", "carrot",
+		"lechuga", "lettuce", "espinaca", "spinach", "pimiento", "morron", "pepper", "zapallo", "calabaza", "pumpkin",
+		"zucchini", "zapallito", "calabacin", "berenjena", "eggplant", "pepino", "cucumber", "apio", "celery",
+		"brocoli", "broccoli", "coliflor", "cauliflower", "repollo", "cabbage", "choclo", "corn", "palta", "aguacate",
+		"avocado", "limon", "lemon", "lima", "lime", "naranja", "orange", "manzana", "apple", "banana", "platano",
+		"frutilla", "fresa", "strawberry", "berry", "arandano", "blueberry", "pera", "pear", "uva", "grape",
+		"hongo", "champinon", "mushroom", "perejil", "parsley", "cilantro", "coriander", "albahaca", "basil",
+		"menta", "mint", "romero", "rosemary", "tomillo", "thyme", "jengibre", "ginger", "verdeo", "scallion",
+		"puerro", "leek", "batata", "boniato", "sweet potato", "remolacha", "beet", "rucula", "arugula", "kale",
+		"chile", "aji", "jalapeno", "chili",
+	},
+	Meat: {
+		"carne", "beef", "pollo", "chicken", "cerdo", "pork", "jamon", "ham", "panceta", "tocino", "bacon",
+		"chorizo", "salchicha", "sausage", "cordero", "lamb", "pavo", "turkey", "bife", "steak", "lomo",
+		"molida", "ground beef", "costilla", "rib", "pechuga", "breast", "muslo", "thigh", "salame", "salami",
+	},
+	Seafood: {
+		"pescado", "fish", "salmon", "atun", "tuna", "merluza", "hake", "bacalao", "cod", "camaron", "langostino",
+		"gamba", "shrimp", "prawn", "calamar", "squid", "mejillon", "mussel", "almeja", "clam", "anchoa", "anchovy",
+	},
+	Dairy: {
+		"leche", "milk", "manteca", "mantequilla", "butter", "queso", "cheese", "crema", "cream", "yogur", "yogurt",
+		"huevo", "egg", "ricota", "ricotta", "mozzarella", "parmesano", "parmesan", "dulce de leche", "buttermilk",
+		"nata",
+	},
+	Bakery: {
+		"pan", "bread", "baguette", "tortilla", "pita", "bollo", "bun", "medialuna", "croissant", "masa", "dough",
+		"tapa de empanada", "pionono",
+	},
+	Pantry: {
+		"harina", "flour", "azucar", "sugar", "arroz", "rice", "fideo", "pasta", "spaghetti", "noodle", "aceite", "oil",
+		"vinagre", "vinegar", "lenteja", "lentil", "garbanzo", "chickpea", "poroto", "frijol", "bean", "avena", "oat",
+		"levadura", "yeast", "polvo de hornear", "baking powder", "bicarbonato", "baking soda", "miel", "honey",
+		"chocolate", "cacao", "cocoa", "nuez", "walnut", "almendra", "almond", "mani", "peanut", "caldo", "broth",
+		"stock", "salsa", "sauce", "mostaza", "mustard", "mayonesa", "mayonnaise", "ketchup", "pure de tomate",
+		"tomato paste", "tomate triturado", "lata", "esencia de vainilla", "vanilla", "maicena", "cornstarch",
+		"pan rallado", "breadcrumb", "quinoa", "polenta", "cuscus", "couscous", "aceituna", "olive", "soja", "soy",
+	},
+	Spices: {
+		"sal", "salt", "pimienta", "black pepper", "comino", "cumin", "oregano", "pimenton", "paprika", "canela",
+		"cinnamon", "nuez moscada", "nutmeg", "curry", "curcuma", "turmeric", "laurel", "bay leaf", "clavo de olor",
+		"aji molido", "chili flakes", "provenzal", "especia", "spice",
+	},
+	Frozen: {
+		"congelado", "congelada", "frozen", "helado", "ice cream", "hielo", "ice",
+	},
+	Beverages: {
+		"vino", "wine", "cerveza", "beer", "jugo", "juice", "agua", "water", "gaseosa", "soda", "cafe", "coffee",
+		"te", "tea", "ron", "rum", "vodka", "whisky", "licor", "liqueur",
+	},
+}
+
+// keywordAisle indexa las palabras clave por su Key. Si una palabra aparece
+// en dos pasillos gana el primero del orden de Aisles.
+var keywordAisle = func() map[string]string {
+	index := map[string]string{}
+	for i := len(Aisles) - 1; i >= 0; i-- {
+		for _, keyword := range aisleKeywords[Aisles[i]] {
+			index[Key(keyword)] = Aisles[i]
+		}
+	}
+	return index
+}()
+
+// ValidAisle indica si aisle es uno de los pasillos conocidos.
+func ValidAisle(aisle string) bool {
+	for _, known := range Aisles {
+		if known == aisle {
+			return true
+		}
+	}
+	return false
+}
+
+// AisleOf elige el pasillo de un ingrediente a partir de su Key. Primero se
+// prueba el nombre completo, después las frases de dos y tres palabras
+// ("polvo de hornear", "ice cream") y por último cada palabra en orden.
+func AisleOf(key string) string {
+	if aisle, ok := keywordAisle[key]; ok {
+		return aisle
+	}
+	words := strings.Fields(key)
+	for size := 3; size >= 2; size-- {
+		for start := 0; start+size <= len(words); start++ {
+			if aisle, ok := keywordAisle[strings.Join(words[start:start+size], " ")]; ok {
+				return aisle
+			}
+		}
+	}
+	for _, word := range words {
+		if aisle, ok := keywordAisle[word]; ok {
+			return aisle
+		}
+	}
+	return Other
+}
+
+// AisleIndex es la posición del pasillo en el recorrido; los desconocidos van al final.
+func AisleIndex(aisle string) int {
+	for i, known := range Aisles {
+		if known == aisle {
+			return i
+		}
+	}
+	return len(Aisles)
+}
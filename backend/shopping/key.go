@@ -0,0 +1,106 @@
+package shopping
+
+import (
+	"burned/backend/units"
+	"strings"
+)
+
+// irregulars son plurales que la regla general no resuelve.
+var irregulars = map[string]string{
+	"nueces":  "nuez",
+	"leaves":  "leaf",
+	"loaves":  "loaf",
+	"halves":  "half",
+	"knives":  "knife",
+	"peces":   "pez",
+	"raices":  "raiz",
+	"lapices": "lapiz",
+}
+
+// Key es la forma con la que se comparan nombres de ingredientes: minúsculas,
+// sin acentos ni puntuación y cada palabra reducida a una raíz simple, para
+// que "Limones" y "limón" o "eggs" y "egg" se junten. No es para mostrar.
+func Key(name string) string {
+	words := strings.Fields(strings.NewReplacer(",", " ", ".", " ", "(", " ", ")", " ").Replace(units.Normalize(name)))
+	for i, word := range words {
+		words[i] = stem(word)
+	}
+	return strings.Join(words, " ")
+}
+
+// stem saca el plural (español o inglés) y la "e" final, de modo que singular
+// y plural terminen en la misma raíz: "limon"/"limones" -> "limon",
+// "tomate"/"tomates" -> "tomat", "potato"/"potatoes" -> "potato".
+func stem(word string) string {
+	if singular, ok := irregulars[word]; ok {
+		word = singular
+	}
+	if len(word) <= 3 {
+		return word
+	}
+	switch {
+	case strings.HasSuffix(word, "ies"):
+		word = strings.TrimSuffix(word, "ies") + "y"
+	case strings.HasSuffix(word, "oes"):
+		word = strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") && !strings.HasSuffix(word, "us") && !strings.HasSuffix(word, "is"):
+		word = strings.TrimSuffix(word, "s")
+	}
+	if len(word) > 3 && strings.HasSuffix(word, "e") && !strings.HasSuffix(word, "ee") {
+		word = strings.TrimSuffix(word, "e")
+	}
+	return word
+}
+
+// maxTermWords acota las frases que se generan por ingrediente.
+const maxTermWords = 6
+
+// fillerWords no alcanzan por sí solas para identificar un ingrediente:
+// una frase de Terms no empieza ni termina en ellas.
+var fillerWords = func() map[string]bool {
+	words := []string{
+		"de", "del", "la", "las", "el", "los", "y", "o", "con", "sin", "en", "para", "a", "al",
+		"of", "the", "and", "or", "with", "without", "for", "in",
+		"fresco", "fresca", "fresh", "grande", "large", "chico", "chica", "small", "mediano", "mediana", "medium",
+		"picado", "picada", "chopped", "rallado", "rallada", "grated", "entero", "entera", "whole",
+	}
+	set := map[string]bool{}
+	for _, word := range words {
+		set[Key(word)] = true
+	}
+	return set
+}()
+
+// Terms devuelve las frases de Key(name) que identifican al ingrediente: el
+// nombre completo y cada tramo de palabras consecutivas que no empieza ni
+// termina en una palabra de relleno. Así "aceite de oliva extra virgen"
+// coincide con "aceite de oliva" y con "aceite", pero "pan rallado" no
+// coincide con "rallado". Es lo que se compara contra la despensa.
+func Terms(name string) []string {
+	key := Key(name)
+	if key == "" {
+		return nil
+	}
+	words := strings.Fields(key)
+	if len(words) > maxTermWords {
+		words = words[:maxTermWords]
+	}
+	terms := []string{key}
+	seen := map[string]bool{key: true}
+	for start := range words {
+		if fillerWords[words[start]] {
+			continue
+		}
+		for end := start + 1; end <= len(words); end++ {
+			if fillerWords[words[end-1]] {
+				continue
+			}
+			term := strings.Join(words[start:end], " ")
+			if !seen[term] {
+				seen[term] = true
+				terms = append(terms, term)
+			}
+		}
+	}
+	return terms
+}
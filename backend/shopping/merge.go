@@ -0,0 +1,75 @@
+package shopping
+
+import (
+	"burned/backend/scaling"
+	"burned/backend/units"
+)
+
+// baseUnits son las unidades en las que se suman masa y volumen antes de
+// expresarlas de nuevo en una unidad cómoda.
+var baseUnits = map[units.Dimension]string{
+	units.Mass:   "g",
+	units.Volume: "ml",
+}
+
+// Merge suma las cantidades de un mismo ingrediente. Masa con masa y volumen
+// con volumen se suman aunque vengan en sistemas distintos; las de conteo
+// solo con la misma unidad (2 dientes y 1 unidad de ajo quedan separadas) y
+// las desconocidas solo si la unidad coincide exactamente. "A gusto" se
+// descarta cuando hay alguna cantidad concreta. Si system está vacío cada
+// suma se expresa en el sistema de la primera unidad que la aportó. El
+// resultado conserva el orden de aparición y está redondeado a medidas de cocina.
+func Merge(amounts []scaling.Amount, system units.System) []scaling.Amount {
+	type bucket struct {
+		amount scaling.Amount
+		system units.System
+	}
+	order := []string{}
+	buckets := map[string]*bucket{}
+	add := func(key string, amount scaling.Amount, unitSystem units.System) {
+		if current, ok := buckets[key]; ok {
+			current.amount.Quantity += amount.Quantity
+			return
+		}
+		order = append(order, key)
+		buckets[key] = &bucket{amount: amount, system: unitSystem}
+	}
+
+	for _, amount := range amounts {
+		unit, ok := units.Lookup(amount.Unit)
+		switch {
+		case !ok:
+			add("raw:"+amount.Unit, amount, "")
+		case unit.Dimension == units.ToTaste:
+			add(units.CodeToTaste, scaling.Amount{Unit: units.CodeToTaste}, "")
+		case baseUnits[unit.Dimension] != "":
+			converted, err := scaling.Convert(amount, baseUnits[unit.Dimension])
+			if err != nil {
+				add("raw:"+amount.Unit, amount, "")
+				continue
+			}
+			add(string(unit.Dimension), converted, unit.System)
+		default:
+			add("count:"+unit.Code, amount, "")
+		}
+	}
+
+	merged := make([]scaling.Amount, 0, len(order))
+	for _, key := range order {
+		if key == units.CodeToTaste && len(order) > 1 {
+			continue
+		}
+		current := buckets[key]
+		amount := current.amount
+		if current.system != "" {
+			target := system
+			if target == "" {
+				target = current.system
+			}
+			amount = scaling.ToSystem(amount, target)
+		}
+		amount.Quantity = scaling.Round(amount)
+		merged = append(merged, amount)
+	}
+	return merged
+}
@@ -0,0 +1,101 @@
+package shopping
+
+import (
+	"burned/backend/models"
+	"burned/backend/scaling"
+	"bytes"
+	"encoding/csv"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Sort ordena los ítems por pasillo y dentro de cada pasillo por nombre,
+// dejando los ya tachados al final.
+func Sort(items []models.ShoppingItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		if a, b := AisleIndex(items[i].Aisle), AisleIndex(items[j].Aisle); a != b {
+			return a < b
+		}
+		if items[i].Checked != items[j].Checked {
+			return !items[i].Checked
+		}
+		return strings.ToLower(items[i].Name) < strings.ToLower(items[j].Name)
+	})
+}
+
+// Display arma el texto de las cantidades de un ítem: "1 kg + 2 can".
+func Display(amounts []models.ShoppingAmount) string {
+	parts := make([]string, 0, len(amounts))
+	for _, amount := range amounts {
+		parts = append(parts, scaling.Format(scaling.Amount{Quantity: amount.Quantity, Unit: amount.Unit}))
+	}
+	return strings.Join(parts, " + ")
+}
+
+// Text arma la lista en texto plano, un bloque por pasillo, con [x] en los
+// ítems tachados.
+func Text(items []models.ShoppingItem) []byte {
+	items = append([]models.ShoppingItem{}, items...)
+	Sort(items)
+
+	var buffer bytes.Buffer
+	buffer.WriteString("Shopping list\n")
+	aisle := ""
+	for _, item := range items {
+		if item.Aisle != aisle {
+			aisle = item.Aisle
+			buffer.WriteString("\n" + strings.ToUpper(aisle) + "\n")
+		}
+		mark := "[ ] "
+		if item.Checked {
+			mark = "[x] "
+		}
+		line := item.Name
+		if display := Display(item.Amounts); display != "" {
+			line = display + " " + item.Name
+		}
+		buffer.WriteString(mark + line + "\n")
+	}
+	return buffer.Bytes()
+}
+
+// CSV escribe una fila por cantidad (un ítem con "1 kg + 2 can" ocupa dos
+// filas) para que la columna quantity se pueda sumar en una planilla.
+func CSV(items []models.ShoppingItem) ([]byte, error) {
+	items = append([]models.ShoppingItem{}, items...)
+	Sort(items)
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	if err := writer.Write([]string{"aisle", "item", "quantity", "unit", "checked", "manual"}); err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		amounts := item.Amounts
+		if len(amounts) == 0 {
+			amounts = []models.ShoppingAmount{{}}
+		}
+		for _, amount := range amounts {
+			quantity := ""
+			if amount.Quantity > 0 {
+				quantity = scaling.FormatDecimal(amount.Quantity)
+			}
+			record := []string{item.Aisle, csvCell(item.Name), quantity, amount.Unit, strconv.FormatBool(item.Checked), strconv.FormatBool(item.Manual)}
+			if err := writer.Write(record); err != nil {
+				return nil, err
+			}
+		}
+	}
+	writer.Flush()
+	return buffer.Bytes(), writer.Error()
+}
+
+// csvCell evita que una planilla interprete como fórmula un nombre escrito
+// por el usuario ("=HYPERLINK(...)").
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,42 @@
+package substitutions
+
+import "burned/backend/models"
+
+// Defaults son las reglas de fábrica. Se cargan una sola vez (migración
+// 0006_substitution_rules); después las mantienen los admins.
+var Defaults = []models.SubstitutionRule{
+	rule("buttermilk", 1, "cup", "Dejar reposar 10 minutos antes de usar.", part("leche", 1, "cup"), part("jugo de limón", 1, "tbsp")),
+	rule("buttermilk", 1, "cup", "", part("yogur natural", 0.75, "cup"), part("leche", 0.25, "cup")),
+	rule("huevo", 1, "unit", "Huevo de lino: mezclar y dejar reposar 5 minutos. Para masas, galletitas y budines.", part("semillas de lino molidas", 1, "tbsp"), part("agua", 3, "tbsp")),
+	rule("huevo", 1, "unit", "Para tortas y budines húmedos.", part("puré de manzana", 0.25, "cup")),
+	rule("huevo", 1, "unit", "Aquafaba (líquido de la lata de garbanzos). Se puede batir a punto nieve.", part("aquafaba", 3, "tbsp")),
+	rule("egg", 1, "unit", "Flax egg: mix and let sit for 5 minutes.", part("ground flaxseed", 1, "tbsp"), part("water", 3, "tbsp")),
+	rule("manteca", 1, "cup", "Para horneados; no sirve para masas hojaldradas.", part("aceite de girasol", 0.75, "cup")),
+	rule("manteca", 100, "g", "Usar sólido si la receta pide manteca fría.", part("aceite de coco", 100, "g")),
+	rule("leche", 1, "cup", "", part("leche de almendras", 1, "cup")),
+	rule("leche", 1, "cup", "", part("leche de avena", 1, "cup")),
+	rule("leche", 1, "cup", "", part("leche de soja", 1, "cup")),
+	rule("crema de leche", 1, "cup", "Usar leche de coco entera, no la light.", part("leche de coco", 1, "cup")),
+	rule("crema de leche", 1, "cup", "No sirve para batir a punto chantilly.", part("leche", 0.75, "cup"), part("manteca derretida", 0.25, "cup")),
+	rule("harina", 1, "cup", "Si la premezcla no trae goma xántica, agregar 1/4 cdita por taza.", part("premezcla sin gluten", 1, "cup")),
+	rule("azúcar", 1, "cup", "Reducir los líquidos de la receta en 1/4 de taza y bajar el horno 10 °C.", part("miel", 0.75, "cup")),
+	rule("miel", 1, "cup", "", part("jarabe de arce", 1, "cup")),
+	rule("salsa de soja", 1, "tbsp", "Controlar que el envase diga sin TACC.", part("tamari sin gluten", 1, "tbsp")),
+	rule("salsa de soja", 1, "tbsp", "Es más dulce: reducir el azúcar de la receta.", part("aminos de coco", 1, "tbsp")),
+	rule("yogur", 1, "cup", "", part("yogur vegetal", 1, "cup")),
+	rule("queso parmesano", 0.25, "cup", "Para gratinar o espolvorear.", part("levadura nutricional", 0.25, "cup")),
+	rule("pan rallado", 1, "cup", "Para rebozar sin gluten.", part("polenta", 1, "cup")),
+	rule("mayonesa", 1, "cup", "Para aderezos fríos.", part("yogur natural", 1, "cup")),
+	rule("vino blanco", 1, "cup", "Para guisos y salsas.", part("caldo de verdura", 1, "cup"), part("vinagre de manzana", 1, "tbsp")),
+	rule("jugo de limón", 1, "tbsp", "", part("vinagre blanco", 1, "tbsp")),
+	rule("polvo de hornear", 1, "tsp", "", part("bicarbonato de sodio", 0.25, "tsp"), part("cremor tártaro", 0.5, "tsp")),
+	rule("carne picada", 500, "g", "Para salsas, rellenos y hamburguesas.", part("lentejas cocidas", 500, "g")),
+}
+
+func rule(ingredient string, quantity float64, unit string, note string, parts ...models.SubstitutePart) models.SubstitutionRule {
+	return models.SubstitutionRule{Ingredient: ingredient, Quantity: quantity, Unit: unit, Note: note, Substitute: parts}
+}
+
+func part(name string, quantity float64, unit string) models.SubstitutePart {
+	return models.SubstitutePart{Name: name, Quantity: quantity, Unit: unit}
+}
@@ -0,0 +1,183 @@
+// Package substitutions elige y calcula reemplazos de ingredientes a partir
+// de reglas con una cantidad de referencia.
+package substitutions
+
+import (
+	"burned/backend/dietary"
+	"burned/backend/models"
+	"burned/backend/scaling"
+	"burned/backend/shopping"
+	"burned/backend/units"
+	"strings"
+)
+
+// Derive completa los campos calculados de una regla: la clave con la que
+// se busca y los alérgenos y dietas del reemplazo.
+func Derive(rule *models.SubstitutionRule) {
+	rule.Key = shopping.Key(rule.Ingredient)
+	parts := make([]models.Ingredient, len(rule.Substitute))
+	for i, part := range rule.Substitute {
+		parts[i] = models.Ingredient{Name: part.Name, Unit: part.Unit}
+	}
+	rule.Allergens, rule.Diets, rule.Unclassified = dietary.Classify(parts)
+}
+
+// Match devuelve las reglas que corresponden a un ingrediente. Se quedan
+// las de la clave más larga que aparece en el nombre, así "leche de coco"
+// no recibe los reemplazos de "leche" si tiene los suyos. Una clave no vale
+// si en el nombre es el complemento de otro ingrediente ("dulce de leche") o
+// si el ingrediente tiene otros alérgenos que ella ("manteca de maní").
+func Match(rules []models.SubstitutionRule, name string) []models.SubstitutionRule {
+	key := shopping.Key(name)
+	categories := strings.Join(dietary.Categories(name), ",")
+	terms := map[string]bool{}
+	for _, term := range shopping.Terms(name) {
+		terms[term] = true
+	}
+	matched := []models.SubstitutionRule{}
+	longest := 0
+	for _, rule := range rules {
+		if !terms[rule.Key] || !isHead(key, rule.Key) || strings.Join(dietary.Categories(rule.Ingredient), ",") != categories {
+			continue
+		}
+		words := len(strings.Fields(rule.Key))
+		if words > longest {
+			matched, longest = matched[:0], words
+		}
+		if words == longest {
+			matched = append(matched, rule)
+		}
+	}
+	return matched
+}
+
+// isHead indica si phrase aparece en key sin ir después de "de": en
+// "harina de trigo" la cabeza es "harina", no "trigo".
+func isHead(key string, phrase string) bool {
+	words := strings.Fields(key)
+	size := len(strings.Fields(phrase))
+	for start := 0; start+size <= len(words); start++ {
+		if strings.Join(words[start:start+size], " ") != phrase {
+			continue
+		}
+		if start == 0 || !complementWords[words[start-1]] {
+			return true
+		}
+	}
+	return false
+}
+
+// complementWords introducen el complemento de un ingrediente
+var complementWords = map[string]bool{"de": true, "del": true, "of": true}
+
+// Suits indica si el reemplazo no tiene ninguno de los alérgenos excluidos y
+// entra en todas las dietas pedidas. Un reemplazo con partes sin clasificar
+// no se puede asegurar libre de nada.
+func Suits(rule models.SubstitutionRule, excludeAllergens []string, diets []string) bool {
+	if len(rule.Unclassified) > 0 && (len(excludeAllergens) > 0 || len(diets) > 0) {
+		return false
+	}
+	for _, allergen := range excludeAllergens {
+		if contains(rule.Allergens, allergen) {
+			return false
+		}
+	}
+	for _, diet := range diets {
+		if !contains(rule.Diets, diet) {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply calcula las cantidades del reemplazo para la cantidad del
+// ingrediente en la receta. Las partes medidas en la unidad de referencia de
+// la regla se expresan en la unidad de la receta, en proporción ("3/4 cup
+// aceite por cup de manteca" da 150 g de aceite para 200 g de manteca). Las
+// demás necesitan pasar la cantidad de la receta a la unidad de la regla; si
+// una mide en masa y la otra en volumen se usa density en g/ml (0 si no se
+// conoce). Devuelve false cuando no hay proporción posible ("a gusto",
+// conteos distintos) y entonces solo vale el texto de Ratio.
+func Apply(rule models.SubstitutionRule, ingredient models.Ingredient, density float64, system units.System) ([]models.Ingredient, bool) {
+	unit, ok := units.Lookup(ingredient.Unit)
+	if !ok || unit.Dimension == units.ToTaste || ingredient.Quantity <= 0 || rule.Quantity <= 0 {
+		return nil, false
+	}
+	// sin sistema pedido, el reemplazo se expresa en el de la receta
+	if system == "" {
+		system = unit.System
+	}
+
+	parts := make([]models.Ingredient, 0, len(rule.Substitute))
+	for _, part := range rule.Substitute {
+		var amount scaling.Amount
+		if part.Unit == rule.Unit {
+			amount = scaling.Amount{Quantity: ingredient.Quantity * part.Quantity / rule.Quantity, Unit: ingredient.Unit}
+		} else {
+			quantity, ok := inUnit(ingredient, rule.Unit, density)
+			if !ok {
+				return nil, false
+			}
+			amount = scaling.Amount{Quantity: quantity * part.Quantity / rule.Quantity, Unit: part.Unit}
+		}
+		amount = scaling.ToSystem(amount, system)
+		amount.Quantity = scaling.Round(amount)
+		parts = append(parts, models.Ingredient{
+			Name:     part.Name,
+			Quantity: amount.Quantity,
+			Unit:     amount.Unit,
+			Display:  scaling.Format(amount),
+		})
+	}
+	return parts, true
+}
+
+// Ratio describe la regla: "1 cup buttermilk = 1 cup leche + 1 tbsp jugo de limón".
+func Ratio(rule models.SubstitutionRule) string {
+	parts := make([]string, 0, len(rule.Substitute))
+	for _, part := range rule.Substitute {
+		parts = append(parts, describe(part.Quantity, part.Unit, part.Name))
+	}
+	return describe(rule.Quantity, rule.Unit, rule.Ingredient) + " = " + strings.Join(parts, " + ")
+}
+
+func describe(quantity float64, unit string, name string) string {
+	amount := scaling.Amount{Quantity: quantity, Unit: unit}
+	if unit == units.CodeToTaste {
+		return name + " " + scaling.Format(amount)
+	}
+	return scaling.Format(amount) + " " + name
+}
+
+// inUnit expresa la cantidad del ingrediente en la unidad de la regla.
+func inUnit(ingredient models.Ingredient, target string, density float64) (float64, bool) {
+	amount := scaling.Amount{Quantity: ingredient.Quantity, Unit: ingredient.Unit}
+	if converted, err := scaling.Convert(amount, target); err == nil {
+		return converted.Quantity, true
+	}
+	from, fromOk := units.Lookup(ingredient.Unit)
+	to, toOk := units.Lookup(target)
+	if !fromOk || !toOk || density <= 0 {
+		return 0, false
+	}
+	switch {
+	case from.Dimension == units.Mass && to.Dimension == units.Volume:
+		grams, _ := scaling.Convert(amount, "g")
+		converted, err := scaling.Convert(scaling.Amount{Quantity: grams.Quantity / density, Unit: "ml"}, target)
+		return converted.Quantity, err == nil
+	case from.Dimension == units.Volume && to.Dimension == units.Mass:
+		milliliters, _ := scaling.Convert(amount, "ml")
+		converted, err := scaling.Convert(scaling.Amount{Quantity: milliliters.Quantity * density, Unit: "g"}, target)
+		return converted.Quantity, err == nil
+	}
+	return 0, false
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package substitutions
+
+import (
+	"burned/backend/dietary"
+	"burned/backend/models"
+	"testing"
+)
+
+func derived(parts ...string) models.SubstitutionRule {
+	rule := models.SubstitutionRule{Ingredient: "manteca", Quantity: 100, Unit: "g"}
+	for _, name := range parts {
+		rule.Substitute = append(rule.Substitute, models.SubstitutePart{Name: name, Quantity: 80, Unit: "g"})
+	}
+	Derive(&rule)
+	return rule
+}
+
+func TestSuitsRejectsUnknownSubstitute(t *testing.T) {
+	rule := derived("aceite de coco", "relleno misterioso")
+	if len(rule.Unclassified) != 1 || len(rule.Diets) != 0 {
+		t.Fatalf("Derive: diets %v, unclassified %v", rule.Diets, rule.Unclassified)
+	}
+	if Suits(rule, []string{dietary.Dairy}, nil) {
+		t.Errorf("unknown substitute suits excludeAllergens=dairy")
+	}
+	if Suits(rule, nil, []string{dietary.Vegan}) {
+		t.Errorf("unknown substitute suits diets=vegan")
+	}
+	if !Suits(rule, nil, nil) {
+		t.Errorf("without filters every substitute suits")
+	}
+}
+
+func TestSuitsKnownSubstitute(t *testing.T) {
+	rule := derived("aceite de coco")
+	if !Suits(rule, []string{dietary.Dairy}, []string{dietary.Vegan}) {
+		t.Errorf("aceite de coco should suit dairy free vegan: allergens %v, diets %v", rule.Allergens, rule.Diets)
+	}
+	if Suits(derived("provolone"), nil, []string{dietary.Vegan}) {
+		t.Errorf("provolone suits diets=vegan")
+	}
+}
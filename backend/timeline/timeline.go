@@ -0,0 +1,199 @@
+// Package timeline calcula a qué hora empieza cada paso de una receta para
+// que esté lista a una hora dada.
+package timeline
+
+import (
+	"burned/backend/models"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Slot es el horario de un paso; Step es su posición en la receta (desde 0).
+type Slot struct {
+	Step  int
+	Start time.Time
+	End   time.Time
+}
+
+// Task es un paso ubicado en minutos desde que se empieza a cocinar.
+// Critical indica que está en el camino que define el tiempo total.
+type Task struct {
+	Step     int
+	Start    int
+	End      int
+	Passive  bool
+	Critical bool
+}
+
+// Plan es el cronograma de una receta. Total son los minutos desde el primer
+// paso hasta que está lista; Critical, los pasos que lo definen en orden.
+type Plan struct {
+	Total    int
+	Tasks    []Task
+	Critical []int
+}
+
+// Dependencies devuelve de qué pasos depende cada uno. Un paso que declara
+// DependsOn espera solo a esos; uno que no lo declara va después del
+// anterior, como en una lista simple. Así agregar una dependencia en un paso
+// no adelanta a los demás: solo el primero empieza desde el principio.
+func Dependencies(steps []models.Step) [][]int {
+	dependencies := make([][]int, len(steps))
+	for i, step := range steps {
+		switch {
+		case len(step.DependsOn) > 0:
+			dependencies[i] = step.DependsOn
+		case i > 0:
+			dependencies[i] = []int{i - 1}
+		}
+	}
+	return dependencies
+}
+
+// Validate revisa que las dependencias apunten a pasos anteriores (así no
+// puede haber ciclos) y que los ingredientes referidos existan.
+func Validate(steps []models.Step, ingredients int) error {
+	for i, step := range steps {
+		seen := map[int]bool{}
+		for _, dependency := range step.DependsOn {
+			if dependency < 0 || dependency >= i {
+				return fmt.Errorf("step %d: it can only depend on earlier steps", i+1)
+			}
+			if seen[dependency] {
+				return fmt.Errorf("step %d: repeated dependency", i+1)
+			}
+			seen[dependency] = true
+		}
+		for _, ingredient := range step.Ingredients {
+			if ingredient < 0 || ingredient >= ingredients {
+				return fmt.Errorf("step %d: unknown ingredient %d", i+1, ingredient)
+			}
+		}
+	}
+	return nil
+}
+
+// Timed indica si todos los pasos tienen tiempo cargado, es decir, si el
+// total que calcula Schedule es confiable.
+func Timed(steps []models.Step) bool {
+	for _, step := range steps {
+		if step.Time <= 0 {
+			return false
+		}
+	}
+	return len(steps) > 0
+}
+
+// Schedule ubica cada paso lo antes posible respetando sus dependencias y
+// que hay una sola persona cocinando: los pasos activos no se superponen
+// entre sí, los pasivos ("reposar 30 min", "hornear") corren en paralelo con
+// cualquier otro. Un paso activo puede ocupar un hueco libre anterior si
+// sus dependencias ya terminaron.
+func Schedule(steps []models.Step) Plan {
+	dependencies := Dependencies(steps)
+	tasks := make([]Task, len(steps))
+	busy := []Task{} // pasos activos ya ubicados, por inicio
+	total := 0
+
+	for i, step := range steps {
+		duration := step.Time
+		if duration < 0 {
+			duration = 0
+		}
+		earliest := 0
+		for _, dependency := range dependencies[i] {
+			if tasks[dependency].End > earliest {
+				earliest = tasks[dependency].End
+			}
+		}
+		start := earliest
+		if !step.Passive && duration > 0 {
+			start = firstGap(busy, earliest, duration)
+		}
+		tasks[i] = Task{Step: i, Start: start, End: start + duration, Passive: step.Passive}
+		if !step.Passive && duration > 0 {
+			busy = append(busy, tasks[i])
+			sort.Slice(busy, func(a, b int) bool { return busy[a].Start < busy[b].Start })
+		}
+		if tasks[i].End > total {
+			total = tasks[i].End
+		}
+	}
+
+	plan := Plan{Total: total, Tasks: tasks, Critical: []int{}}
+	plan.Critical = criticalPath(tasks, dependencies, total)
+	for _, step := range plan.Critical {
+		plan.Tasks[step].Critical = true
+	}
+	return plan
+}
+
+// firstGap busca el primer momento desde earliest en que la persona que
+// cocina está libre durante duration minutos.
+func firstGap(busy []Task, earliest int, duration int) int {
+	start := earliest
+	for _, task := range busy {
+		if task.End <= start {
+			continue
+		}
+		if task.Start >= start+duration {
+			break
+		}
+		start = task.End
+	}
+	return start
+}
+
+// criticalPath recorre hacia atrás desde el último paso en terminar: cada
+// paso empezó cuando terminó una de sus dependencias o, si esperó a que la
+// persona se liberara, el paso activo que la ocupaba.
+func criticalPath(tasks []Task, dependencies [][]int, total int) []int {
+	current := -1
+	for i := len(tasks) - 1; i >= 0; i-- {
+		if tasks[i].End == total {
+			current = i
+			break
+		}
+	}
+	path := []int{}
+	for current >= 0 {
+		path = append(path, current)
+		task := tasks[current]
+		previous := -1
+		for _, dependency := range dependencies[current] {
+			if tasks[dependency].End == task.Start {
+				previous = dependency
+			}
+		}
+		if previous < 0 && !task.Passive && task.Start > 0 {
+			for i := current - 1; i >= 0; i-- {
+				if !tasks[i].Passive && tasks[i].End == task.Start {
+					previous = i
+					break
+				}
+			}
+		}
+		current = previous
+	}
+	for left, right := 0, len(path)-1; left < right; left, right = left+1, right-1 {
+		path[left], path[right] = path[right], path[left]
+	}
+	return path
+}
+
+// Backwards ubica los pasos según Schedule de manera que la receta termine
+// en serveAt.
+func Backwards(steps []models.Step, serveAt time.Time) []Slot {
+	plan := Schedule(steps)
+	begin := serveAt.Add(-time.Duration(plan.Total) * time.Minute)
+	slots := make([]Slot, len(plan.Tasks))
+	for i, task := range plan.Tasks {
+		slots[i] = Slot{
+			Step:  task.Step,
+			Start: begin.Add(time.Duration(task.Start) * time.Minute),
+			End:   begin.Add(time.Duration(task.End) * time.Minute),
+		}
+	}
+	return slots
+}
@@ -0,0 +1,122 @@
+package timeline
+
+import (
+	"burned/backend/models"
+	"reflect"
+	"testing"
+)
+
+type span struct{ start, end int }
+
+func spans(plan Plan) []span {
+	result := make([]span, len(plan.Tasks))
+	for i, task := range plan.Tasks {
+		result[i] = span{task.Start, task.End}
+	}
+	return result
+}
+
+func TestDependencies(t *testing.T) {
+	steps := []models.Step{
+		{Title: "amasar"},
+		{Title: "leudar"},
+		{Title: "salsa", DependsOn: []int{0}},
+		{Title: "armar"},
+	}
+	want := [][]int{nil, {0}, {0}, {2}}
+	if got := Dependencies(steps); !reflect.DeepEqual(got, want) {
+		t.Errorf("Dependencies = %v, want %v", got, want)
+	}
+}
+
+func TestScheduleLinear(t *testing.T) {
+	plan := Schedule([]models.Step{{Time: 10}, {Time: 20}, {Time: 5}})
+	if plan.Total != 35 {
+		t.Errorf("Total = %d, want 35", plan.Total)
+	}
+	if want := []span{{0, 10}, {10, 30}, {30, 35}}; !reflect.DeepEqual(spans(plan), want) {
+		t.Errorf("tasks = %v, want %v", spans(plan), want)
+	}
+	if want := []int{0, 1, 2}; !reflect.DeepEqual(plan.Critical, want) {
+		t.Errorf("Critical = %v, want %v", plan.Critical, want)
+	}
+}
+
+// Declarar la dependencia de un paso no puede adelantar a los que no la
+// declaran: leudar sigue esperando al amasado.
+func TestScheduleUndeclaredStepFollowsPrevious(t *testing.T) {
+	plan := Schedule([]models.Step{
+		{Title: "amasar", Time: 15},
+		{Title: "leudar", Time: 60, Passive: true},
+		{Title: "salsa", Time: 20, DependsOn: []int{0}},
+		{Title: "armar", Time: 10},
+	})
+	if want := []span{{0, 15}, {15, 75}, {15, 35}, {35, 45}}; !reflect.DeepEqual(spans(plan), want) {
+		t.Errorf("tasks = %v, want %v", spans(plan), want)
+	}
+	if plan.Total != 75 {
+		t.Errorf("Total = %d, want 75", plan.Total)
+	}
+	if want := []int{0, 1}; !reflect.DeepEqual(plan.Critical, want) {
+		t.Errorf("Critical = %v, want %v", plan.Critical, want)
+	}
+}
+
+func TestSchedulePassiveRunsInParallel(t *testing.T) {
+	plan := Schedule([]models.Step{
+		{Title: "amasar", Time: 15},
+		{Title: "leudar", Time: 60, Passive: true},
+		{Title: "hornear", Time: 30, Passive: true},
+		{Title: "salsa", Time: 20, DependsOn: []int{0}},
+	})
+	if want := []span{{0, 15}, {15, 75}, {75, 105}, {15, 35}}; !reflect.DeepEqual(spans(plan), want) {
+		t.Errorf("tasks = %v, want %v", spans(plan), want)
+	}
+	if want := []int{0, 1, 2}; !reflect.DeepEqual(plan.Critical, want) {
+		t.Errorf("Critical = %v, want %v", plan.Critical, want)
+	}
+	for _, step := range plan.Critical {
+		if !plan.Tasks[step].Critical {
+			t.Errorf("task %d not marked critical", step)
+		}
+	}
+	if plan.Tasks[3].Critical {
+		t.Errorf("salsa marked critical")
+	}
+}
+
+// Un paso activo que espera a que la persona se libere sigue en el camino
+// crítico al paso que la ocupaba, no a su dependencia.
+func TestScheduleWaitsForCook(t *testing.T) {
+	plan := Schedule([]models.Step{
+		{Title: "amasar", Time: 10},
+		{Title: "reposar", Time: 5, Passive: true},
+		{Title: "picar", Time: 20, DependsOn: []int{0}},
+		{Title: "estirar", Time: 10, DependsOn: []int{1}},
+	})
+	if want := []span{{0, 10}, {10, 15}, {10, 30}, {30, 40}}; !reflect.DeepEqual(spans(plan), want) {
+		t.Errorf("tasks = %v, want %v", spans(plan), want)
+	}
+	if want := []int{0, 2, 3}; !reflect.DeepEqual(plan.Critical, want) {
+		t.Errorf("Critical = %v, want %v", plan.Critical, want)
+	}
+}
+
+func TestCriticalPath(t *testing.T) {
+	tasks := []Task{
+		{Step: 0, Start: 0, End: 10},
+		{Step: 1, Start: 0, End: 40, Passive: true},
+		{Step: 2, Start: 10, End: 25},
+		{Step: 3, Start: 40, End: 50},
+	}
+	dependencies := [][]int{nil, nil, {0}, {1, 2}}
+	if got, want := criticalPath(tasks, dependencies, 50), []int{1, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("criticalPath = %v, want %v", got, want)
+	}
+	if got, want := criticalPath(tasks, dependencies, 25), []int{0, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("criticalPath to 25 = %v, want %v", got, want)
+	}
+	if got := criticalPath(nil, nil, 0); len(got) != 0 {
+		t.Errorf("criticalPath of no tasks = %v, want empty", got)
+	}
+}
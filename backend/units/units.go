@@ -0,0 +1,139 @@
+package units
+
+import (
+	"sort"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Dimension agrupa las unidades que se pueden convertir entre sí.
+type Dimension string
+
+const (
+	Mass    Dimension = "mass"
+	Volume  Dimension = "volume"
+	Count   Dimension = "count"
+	ToTaste Dimension = "to_taste"
+)
+
+// System indica a qué sistema de medidas pertenece una unidad. Las unidades
+// de conteo y "a gusto" no tienen sistema.
+type System string
+
+const (
+	Metric   System = "metric"
+	Imperial System = "imperial"
+)
+
+// Códigos de las unidades más usadas por el resto del backend.
+const (
+	CodeUnit    = "unit"
+	CodeToTaste = "to_taste"
+)
+
+// Unit es una entrada del catálogo. ToBase es el factor para llevar una
+// cantidad a la unidad base de su dimensión: gramos para masa y mililitros
+// para volumen. En las unidades de conteo vale 1 y no se convierten entre sí.
+type Unit struct {
+	Code      string
+	Dimension Dimension
+	System    System
+	ToBase    float64
+	Aliases   []string
+}
+
+// En el catálogo no hay abreviaturas de una letra que dependan de la
+// mayúscula: Find compara en minúsculas y "T" (cucharada) terminaría como "t"
+// (cucharadita), tres veces menos.
+var catalog = []Unit{
+	// Masa
+	{Code: "mg", Dimension: Mass, System: Metric, ToBase: 0.001, Aliases: []string{"miligramo", "miligramos", "milligram", "milligrams"}},
+	{Code: "g", Dimension: Mass, System: Metric, ToBase: 1, Aliases: []string{"gr", "grs", "gramo", "gramos", "gram", "grams", "gramme", "grammes"}},
+	{Code: "kg", Dimension: Mass, System: Metric, ToBase: 1000, Aliases: []string{"kilo", "kilos", "kilogramo", "kilogramos", "kilogram", "kilograms"}},
+	{Code: "oz", Dimension: Mass, System: Imperial, ToBase: 28.349523125, Aliases: []string{"onza", "onzas", "ounce", "ounces"}},
+	{Code: "lb", Dimension: Mass, System: Imperial, ToBase: 453.59237, Aliases: []string{"lbs", "libra", "libras", "pound", "pounds"}},
+
+	// Volumen
+	{Code: "ml", Dimension: Volume, System: Metric, ToBase: 1, Aliases: []string{"mililitro", "mililitros", "milliliter", "milliliters", "millilitre", "millilitres", "cc"}},
+	{Code: "cl", Dimension: Volume, System: Metric, ToBase: 10, Aliases: []string{"centilitro", "centilitros", "centiliter", "centiliters"}},
+	{Code: "dl", Dimension: Volume, System: Metric, ToBase: 100, Aliases: []string{"decilitro", "decilitros", "deciliter", "deciliters"}},
+	{Code: "l", Dimension: Volume, System: Metric, ToBase: 1000, Aliases: []string{"lt", "lts", "litro", "litros", "liter", "liters", "litre", "litres"}},
+	{Code: "tsp", Dimension: Volume, System: Imperial, ToBase: 4.92892159375, Aliases: []string{"cdta", "cdita", "cucharadita", "cucharaditas", "teaspoon", "teaspoons"}},
+	{Code: "tbsp", Dimension: Volume, System: Imperial, ToBase: 14.78676478125, Aliases: []string{"cda", "cucharada", "cucharadas", "tablespoon", "tablespoons", "tbs", "tbl"}},
+	{Code: "fl_oz", Dimension: Volume, System: Imperial, ToBase: 29.5735295625, Aliases: []string{"fl oz", "floz", "fluid ounce", "fluid ounces", "onza liquida", "onzas liquidas"}},
+	{Code: "cup", Dimension: Volume, System: Imperial, ToBase: 236.5882365, Aliases: []string{"cups", "taza", "tazas"}},
+	{Code: "pint", Dimension: Volume, System: Imperial, ToBase: 473.176473, Aliases: []string{"pints", "pt", "pinta", "pintas"}},
+	{Code: "quart", Dimension: Volume, System: Imperial, ToBase: 946.352946, Aliases: []string{"quarts", "qt", "cuarto de galon"}},
+	{Code: "gallon", Dimension: Volume, System: Imperial, ToBase: 3785.411784, Aliases: []string{"gallons", "gal", "galon", "galones"}},
+
+	// Conteo
+	{Code: CodeUnit, Dimension: Count, ToBase: 1, Aliases: []string{"units", "u", "unidad", "unidades", "ud", "uds", "pieza", "piezas", "piece", "pieces", "pc", "pcs", "whole"}},
+	{Code: "clove", Dimension: Count, ToBase: 1, Aliases: []string{"cloves", "diente", "dientes"}},
+	{Code: "slice", Dimension: Count, ToBase: 1, Aliases: []string{"slices", "rebanada", "rebanadas", "feta", "fetas", "rodaja", "rodajas"}},
+	{Code: "can", Dimension: Count, ToBase: 1, Aliases: []string{"cans", "lata", "latas"}},
+	{Code: "package", Dimension: Count, ToBase: 1, Aliases: []string{"packages", "pkg", "paquete", "paquetes", "sobre", "sobres"}},
+	{Code: "bunch", Dimension: Count, ToBase: 1, Aliases: []string{"bunches", "atado", "atados", "manojo", "manojos"}},
+	{Code: "pinch", Dimension: Count, ToBase: 1, Aliases: []string{"pinches", "pizca", "pizcas"}},
+
+	// A gusto
+	{Code: CodeToTaste, Dimension: ToTaste, ToBase: 0, Aliases: []string{"to taste", "a gusto", "c/n", "cantidad necesaria", "al gusto"}},
+}
+
+var byName = func() map[string]Unit {
+	index := map[string]Unit{}
+	for _, unit := range catalog {
+		index[Normalize(unit.Code)] = unit
+		for _, alias := range unit.Aliases {
+			index[Normalize(alias)] = unit
+		}
+	}
+	return index
+}()
+
+// Lookup busca una unidad por su código canónico.
+func Lookup(code string) (Unit, bool) {
+	for _, unit := range catalog {
+		if unit.Code == code {
+			return unit, true
+		}
+	}
+	return Unit{}, false
+}
+
+// Find busca una unidad por código o por cualquiera de sus nombres en
+// español o inglés, sin distinguir mayúsculas, acentos ni punto final.
+func Find(name string) (Unit, bool) {
+	unit, ok := byName[Normalize(name)]
+	return unit, ok
+}
+
+// Codes devuelve los códigos del catálogo ordenados alfabéticamente.
+func Codes() []string {
+	codes := make([]string, 0, len(catalog))
+	for _, unit := range catalog {
+		codes = append(codes, unit.Code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Catalog devuelve una copia del catálogo completo.
+func Catalog() []Unit {
+	return append([]Unit{}, catalog...)
+}
+
+// Normalize pasa a minúsculas, quita acentos, el punto de las abreviaturas
+// y espacios sobrantes. Es la forma en que se comparan nombres de unidades.
+func Normalize(name string) string {
+	decomposed := norm.NFD.String(strings.ToLower(strings.TrimSpace(name)))
+	var builder strings.Builder
+	for _, r := range decomposed {
+		// Marcas diacríticas combinadas (tildes, diéresis)
+		if r >= 0x0300 && r <= 0x036f {
+			continue
+		}
+		builder.WriteRune(r)
+	}
+	return strings.Join(strings.Fields(strings.TrimSuffix(builder.String(), ".")), " ")
+}
@@ -151,13 +151,13 @@ const Home = () => {
         api.get('/recipes'),
         api.get('/recipes/top')
       ]);
-      setRecipes(res.data);
+      setRecipes(res.data?.items || []);
       setTopRecipes(topRes.data || []);
 
       const token = localStorage.getItem('token');
       if (token) {
-          const savedRes = await api.get('/saved-recipes');
-          const ids = new Set(savedRes.data.map(r => r.id));
+          const savedRes = await api.get('/saved-recipes?limit=100');
+          const ids = new Set((savedRes.data?.items || []).map(r => r.id));
           setSavedRecipeIds(ids);
       }
     } catch (err) { console.error("Error fetching data:", err); }
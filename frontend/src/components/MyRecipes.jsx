@@ -24,10 +24,10 @@ const MyRecipes = () => {
       try {
         const [createdRes, savedRes] = await Promise.all([
           api.get('/user/recipes'),
-          api.get('/saved-recipes')
+          api.get('/saved-recipes?limit=100')
         ]);
-        setCreatedRecipes(createdRes.data || []);
-        setSavedRecipes(savedRes.data || []);
+        setCreatedRecipes(createdRes.data?.items || []);
+        setSavedRecipes(savedRes.data?.items || []);
       } catch (err) {
         console.error("Error cargando recetas:", err);
       } finally {
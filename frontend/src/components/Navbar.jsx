@@ -41,8 +41,8 @@ const Navbar = () => {
     }
     const delayDebounceFn = setTimeout(async () => {
         try {
-            const res = await api.get(`/recipes/search?q=${searchTerm}`);
-            setSuggestions(res.data || []);
+            const res = await api.get(`/recipes/search?q=${encodeURIComponent(searchTerm)}&limit=8`);
+            setSuggestions(res.data?.items || []);
             setShowSuggestions(true);
         } catch (error) {
             console.error("Error fetching suggestions", error);
@@ -19,7 +19,7 @@ const RecipeComments = ({ recipeId }) => {
     const fetchComments = async () => {
         try {
             const res = await api.get(`/recipes/comments/${recipeId}`);
-            setComments(res.data);
+            setComments(res.data?.items || []);
         } catch (err) {
             console.error("Error cargando comentarios:", err);
         }
@@ -52,13 +52,13 @@ const RecipeDetail = () => {
 
         try {
             const commentsRes = await api.get(`/recipes/comments/${id}`);
-            setComments(commentsRes.data || []);
+            setComments(commentsRes.data?.items || []);
         } catch (e) { setComments([]); }
 
         const token = localStorage.getItem('token');
         if (token) {
-            const savedRes = await api.get('/saved-recipes');
-            const savedList = Array.isArray(savedRes.data) ? savedRes.data : [];
+            const savedRes = await api.get('/saved-recipes?limit=100');
+            const savedList = Array.isArray(savedRes.data?.items) ? savedRes.data.items : [];
             setIsSaved(savedList.some(r => String(r.id) === String(id)));
         }
       } catch (err) {
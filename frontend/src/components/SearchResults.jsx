@@ -38,7 +38,7 @@ const SearchResults = () => {
         if (initialDesc) params.append('desc', initialDesc);
 
         const res = await api.get(`/recipes/search?${params.toString()}`);
-        setRecipes(res.data || []);
+        setRecipes(res.data?.items || []);
       } catch (err) {
         console.error("Error buscando:", err);
       } finally {
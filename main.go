@@ -4,11 +4,15 @@ import (
 	"burned/backend/database"
 	"burned/backend/handlers"
 	"burned/backend/middlewares"
+	"burned/backend/migrations"
+	"burned/backend/nutrition"
 	"burned/backend/repositories"
+	"burned/backend/screening"
 	"burned/backend/services"
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -17,13 +21,26 @@ import (
 )
 
 var (
-	router             *gin.Engine
-	SavedRecipeHandler *handlers.SavedRecipeHandler
-	RecipeHandler      *handlers.RecipeHandler
-	UserHandler        *handlers.UserHandler
-	AuthHandler        *handlers.AuthHandler
-	RatingHandler      *handlers.RatingHandler
-	CommentHandler     *handlers.CommentHandler
+	router              *gin.Engine
+	SavedRecipeHandler  *handlers.SavedRecipeHandler
+	RecipeHandler       *handlers.RecipeHandler
+	UserHandler         *handlers.UserHandler
+	AuthHandler         *handlers.AuthHandler
+	RatingHandler       *handlers.RatingHandler
+	CommentHandler      *handlers.CommentHandler
+	IngredientHandler   *handlers.IngredientHandler
+	ImportHandler       *handlers.RecipeImportHandler
+	ExportHandler       *handlers.ExportHandler
+	RevisionHandler     *handlers.RevisionHandler
+	CollectionHandler   *handlers.CollectionHandler
+	MealPlanHandler     *handlers.MealPlanHandler
+	ScheduleHandler     *handlers.ScheduleHandler
+	ShoppingListHandler *handlers.ShoppingListHandler
+	PantryHandler       *handlers.PantryHandler
+	NutritionHandler    *handlers.NutritionHandler
+	SubstitutionHandler *handlers.SubstitutionHandler
+	ModerationHandler   *handlers.ModerationHandler
+	FollowHandler       *handlers.FollowHandler
 )
 
 func main() {
@@ -57,36 +74,196 @@ func dependencies() {
 	var db database.DB
 
 	var (
-		userRepo        repositories.UserRepositoryInterface
-		recipeRepo      repositories.RecipeRepositoryInterface
-		savedRecipeRepo repositories.SavedRecipeRepositoryInterface
-		ratingRepo      repositories.RatingRepositoryInterface
-		commentRepo     repositories.CommentRepositoryInterface
+		userRepo         repositories.UserRepositoryInterface
+		recipeRepo       repositories.RecipeRepositoryInterface
+		savedRecipeRepo  repositories.SavedRecipeRepositoryInterface
+		ratingRepo       repositories.RatingRepositoryInterface
+		commentRepo      repositories.CommentRepositoryInterface
+		revisionRepo     repositories.RecipeRevisionRepositoryInterface
+		collectionRepo   repositories.RecipeCollectionRepositoryInterface
+		mealPlanRepo     repositories.MealPlanRepositoryInterface
+		cookSessionRepo  repositories.CookSessionRepositoryInterface
+		shoppingListRepo repositories.ShoppingListRepositoryInterface
+		pantryRepo       repositories.PantryRepositoryInterface
+		nutritionRepo    repositories.NutritionRepositoryInterface
+		substitutionRepo repositories.SubstitutionRepositoryInterface
+		moderationRepo   repositories.ModerationRepositoryInterface
+		followRepo       repositories.FollowRepositoryInterface
+		feedRepo         repositories.FeedRepositoryInterface
 	)
 
 	var (
-		userService        services.UserServiceInterface
-		recipeService      services.RecipeServiceInterface
-		savedRecipeService services.SavedRecipeServiceInterface
-		ratingService      services.RatingServiceInterface
-		commentService     services.CommentServiceInterface
+		userService         services.UserServiceInterface
+		recipeService       services.RecipeServiceInterface
+		savedRecipeService  services.SavedRecipeServiceInterface
+		ratingService       services.RatingServiceInterface
+		commentService      services.CommentServiceInterface
+		deletionService     services.DeletionServiceInterface
+		ingredientService   services.IngredientServiceInterface
+		importService       services.RecipeImportServiceInterface
+		exportService       services.ExportServiceInterface
+		revisionService     services.RevisionServiceInterface
+		collectionService   services.CollectionServiceInterface
+		mealPlanService     services.MealPlanServiceInterface
+		scheduleService     services.ScheduleServiceInterface
+		shoppingListService services.ShoppingListServiceInterface
+		pantryService       services.PantryServiceInterface
+		nutritionService    services.NutritionServiceInterface
+		substitutionService services.SubstitutionServiceInterface
+		moderationService   services.ModerationServiceInterface
+		screeningService    services.ScreeningServiceInterface
+		feedService         services.FeedServiceInterface
+		followService       services.FollowServiceInterface
 	)
 
 	// Conexión a base de datos
 	db = database.NewMongoDB()
 
+	if err := migrations.Run(db); err != nil {
+		log.Fatal("❌ Error FATAL al aplicar migraciones: ", err)
+	}
+
 	// Repositorios
 	userRepo = repositories.NewUserRepository(db)
 	savedRecipeRepo = repositories.NewSavedRecipeRepository(db)
 	recipeRepo = repositories.NewRecipeRepository(db, savedRecipeRepo)
 	ratingRepo = repositories.NewRatingRepository(db)
 	commentRepo = repositories.NewCommentRepository(db)
+	revisionRepo = repositories.NewRecipeRevisionRepository(db)
+	collectionRepo = repositories.NewRecipeCollectionRepository(db)
+	mealPlanRepo = repositories.NewMealPlanRepository(db)
+	cookSessionRepo = repositories.NewCookSessionRepository(db)
+	shoppingListRepo = repositories.NewShoppingListRepository(db)
+	pantryRepo = repositories.NewPantryRepository(db)
+	nutritionRepo = repositories.NewNutritionRepository(db)
+	substitutionRepo = repositories.NewSubstitutionRepository(db)
+	moderationRepo = repositories.NewModerationRepository(db)
+	followRepo = repositories.NewFollowRepository(db)
+	feedRepo = repositories.NewFeedRepository(db)
+	if err := recipeRepo.EnsureSearchIndex(); err != nil {
+		log.Println("⚠️ Aviso: No se pudo crear el índice de búsqueda de recetas:", err)
+	}
+	if err := savedRecipeRepo.SyncSavedCounts(); err != nil {
+		log.Println("⚠️ Aviso: No se pudo sincronizar el contador de guardados:", err)
+	}
+	if err := recipeRepo.EnsureForkIndex(); err != nil {
+		log.Println("⚠️ Aviso: No se pudo crear el índice de versiones derivadas:", err)
+	}
+	if err := revisionRepo.EnsureIndexes(); err != nil {
+		log.Println("⚠️ Aviso: No se pudo crear el índice de revisiones:", err)
+	}
+	if err := collectionRepo.EnsureIndexes(); err != nil {
+		log.Println("⚠️ Aviso: No se pudo crear el índice de colecciones:", err)
+	}
+	if err := mealPlanRepo.EnsureIndexes(); err != nil {
+		log.Println("⚠️ Aviso: No se pudo crear el índice del plan de comidas:", err)
+	}
+	if err := cookSessionRepo.EnsureIndexes(); err != nil {
+		log.Println("⚠️ Aviso: No se pudo crear el índice de sesiones de cocina:", err)
+	}
+	if err := pantryRepo.EnsureIndexes(); err != nil {
+		log.Println("⚠️ Aviso: No se pudo crear el índice de la despensa:", err)
+	}
+	if err := recipeRepo.EnsurePantryIndex(); err != nil {
+		log.Println("⚠️ Aviso: No se pudo crear el índice de búsqueda por despensa:", err)
+	}
+	if err := nutritionRepo.EnsureIndexes(); err != nil {
+		log.Println("⚠️ Aviso: No se pudo crear el índice de ingredientes sin datos nutricionales:", err)
+	}
+	if err := substitutionRepo.EnsureIndexes(); err != nil {
+		log.Println("⚠️ Aviso: No se pudo crear el índice de reglas de reemplazo:", err)
+	}
+	if err := commentRepo.EnsureIndexes(); err != nil {
+		log.Println("⚠️ Aviso: No se pudo crear el índice de comentarios:", err)
+	}
+	if err := moderationRepo.EnsureIndexes(); err != nil {
+		log.Println("⚠️ Aviso: No se pudo crear el índice de moderación:", err)
+	}
+	if err := followRepo.EnsureIndexes(); err != nil {
+		log.Println("⚠️ Aviso: No se pudo crear el índice de seguidores:", err)
+	}
+	if err := feedRepo.EnsureIndexes(); err != nil {
+		log.Println("⚠️ Aviso: No se pudo crear el índice del feed:", err)
+	}
+
+	// Base nutricional: la embebida, o un CSV propio en NUTRITION_DB_PATH
+	foods := nutrition.Default()
+	if path := os.Getenv("NUTRITION_DB_PATH"); path != "" {
+		loaded, err := nutrition.LoadFile(path)
+		if err != nil {
+			log.Println("⚠️ Aviso: No se pudo leer la base nutricional, se usa la incluida:", err)
+		} else {
+			foods = loaded
+		}
+	}
+	// Moderación automática: la lista de palabras incluida, o una propia en MODERATION_WORDLIST_PATH
+	words := screening.DefaultWords()
+	if path := os.Getenv("MODERATION_WORDLIST_PATH"); path != "" {
+		loaded, err := screening.LoadWordsFile(path)
+		if err != nil {
+			log.Println("⚠️ Aviso: No se pudo leer la lista de palabras, se usa la incluida:", err)
+		} else {
+			words = loaded
+		}
+	}
 	// Servicios
-	userService = services.NewUserService(userRepo)
-	recipeService = services.NewRecipeService(recipeRepo, userRepo)
-	savedRecipeService = services.NewSavedRecipeService(savedRecipeRepo, recipeRepo)
+	deletion := services.NewDeletionService(db, userRepo, recipeRepo, commentRepo, ratingRepo, savedRecipeRepo)
+	deletion.AddRecipeCascade(services.RecipeCascade{Name: "RecipeRevision", Count: revisionRepo.CountRevisionsByRecipes, Delete: revisionRepo.DeleteRevisionsByRecipes})
+	deletion.AddRecipeCascade(services.RecipeCascade{Name: "RecipeCollectionItems", Count: collectionRepo.CountItemsByRecipes, Delete: collectionRepo.RemoveItemsByRecipes})
+	deletion.AddRecipeCascade(services.RecipeCascade{Name: "MealPlan", Count: mealPlanRepo.CountEntriesByRecipes, Delete: mealPlanRepo.DeleteEntriesByRecipes})
+	deletion.AddUserCascade(services.UserCascade{Name: "MealPlan", Count: mealPlanRepo.CountEntriesByUser, Apply: mealPlanRepo.DeleteEntriesByUser})
+	deletion.AddRecipeCascade(services.RecipeCascade{Name: "CommentEdit", Count: commentRepo.CountCommentEditsByRecipes, Delete: commentRepo.DeleteCommentEditsByRecipes})
+	deletion.AddRecipeCascade(services.RecipeCascade{Name: "CookSession", Count: cookSessionRepo.CountSessionsByRecipes, Delete: cookSessionRepo.DeleteSessionsByRecipes})
+	deletion.AddUserCascade(services.UserCascade{Name: "CookSession", Count: cookSessionRepo.CountSessionsByUser, Apply: cookSessionRepo.DeleteSessionsByUser})
+	deletion.AddUserCascade(services.UserCascade{Name: "ShoppingList", Count: shoppingListRepo.CountListByUser, Apply: shoppingListRepo.DeleteListByUser})
+	deletion.AddUserCascade(services.UserCascade{Name: "Pantry", Count: pantryRepo.CountItemsByUser, Apply: pantryRepo.DeleteItemsByUser})
+	deletion.AddUserCascade(services.UserCascade{Name: "RecipeCollection", Count: collectionRepo.CountCollectionsByUser, Apply: collectionRepo.DeleteCollectionsByUser})
+	deletion.AddRecipeCascade(services.RecipeCascade{Name: "Report", Count: moderationRepo.CountReportsByRecipes, Delete: moderationRepo.DeleteReportsByRecipes})
+	deletion.AddUserCascade(services.UserCascade{Name: "Report", Count: moderationRepo.CountReportsByUser, Apply: moderationRepo.DeleteReportsByUser})
+	deletion.AddUserCascade(services.UserCascade{Name: "UserWarning", Count: moderationRepo.CountWarningsByUser, Apply: moderationRepo.DeleteWarningsByUser})
+	deletion.AddRecipeCascade(services.RecipeCascade{Name: "FeedItem", Count: feedRepo.CountItemsByRecipes, Delete: feedRepo.DeleteItemsByRecipes})
+	deletion.AddUserCascade(services.UserCascade{Name: "FeedItem", Count: feedRepo.CountItemsByUser, Apply: feedRepo.DeleteItemsByUser})
+	deletion.AddUserCascade(services.UserCascade{Name: "Follow", Count: followRepo.CountFollowsByUser, Apply: followRepo.DeleteFollowsByUser})
+	deletion.AddUserCascade(services.UserCascade{Name: "RecipeLineage", Anonymize: true, Count: recipeRepo.CountLineageByUser, Apply: recipeRepo.AnonymizeLineageByUser})
+	deletionService = deletion
+	nutritionService = services.NewNutritionService(nutritionRepo, recipeRepo, foods)
+	if err := nutritionService.LoadAliases(); err != nil {
+		log.Println("⚠️ Aviso: No se pudieron cargar los alias nutricionales:", err)
+	}
+	userService = services.NewUserService(userRepo, deletionService)
+	screeningService = services.NewScreeningService(screening.NewPipeline(
+		screening.NewWordRule(words),
+		screening.NewLinkRule(),
+		screening.NewSpamRule(),
+		screening.NewRepeatedRule(moderationRepo),
+	), moderationRepo)
+	feedService = services.NewFeedService(feedRepo, followRepo, recipeRepo, commentRepo, userRepo)
+	followService = services.NewFollowService(followRepo, userRepo, feedService)
+	recipeService = services.NewRecipeService(recipeRepo, userRepo, revisionRepo, savedRecipeRepo, deletionService, nutritionService, screeningService, feedService)
+	savedRecipeService = services.NewSavedRecipeService(savedRecipeRepo, recipeRepo, collectionRepo)
 	ratingService = services.NewRatingService(ratingRepo, recipeRepo)
-	commentService = services.NewCommentService(commentRepo, userRepo, recipeRepo)
+	commentService = services.NewCommentService(commentRepo, userRepo, recipeRepo, screeningService, feedService)
+	ingredientService = services.NewIngredientService()
+	importService = services.NewRecipeImportService()
+	exportService = services.NewExportService(recipeRepo, userRepo)
+	revisionService = services.NewRevisionService(revisionRepo, recipeRepo, userRepo, nutritionService)
+	collectionService = services.NewCollectionService(collectionRepo, recipeRepo, savedRecipeRepo, userRepo)
+	mealPlanService = services.NewMealPlanService(mealPlanRepo, recipeRepo)
+	scheduleService = services.NewScheduleService(cookSessionRepo, recipeRepo)
+	shoppingListService = services.NewShoppingListService(shoppingListRepo, recipeRepo, mealPlanRepo)
+	pantryService = services.NewPantryService(pantryRepo, recipeRepo)
+	substitutionService = services.NewSubstitutionService(substitutionRepo, recipeRepo, foods)
+	// Denuncias necesarias para ocultar solo un contenido; 0 lo desactiva
+	autoHideReports := services.DefaultAutoHideReports
+	if value := os.Getenv("MODERATION_AUTO_HIDE_REPORTS"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			log.Println("⚠️ Aviso: MODERATION_AUTO_HIDE_REPORTS inválido, se usa", autoHideReports)
+		} else {
+			autoHideReports = parsed
+		}
+	}
+	moderationService = services.NewModerationService(moderationRepo, commentRepo, recipeRepo, userRepo, commentService, recipeService, autoHideReports)
 	// Handlers
 	AuthHandler = handlers.NewAuthHandler(userService)
 	RecipeHandler = handlers.NewRecipeHandler(recipeService)
@@ -94,6 +271,19 @@ func dependencies() {
 	UserHandler = handlers.NewUserHandler(userService)
 	RatingHandler = handlers.NewRatingHandler(ratingService)
 	CommentHandler = handlers.NewCommentHandler(commentService)
+	IngredientHandler = handlers.NewIngredientHandler(ingredientService)
+	ImportHandler = handlers.NewRecipeImportHandler(importService)
+	ExportHandler = handlers.NewExportHandler(exportService)
+	RevisionHandler = handlers.NewRevisionHandler(revisionService)
+	CollectionHandler = handlers.NewCollectionHandler(collectionService)
+	MealPlanHandler = handlers.NewMealPlanHandler(mealPlanService)
+	ScheduleHandler = handlers.NewScheduleHandler(scheduleService)
+	ShoppingListHandler = handlers.NewShoppingListHandler(shoppingListService)
+	PantryHandler = handlers.NewPantryHandler(pantryService)
+	NutritionHandler = handlers.NewNutritionHandler(nutritionService)
+	SubstitutionHandler = handlers.NewSubstitutionHandler(substitutionService)
+	ModerationHandler = handlers.NewModerationHandler(moderationService)
+	FollowHandler = handlers.NewFollowHandler(followService, feedService)
 }
 
 func mappingRoutes() {
@@ -104,6 +294,16 @@ func mappingRoutes() {
 	router.POST("/get-rate/:id", RatingHandler.GetRatingByRecipe)
 	router.GET("/auth/google/login", AuthHandler.GoogleLogin)
 	router.GET("/auth/google/callback", AuthHandler.GoogleCallback)
+	router.POST("/ingredients/parse", IngredientHandler.ParseIngredients)
+
+	router.GET("/collections/shared/:token", middlewares.OptionalAuthMiddleware(), CollectionHandler.GetSharedCollection)
+	router.GET("/collections/:id", middlewares.OptionalAuthMiddleware(), CollectionHandler.GetCollection)
+	router.GET("/users/:id/collections", middlewares.OptionalAuthMiddleware(), CollectionHandler.GetCollectionsByUser)
+	router.GET("/users/:id", middlewares.OptionalAuthMiddleware(), FollowHandler.GetProfile)
+	router.GET("/users/:id/followers", FollowHandler.GetFollowers)
+	router.GET("/users/:id/following", FollowHandler.GetFollowing)
+	router.GET("/calendar/:file", ScheduleHandler.Feed)
+	router.GET("/comments/:id/replies", CommentHandler.GetReplies)
 
 	recipes := router.Group("/recipes")
 	{
@@ -112,7 +312,13 @@ func mappingRoutes() {
 		recipes.GET("/top", RecipeHandler.GetTopRecipes)
 		recipes.GET("/count/:id", SavedRecipeHandler.GetSavedCountByRecipe)
 		recipes.GET("", RecipeHandler.GetAll)
-		recipes.GET("/:id", RecipeHandler.GetRecipeById)
+		recipes.GET("/:id", middlewares.OptionalAuthMiddleware(), RecipeHandler.GetRecipeById)
+		recipes.GET("/:id/scaled", middlewares.OptionalAuthMiddleware(), RecipeHandler.GetScaledRecipe)
+		recipes.GET("/:id/forks", RecipeHandler.GetForks)
+		recipes.GET("/:id/schedule.ics", middlewares.OptionalAuthMiddleware(), ScheduleHandler.RecipeSchedule)
+		recipes.GET("/:id/timeline", middlewares.OptionalAuthMiddleware(), ScheduleHandler.RecipeTimeline)
+		recipes.GET("/:id/export", middlewares.OptionalAuthMiddleware(), ExportHandler.ExportRecipe)
+		recipes.GET("/:id/substitutions", middlewares.OptionalAuthMiddleware(), SubstitutionHandler.RecipeSubstitutions)
 		recipes.GET("/comments/:recipeId", CommentHandler.GetCommentsByRecipe)
 	}
 
@@ -127,17 +333,92 @@ func mappingRoutes() {
 		priv.GET("/user/me", UserHandler.GetUserById)
 
 		priv.POST("/recipes", RecipeHandler.CreateRecipe)
+		priv.POST("/recipes/import", ImportHandler.PreviewImport)
 		priv.PUT("/recipes/:id", RecipeHandler.UpdateRecipe)
+		priv.POST("/recipes/:id/fork", RecipeHandler.ForkRecipe)
 		priv.DELETE("/recipes/:id", RecipeHandler.DeleteRecipe)
+		priv.GET("/recipes/:id/revisions", RevisionHandler.GetRevisions)
+		priv.GET("/recipes/:id/revisions/diff", RevisionHandler.DiffRevisions)
+		priv.GET("/recipes/:id/revisions/:number", RevisionHandler.GetRevision)
+		priv.POST("/recipes/:id/revisions/:number/restore", RevisionHandler.RestoreRevision)
 		priv.GET("/user/recipes", RecipeHandler.GetRecipesByUser)
+		priv.GET("/user/recipes/export", ExportHandler.ExportRecipesByUser)
 
 		priv.POST("/saved-recipes", SavedRecipeHandler.SavedRecipe)
 		priv.DELETE("/saved-recipes/:id", SavedRecipeHandler.UnsavedRecipe)
 		priv.GET("/saved-recipes", SavedRecipeHandler.GetRecipesSavedByUser)
+		priv.GET("/saved-recipes/:id/notes", SavedRecipeHandler.GetNotes)
+		priv.PUT("/saved-recipes/:id/notes", SavedRecipeHandler.UpdateNotes)
+		priv.DELETE("/saved-recipes/:id/notes", SavedRecipeHandler.DeleteNotes)
 		priv.POST("/rate-recipe/:id", RatingHandler.RateRecipe)
 
+		priv.GET("/collections", CollectionHandler.GetMyCollections)
+		priv.POST("/collections", CollectionHandler.CreateCollection)
+		priv.PUT("/collections/:id", CollectionHandler.UpdateCollection)
+		priv.DELETE("/collections/:id", CollectionHandler.DeleteCollection)
+		priv.POST("/collections/:id/items", CollectionHandler.AddRecipe)
+		priv.DELETE("/collections/:id/items/:recipeId", CollectionHandler.RemoveRecipe)
+		priv.PUT("/collections/:id/items/order", CollectionHandler.ReorderRecipes)
+		priv.POST("/collections/:id/share", CollectionHandler.RotateShareToken)
+
+		priv.GET("/meal-plan", MealPlanHandler.GetPlan)
+		priv.POST("/meal-plan", MealPlanHandler.CreateEntry)
+		priv.PUT("/meal-plan/:id", MealPlanHandler.UpdateEntry)
+		priv.DELETE("/meal-plan/:id", MealPlanHandler.DeleteEntry)
+		priv.POST("/meal-plan/copy-week", MealPlanHandler.CopyWeek)
+		priv.POST("/meal-plan/repeat-week", MealPlanHandler.RepeatWeek)
+
+		priv.GET("/shopping-list", ShoppingListHandler.GetList)
+		priv.DELETE("/shopping-list", ShoppingListHandler.ClearList)
+		priv.POST("/shopping-list/generate", ShoppingListHandler.Generate)
+		priv.GET("/shopping-list/export", ShoppingListHandler.Export)
+		priv.POST("/shopping-list/items", ShoppingListHandler.AddItem)
+		priv.PUT("/shopping-list/items/:id", ShoppingListHandler.UpdateItem)
+		priv.DELETE("/shopping-list/items/:id", ShoppingListHandler.DeleteItem)
+		priv.DELETE("/shopping-list/checked", ShoppingListHandler.ClearChecked)
+
+		priv.GET("/pantry", PantryHandler.GetItems)
+		priv.POST("/pantry", PantryHandler.CreateItem)
+		priv.PUT("/pantry/:id", PantryHandler.UpdateItem)
+		priv.DELETE("/pantry/:id", PantryHandler.DeleteItem)
+		priv.GET("/pantry/recipes", PantryHandler.SearchRecipes)
+
+		priv.GET("/cook-sessions", ScheduleHandler.GetSessions)
+		priv.POST("/cook-sessions", ScheduleHandler.CreateSession)
+		priv.DELETE("/cook-sessions/:id", ScheduleHandler.DeleteSession)
+		priv.POST("/user/calendar-feed", ScheduleHandler.CreateFeed)
+		priv.DELETE("/user/calendar-feed", ScheduleHandler.RevokeFeed)
+
 		priv.DELETE("/comments/:id", CommentHandler.DeleteComment)
+		priv.PUT("/comments/:id", CommentHandler.UpdateComment)
+		priv.POST("/comments/:id/report", ModerationHandler.ReportComment)
+		priv.POST("/recipes/:id/report", ModerationHandler.ReportRecipe)
+		priv.GET("/user/warnings", ModerationHandler.GetWarnings)
+
+		priv.POST("/users/:id/follow", FollowHandler.Follow)
+		priv.DELETE("/users/:id/follow", FollowHandler.Unfollow)
+		priv.GET("/feed", FollowHandler.GetFeed)
 		priv.GET("/comments/:id", CommentHandler.GetCommentById)
 		priv.POST("/comments", CommentHandler.CreateComment)
 	}
+
+	admin := priv.Group("/admin", middlewares.CheckAdmin())
+	{
+		admin.GET("/nutrition/unmatched", NutritionHandler.GetUnmatched)
+		admin.DELETE("/nutrition/unmatched/:id", NutritionHandler.DismissUnmatched)
+		admin.GET("/nutrition/foods", NutritionHandler.GetFoods)
+		admin.POST("/nutrition/aliases", NutritionHandler.AddAlias)
+
+		admin.GET("/substitutions", SubstitutionHandler.GetRules)
+		admin.POST("/substitutions", SubstitutionHandler.CreateRule)
+		admin.PUT("/substitutions/:id", SubstitutionHandler.UpdateRule)
+		admin.DELETE("/substitutions/:id", SubstitutionHandler.DeleteRule)
+
+		admin.GET("/comments/:id/history", CommentHandler.GetCommentHistory)
+
+		admin.GET("/moderation/cases", ModerationHandler.GetCases)
+		admin.GET("/moderation/cases/:id", ModerationHandler.GetCase)
+		admin.POST("/moderation/cases/:id/decision", ModerationHandler.Decide)
+		admin.GET("/moderation/decisions", ModerationHandler.GetDecisions)
+	}
 }